@@ -30,10 +30,11 @@ type App struct {
 	cfgStore       *config.Store
 	pollerCtx      context.Context
 	pollerStop     context.CancelFunc
-	currentSession *plex.MusicSession // Track current playback for page refresh restoration
+	currentSession *plex.MediaSession // Track current playback for page refresh restoration
 
-	// Session polling
-	poller *plex.Poller
+	// Session polling: one poller per active server, merged by an arbiter
+	pollers []*serverPoller
+	arbiter *plex.SessionArbiter
 
 	// Discord integration (production type, accessed via DiscordPresence interface)
 	discord DiscordPresence
@@ -46,6 +47,13 @@ type App struct {
 	// it, and a late resolve only re-issues presence if its generation is current.
 	artworkGen atomic.Uint64
 
+	// presenceSentAt is when presence was last sent, so lyric line updates
+	// stay within Discord's rate limit. Guarded by discordMu.
+	presenceSentAt time.Time
+
+	// lyrics follows the synced lyrics of the playing track (see app_lyrics.go)
+	lyrics *lyricsFollow
+
 	// Plex client factory for constructing clients on demand (per-server).
 	// Using a factory instead of a singleton reflects that the server URL/token
 	// can change at runtime and enables tests to inject fakes.
@@ -54,6 +62,17 @@ type App struct {
 	// Token store abstracts credential persistence (OS keychain in production)
 	tokens TokenStore
 
+	// scanLAN discovers servers on the local network (GDM in production).
+	// Used to find a server again by machine identifier after its IP changed.
+	scanLAN func(timeout time.Duration) ([]plex.Server, error)
+
+	// discoverPlayers lists the account's Companion players (plex.tv in
+	// production). Used to follow playback on shared servers.
+	discoverPlayers func(ctx context.Context, token string) ([]plex.CompanionPlayer, error)
+
+	// relocating holds the machine identifiers with a re-scan in flight
+	relocating map[string]bool
+
 	// Platform integration
 	autostart *platform.AutoStartManager
 	tray      *platform.TrayManager
@@ -94,6 +113,8 @@ type App struct {
 	discordMu  sync.Mutex
 	plexAuthMu sync.Mutex
 	pauseMu    sync.Mutex // Protect presencePaused and pauseTimer
+	relocateMu sync.Mutex // Protect relocating
+	lyricsMu   sync.Mutex // Protect lyrics
 }
 
 // saveConfig persists the current in-memory config via the ConfigStore.
@@ -116,15 +137,21 @@ func (a *App) saveConfig() error {
 // For tests, construct an App directly with injected fakes for bus,
 // plexFactory, tokens, and discord.
 func NewApp() *App {
-	return &App{
-		discord:      discord.NewPresenceManager(),
-		plexFactory:  newPlexClientFactory(),
-		tokens:       newKeychainTokenStore(),
-		autostart:    platform.NewAutoStartManager(),
-		plexRetry:    retry.NewManager("Plex"),
-		discordRetry: retry.NewManager("Discord"),
-		artwork:      artwork.NewResolver(artwork.WithUserAgent("PlexCord/" + version.Version)),
+	presence := discord.NewPresenceManager()
+	a := &App{
+		discord:         presence,
+		plexFactory:     newPlexClientFactory(),
+		tokens:          newKeychainTokenStore(),
+		scanLAN:         plex.DiscoverServers,
+		discoverPlayers: plex.DiscoverPlayers,
+		autostart:       platform.NewAutoStartManager(),
+		plexRetry:       retry.NewManager("Plex"),
+		discordRetry:    retry.NewManager("Discord"),
+		artwork:         artwork.NewResolver(artwork.WithUserAgent("PlexCord/" + version.Version)),
 	}
+	presence.SetConnectionLostHandler(a.onDiscordConnectionLost)
+	presence.SetTargetsChangedHandler(a.onDiscordTargetsChanged)
+	return a
 }
 
 // startup is called at application startup
@@ -148,6 +175,10 @@ func (a *App) startup(ctx context.Context) {
 	a.cfgStore = config.NewStore(cfg, config.Save)
 	log.Printf("Configuration loaded successfully")
 
+	// Show presence on the chosen Discord client(s) when several run
+	a.discord.SetInstance(discordInstanceSelector(cfg.DiscordInstance))
+	a.discord.SetBroadcast(cfg.DiscordBroadcast)
+
 	// Initialize listening history store
 	configDir := config.GetConfigDir()
 	a.history = history.NewStore(configDir, 200)
@@ -156,8 +187,10 @@ func (a *App) startup(ctx context.Context) {
 	// window (or quitting) once the app is running in the background, so it
 	// runs regardless of the "Minimize to tray" setting.
 	a.tray = platform.NewTrayManager(platform.TrayCallbacks{
-		OnShow: a.ShowWindow,
-		OnQuit: a.QuitApp,
+		OnShow:       a.ShowWindow,
+		OnQuit:       a.QuitApp,
+		OnRate:       a.rateFromTray,
+		OnMarkPlayed: a.markPlayedFromTray,
 	}, a.trayIconPNG, a.trayIconICO)
 	a.tray.Start()
 
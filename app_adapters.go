@@ -17,6 +17,18 @@ func (keychainTokenStore) Get() (string, error)   { return keychain.GetToken() }
 func (keychainTokenStore) Set(token string) error { return keychain.SetToken(token) }
 func (keychainTokenStore) Delete() error          { return keychain.DeleteToken() }
 
+func (keychainTokenStore) GetServerToken(machineID string) (string, error) {
+	return keychain.GetServerToken(machineID)
+}
+
+func (keychainTokenStore) SetServerToken(machineID, token string) error {
+	return keychain.SetServerToken(machineID, token)
+}
+
+func (keychainTokenStore) DeleteServerToken(machineID string) error {
+	return keychain.DeleteServerToken(machineID)
+}
+
 // newKeychainTokenStore returns the default OS-keychain-backed TokenStore.
 func newKeychainTokenStore() TokenStore {
 	return keychainTokenStore{}
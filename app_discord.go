@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"log"
+	"strconv"
 	"time"
 
+	"plexcord/internal/config"
 	"plexcord/internal/discord"
 	"plexcord/internal/errors"
 	"plexcord/internal/events"
@@ -58,6 +60,7 @@ func (a *App) ConnectDiscord(clientID string) error {
 	a.bus.Emit(events.DiscordConnected, discord.ConnectionEvent{
 		Connected: true,
 		ClientID:  a.discord.GetClientID(),
+		Targets:   a.discord.Targets(),
 	})
 
 	log.Printf("Discord connected successfully")
@@ -88,6 +91,26 @@ func (a *App) DisconnectDiscord() error {
 	return nil
 }
 
+// onDiscordConnectionLost reports a connection Discord dropped (it quit or
+// restarted) as soon as it happens, and retries until it is back.
+func (a *App) onDiscordConnectionLost(err error) {
+	log.Printf("ERROR: Discord connection lost: %v", err)
+	a.bus.Emit(events.DiscordDisconnected, discord.ConnectionEvent{
+		Connected: false,
+		Error: &discord.Error{
+			Code:    errors.GetCode(err),
+			Message: err.Error(),
+		},
+	})
+	a.startDiscordRetry(err)
+}
+
+// onDiscordTargetsChanged reports a Discord client that dropped or failed
+// an update while presence still reaches others (broadcast mode).
+func (a *App) onDiscordTargetsChanged(targets []discord.TargetStatus) {
+	a.bus.Emit(events.DiscordTargetsUpdated, targets)
+}
+
 // IsDiscordConnected returns whether a Discord connection is active.
 func (a *App) IsDiscordConnected() bool {
 	a.discordMu.Lock()
@@ -135,6 +158,96 @@ func (a *App) SaveDiscordClientID(clientID string) error {
 	return nil
 }
 
+// ListDiscordInstances returns the running Discord clients presence can go
+// to, each with its build and logged-in account, so the user can choose one
+// when several run.
+func (a *App) ListDiscordInstances() []discord.Instance {
+	return discord.ListInstances(a.GetDiscordClientID())
+}
+
+// GetDiscordInstance returns the Discord client presence goes to.
+func (a *App) GetDiscordInstance() config.DiscordInstance {
+	return a.config.DiscordInstance
+}
+
+// SetDiscordInstance chooses the Discord client presence goes to, by build
+// ("stable", "ptb", "canary", "development") and/or Discord user ID. Pass
+// the zero value to use the first client found. An open connection is moved
+// over right away.
+func (a *App) SetDiscordInstance(inst config.DiscordInstance) error {
+	switch inst.Release {
+	case "", discord.ReleaseStable, discord.ReleasePTB, discord.ReleaseCanary, discord.ReleaseDevelopment:
+	default:
+		return errors.New(errors.INVALID_INPUT, "unknown Discord release: "+inst.Release)
+	}
+
+	a.config.DiscordInstance = inst
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save Discord instance: %v", err)
+		return err
+	}
+
+	log.Printf("Discord instance set: release=%q, user=%q", inst.Release, inst.UserID)
+	return a.reconnectDiscordWith(func() {
+		a.discord.SetInstance(discordInstanceSelector(inst))
+	})
+}
+
+// GetDiscordBroadcast returns whether presence goes to every Discord client
+// found.
+func (a *App) GetDiscordBroadcast() bool {
+	return a.config.DiscordBroadcast
+}
+
+// SetDiscordBroadcast turns broadcast mode on or off. When on, presence goes
+// to every Discord client found, such as the desktop app and an arRPC bridge
+// (Vesktop) side by side, instead of the chosen instance. An open connection
+// is moved over right away.
+func (a *App) SetDiscordBroadcast(enabled bool) error {
+	a.config.DiscordBroadcast = enabled
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save Discord broadcast: %v", err)
+		return err
+	}
+
+	log.Printf("Discord broadcast set: %v", enabled)
+	return a.reconnectDiscordWith(func() {
+		a.discord.SetBroadcast(enabled)
+	})
+}
+
+// GetDiscordTargets returns the status of each Discord client presence goes
+// to: one outside broadcast mode, every client found in it.
+func (a *App) GetDiscordTargets() []discord.TargetStatus {
+	a.discordMu.Lock()
+	defer a.discordMu.Unlock()
+	return a.discord.Targets()
+}
+
+// reconnectDiscordWith applies a connection setting, then reconnects if
+// Discord was connected so it takes effect right away.
+func (a *App) reconnectDiscordWith(apply func()) error {
+	a.discordMu.Lock()
+	apply()
+	reconnect := a.discord.IsConnected()
+	if reconnect {
+		if err := a.discord.Disconnect(); err != nil {
+			log.Printf("Warning: Failed to disconnect from Discord: %v", err)
+		}
+	}
+	a.discordMu.Unlock()
+
+	if reconnect {
+		return a.ConnectDiscord("")
+	}
+	return nil
+}
+
+// discordInstanceSelector converts the configured Discord instance.
+func discordInstanceSelector(inst config.DiscordInstance) discord.InstanceSelector {
+	return discord.InstanceSelector{Release: inst.Release, UserID: inst.UserID}
+}
+
 // UpdateDiscordPresence updates the Discord Rich Presence with current playback info.
 // This is called internally when playback state changes.
 func (a *App) UpdateDiscordPresence(track, artist, album, state string, duration, position int64) error {
@@ -145,7 +258,20 @@ func (a *App) UpdateDiscordPresence(track, artist, album, state string, duration
 		return errors.New(errors.DISCORD_CONN_FAILED, "not connected to Discord")
 	}
 
-	return a.discord.UpdatePresenceFromPlayback(track, artist, album, state, duration, position, "", "", a.config.PresenceDetailsFormat, a.config.PresenceStateFormat, a.config.PresenceActivityStyle, a.config.PresenceStatusDisplay)
+	format := a.config.PresenceFormatFor(discord.MediaTypeMusic)
+	return a.discord.UpdatePresenceFromMedia(&discord.PresenceData{
+		MediaType:     discord.MediaTypeMusic,
+		Track:         track,
+		Artist:        artist,
+		Album:         album,
+		State:         state,
+		Duration:      duration,
+		Position:      position,
+		DetailsFormat: format.Details,
+		StateFormat:   format.State,
+		ActivityStyle: a.config.PresenceActivityStyle,
+		StatusDisplay: a.config.PresenceStatusDisplay,
+	})
 }
 
 // ClearDiscordPresence removes the Discord Rich Presence.
@@ -192,10 +318,10 @@ func (a *App) TestDiscordPresence() error {
 	return nil
 }
 
-// updateDiscordFromSession updates Discord Rich Presence with music session info.
+// updateDiscordFromSession updates Discord Rich Presence with media session info.
 // This is called automatically when playback is detected by the poller.
 // If Discord is not connected, attempts to reconnect automatically (Story 3-8).
-func (a *App) updateDiscordFromSession(session *plex.MusicSession) {
+func (a *App) updateDiscordFromSession(session *plex.MediaSession) {
 	a.discordMu.Lock()
 	defer a.discordMu.Unlock()
 
@@ -224,7 +350,7 @@ func (a *App) updateDiscordFromSession(session *plex.MusicSession) {
 
 	// If we have no cover yet, resolve one off the presence path and re-issue
 	// when it lands (dropped if the session has since changed).
-	if artURL == "" && a.artwork != nil && a.config.ArtworkLookupEnabled() {
+	if artURL == "" && a.artworkLookupEnabled(session) {
 		go a.resolveArtworkAsync(session, gen)
 	}
 }
@@ -232,8 +358,8 @@ func (a *App) updateDiscordFromSession(session *plex.MusicSession) {
 // cachedSessionArtwork returns a public artwork URL for the session if one is
 // already cached (no network), or "" to use the Plex logo fallback. It never
 // returns the tokened Plex ThumbURL.
-func (a *App) cachedSessionArtwork(session *plex.MusicSession) string {
-	if a.artwork == nil || !a.config.ArtworkLookupEnabled() {
+func (a *App) cachedSessionArtwork(session *plex.MediaSession) string {
+	if !a.artworkLookupEnabled(session) {
 		return ""
 	}
 	if url, ok := a.artwork.Cached(session.Artist, session.Album); ok {
@@ -242,29 +368,96 @@ func (a *App) cachedSessionArtwork(session *plex.MusicSession) string {
 	return ""
 }
 
+// artworkLookupEnabled reports whether a public cover can be looked up for
+// the session. The lookup services (iTunes / MusicBrainz) only know albums,
+// so other media types always use the Plex logo.
+func (a *App) artworkLookupEnabled(session *plex.MediaSession) bool {
+	return a.artwork != nil && a.config.ArtworkLookupEnabled() && session.MediaType == plex.MediaTypeMusic
+}
+
 // sendPresenceLocked issues a presence update for the session with the given
 // public artwork URL. The caller must hold discordMu.
-func (a *App) sendPresenceLocked(session *plex.MusicSession, artURL string) error {
-	return a.discord.UpdatePresenceFromPlayback(
-		session.Track,
-		session.Artist,
-		session.Album,
-		session.State,
-		session.Duration,
-		session.ViewOffset,
-		artURL,
-		session.PlayerName,
-		a.config.PresenceDetailsFormat,
-		a.config.PresenceStateFormat,
-		a.config.PresenceActivityStyle,
-		a.config.PresenceStatusDisplay,
-	)
+func (a *App) sendPresenceLocked(session *plex.MediaSession, artURL string) error {
+	if err := a.discord.UpdatePresenceFromMedia(a.presenceDataFor(session, artURL)); err != nil {
+		return err
+	}
+	a.presenceSentAt = time.Now()
+	return nil
+}
+
+// presenceDataFor maps a session to presence data, with the format strings
+// configured for its media type. The title goes in Track, which every
+// builder uses as its primary line.
+func (a *App) presenceDataFor(session *plex.MediaSession, artURL string) *discord.PresenceData {
+	format := a.config.PresenceFormatFor(session.MediaType)
+	data := &discord.PresenceData{
+		MediaType:     session.MediaType,
+		Track:         session.Title,
+		Artist:        session.Artist,
+		Album:         session.Album,
+		Player:        session.PlayerName,
+		ShowTitle:     session.ShowTitle,
+		Season:        session.Season,
+		Episode:       session.Episode,
+		ItemCount:     session.PhotoCount,
+		ArtworkURL:    artURL,
+		State:         session.State,
+		Duration:      session.Duration,
+		Position:      session.ViewOffset,
+		ItemKey:       session.SessionKey + ":" + session.RatingKey,
+		ObservedAt:    session.ObservedAt,
+		Seeked:        session.Seeked,
+		DetailsFormat: format.Details,
+		StateFormat:   format.State,
+		ActivityStyle: a.config.PresenceActivityStyle,
+		StatusDisplay: a.config.PresenceStatusDisplay,
+		Codec:         session.Quality.Codec,
+		Bitrate:       session.Quality.Bitrate,
+		BitDepth:      session.Quality.BitDepth,
+		SampleRate:    session.Quality.SampleRate,
+		Decision:      session.Quality.Decision,
+		Resolution:    session.Quality.Resolution,
+		TrackNumber:   session.TrackNumber,
+		TrackCount:    session.TrackCount,
+		Rating:        session.UserRating,
+	}
+	if session.Year > 0 {
+		data.Year = strconv.Itoa(session.Year)
+	}
+	if buttons := a.config.PresenceButtons[session.MediaType]; len(buttons) > 0 {
+		data.PlexURL = session.PlexWebURL()
+		data.WatchURL = session.WatchURL()
+		data.MusicBrainzURL = session.MusicBrainzURL()
+		data.IMDbURL = session.IMDbURL()
+		for _, b := range buttons {
+			data.Buttons = append(data.Buttons, discord.ButtonTemplate{Label: b.Label, URL: b.URL})
+		}
+	}
+	if len(session.UpNext) > 0 {
+		next := session.UpNext[0]
+		data.Next = next.Title
+		if next.Type == "track" && next.Artist != "" {
+			data.Next += " by " + next.Artist
+		}
+	}
+	switch session.MediaType {
+	case plex.MediaTypeMusic:
+		data.Lyric = a.lyricLine(session)
+	case plex.MediaTypeAudiobook:
+		data.Chapter = session.TrackNumber
+		data.ItemCount = session.TrackCount
+	case plex.MediaTypeLive:
+		data.Track = session.ProgramTitle
+		data.Channel = session.ChannelName
+		data.ChannelNumber = session.ChannelNumber
+	}
+	return data
 }
 
 // resolveArtworkAsync resolves a public cover off the presence path and, if the
 // session is still current (generation unchanged) and not paused, re-issues the
 // presence with the cover. Runs in its own goroutine.
-func (a *App) resolveArtworkAsync(session *plex.MusicSession, gen uint64) {
+func (a *App) resolveArtworkAsync(session *plex.MediaSession, gen uint64) {
 	ctx, cancel := context.WithTimeout(context.Background(), 6*time.Second)
 	defer cancel()
 
@@ -485,6 +678,127 @@ func (a *App) SetPresenceFormat(details, state string) error {
 	return nil
 }
 
+// supportedMediaTypes lists the media types that can be shown on Discord.
+var supportedMediaTypes = []string{
+	plex.MediaTypeMusic, plex.MediaTypeMovie, plex.MediaTypeTV, plex.MediaTypePhoto, plex.MediaTypeClip,
+	plex.MediaTypeLive, plex.MediaTypeAudiobook, plex.MediaTypePodcast,
+}
+
+// isSupportedMediaType reports whether mediaType can be shown on Discord.
+func isSupportedMediaType(mediaType string) bool {
+	for _, t := range supportedMediaTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPresenceFormatFor returns the presence format strings of a media type.
+func (a *App) GetPresenceFormatFor(mediaType string) PresenceFormatSettings {
+	format := a.config.PresenceFormatFor(mediaType)
+	return PresenceFormatSettings{DetailsFormat: format.Details, StateFormat: format.State}
+}
+
+// SetPresenceFormatFor updates the presence format strings of a media type.
+// Pass empty strings to use that type's default layout. Music is stored in
+// the same fields as SetPresenceFormat.
+func (a *App) SetPresenceFormatFor(mediaType, details, state string) error {
+	if !isSupportedMediaType(mediaType) {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "unsupported media type: "+mediaType)
+	}
+	if mediaType == plex.MediaTypeMusic {
+		return a.SetPresenceFormat(details, state)
+	}
+
+	if a.config.PresenceFormats == nil {
+		a.config.PresenceFormats = make(map[string]config.PresenceFormat)
+	}
+	a.config.PresenceFormats[mediaType] = config.PresenceFormat{Details: details, State: state}
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save presence format: %v", err)
+		return err
+	}
+	log.Printf("Presence format updated for %s: details=%q, state=%q", mediaType, details, state)
+	return nil
+}
+
+// GetPresenceButtons returns the presence buttons of a media type.
+func (a *App) GetPresenceButtons(mediaType string) []config.PresenceButton {
+	return a.config.PresenceButtons[mediaType]
+}
+
+// SetPresenceButtons sets the presence buttons of a media type, up to two.
+// Labels and URLs are templates using the format tokens; URLs also take the
+// link tokens {plexurl}, {watchurl}, {musicbrainzurl} and {imdburl}. Pass no
+// buttons to remove them.
+func (a *App) SetPresenceButtons(mediaType string, buttons []config.PresenceButton) error {
+	if !isSupportedMediaType(mediaType) {
+		return errors.New(errors.INVALID_INPUT, "unsupported media type: "+mediaType)
+	}
+	templates := make([]discord.ButtonTemplate, len(buttons))
+	for i, b := range buttons {
+		templates[i] = discord.ButtonTemplate{Label: b.Label, URL: b.URL}
+	}
+	if err := discord.ValidateButtons(templates); err != nil {
+		return err
+	}
+
+	if len(buttons) == 0 {
+		delete(a.config.PresenceButtons, mediaType)
+	} else {
+		if a.config.PresenceButtons == nil {
+			a.config.PresenceButtons = make(map[string][]config.PresenceButton)
+		}
+		a.config.PresenceButtons[mediaType] = buttons
+	}
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save presence buttons: %v", err)
+		return err
+	}
+	log.Printf("Presence buttons updated for %s: %d button(s)", mediaType, len(buttons))
+	return nil
+}
+
+// ============================================================================
+// Enabled Media Types
+// ============================================================================
+
+// GetEnabledMediaTypes returns the media types shown on Discord.
+func (a *App) GetEnabledMediaTypes() []string {
+	return a.config.MediaTypesEnabled()
+}
+
+// SetEnabledMediaTypes updates the media types shown on Discord. At least one
+// supported type is required. Running polling is restarted so the pollers
+// pick up the new types immediately.
+func (a *App) SetEnabledMediaTypes(types []string) error {
+	if len(types) == 0 {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "at least one media type must be enabled")
+	}
+	for _, t := range types {
+		if !isSupportedMediaType(t) {
+			return errors.New(errors.CONFIG_WRITE_FAILED, "unsupported media type: "+t)
+		}
+	}
+
+	a.config.EnabledMediaTypes = types
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save enabled media types: %v", err)
+		return err
+	}
+	log.Printf("Enabled media types set to: %v", types)
+
+	if a.IsPollingActive() {
+		a.StopSessionPolling()
+		if err := a.StartSessionPolling(); err != nil {
+			log.Printf("ERROR: Failed to restart polling with new media types: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // ============================================================================
 // Presence Display Options (activity style, member-list line, artwork lookup)
 // ============================================================================
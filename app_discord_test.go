@@ -4,10 +4,14 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"plexcord/internal/config"
 	"plexcord/internal/discord"
+	"plexcord/internal/errors"
+	"plexcord/internal/events"
 	"plexcord/internal/plex"
+	"plexcord/internal/retry"
 )
 
 // fakeDiscordPresence records the arguments of the last presence update so
@@ -17,20 +21,44 @@ type fakeDiscordPresence struct {
 	updateCount    int
 	lastArtworkURL string
 	lastTrack      string
+	lastData       *discord.PresenceData
+	selector       discord.InstanceSelector
+	broadcast      bool
+	connects       int
 }
 
-func (f *fakeDiscordPresence) Connect(string) error { return nil }
-func (f *fakeDiscordPresence) Disconnect() error    { return nil }
+func (f *fakeDiscordPresence) Connect(string) error { f.connects++; f.connected = true; return nil }
+func (f *fakeDiscordPresence) Disconnect() error    { f.connected = false; return nil }
 func (f *fakeDiscordPresence) IsConnected() bool    { return f.connected }
 func (f *fakeDiscordPresence) GetClientID() string  { return "" }
 func (f *fakeDiscordPresence) SetPresence(*discord.PresenceData) error {
 	return nil
 }
 func (f *fakeDiscordPresence) ClearPresence() error { return nil }
-func (f *fakeDiscordPresence) UpdatePresenceFromPlayback(track, artist, album, state string, duration, position int64, artworkURL, player, detailsFormat, stateFormat, activityStyle, statusDisplay string) error {
+func (f *fakeDiscordPresence) SetInstance(sel discord.InstanceSelector) {
+	f.selector = sel
+}
+func (f *fakeDiscordPresence) SetBroadcast(enabled bool) { f.broadcast = enabled }
+func (f *fakeDiscordPresence) Targets() []discord.TargetStatus {
+	if !f.connected {
+		return nil
+	}
+	n := 1
+	if f.broadcast {
+		n = 2
+	}
+	targets := make([]discord.TargetStatus, n)
+	for i := range targets {
+		targets[i].Connected = true
+	}
+	return targets
+}
+func (f *fakeDiscordPresence) UpdatePresenceFromMedia(data *discord.PresenceData) error {
+	data.SetTimestamps(f.lastData)
 	f.updateCount++
-	f.lastArtworkURL = artworkURL
-	f.lastTrack = track
+	f.lastArtworkURL = data.ArtworkURL
+	f.lastTrack = data.Track
+	f.lastData = data
 	return nil
 }
 
@@ -45,16 +73,16 @@ func (f *fakeArtworkResolver) Resolve(context.Context, string, string) (string,
 	return f.cached, nil
 }
 
-func newTokenedSession() *plex.MusicSession {
-	s := &plex.MusicSession{
-		Track:    "Song",
-		Artist:   "Artist",
-		Album:    "Album",
-		ThumbURL: "http://192.168.1.5:32400/library/metadata/1/thumb/1?X-Plex-Token=secret-token",
-		Duration: 240000,
+func newTokenedSession() *plex.MediaSession {
+	return &plex.MediaSession{
+		MediaType: plex.MediaTypeMusic,
+		State:     "playing",
+		Title:     "Song",
+		Artist:    "Artist",
+		Album:     "Album",
+		ThumbURL:  "http://192.168.1.5:32400/library/metadata/1/thumb/1?X-Plex-Token=secret-token",
+		Duration:  240000,
 	}
-	s.State = "playing"
-	return s
 }
 
 func TestUpdateDiscordFromSession_NeverSendsPlexToken(t *testing.T) {
@@ -143,3 +171,222 @@ func TestUpdateDiscordFromSession_ArtworkLookupDisabled(t *testing.T) {
 		t.Errorf("artwork lookup disabled should send no URL, got %q", fake.lastArtworkURL)
 	}
 }
+
+func TestUpdateDiscordFromSession_EpisodeUsesTVFormat(t *testing.T) {
+	fake := &fakeDiscordPresence{connected: true}
+	cfg := config.DefaultConfig()
+	cfg.PresenceDetailsFormat = "{track} by {artist}"
+	cfg.PresenceFormats = map[string]config.PresenceFormat{
+		"tv": {Details: "{show}", State: "S{season}E{episode}"},
+	}
+	a := &App{
+		discord: fake,
+		config:  cfg,
+		// A cached album cover must not be used for video
+		artwork: &fakeArtworkResolver{cached: "https://cdn/cover.jpg", ok: true},
+	}
+
+	a.updateDiscordFromSession(&plex.MediaSession{
+		MediaType: plex.MediaTypeTV,
+		State:     "playing",
+		Title:     "Pilot",
+		ShowTitle: "Show",
+		Season:    1,
+		Episode:   2,
+		Year:      2008,
+	})
+
+	data := fake.lastData
+	if data == nil {
+		t.Fatal("expected a presence update")
+	}
+	if data.MediaType != "tv" || data.Track != "Pilot" || data.ShowTitle != "Show" || data.Season != 1 || data.Episode != 2 || data.Year != "2008" {
+		t.Errorf("episode not mapped to presence data: %+v", data)
+	}
+	if data.DetailsFormat != "{show}" || data.StateFormat != "S{season}E{episode}" {
+		t.Errorf("expected the TV formats, got %q / %q", data.DetailsFormat, data.StateFormat)
+	}
+	if data.ArtworkURL != "" {
+		t.Errorf("expected no album artwork lookup for an episode, got %q", data.ArtworkURL)
+	}
+}
+
+func TestUpdateDiscordFromSession_KeepsTimestampsUnlessSeeked(t *testing.T) {
+	fake := &fakeDiscordPresence{connected: true}
+	a := &App{discord: fake, config: config.DefaultConfig()}
+
+	session := newTokenedSession()
+	session.SessionKey, session.RatingKey = "1", "123"
+	session.ViewOffset = 30000
+	session.ObservedAt = time.Now().Add(-20 * time.Second)
+	a.updateDiscordFromSession(session)
+	first := fake.lastData
+	if first == nil || first.StartTime == nil {
+		t.Fatal("expected a presence update with timestamps")
+	}
+	if want := session.ObservedAt.Add(-30 * time.Second); !first.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", first.StartTime, want)
+	}
+
+	// Re-issued later with the same stale offset (artwork resolved, rating
+	// changed): the progress bar must not jump back
+	resent := *session
+	a.updateDiscordFromSession(&resent)
+	if fake.lastData.StartTime != first.StartTime || fake.lastData.EndTime != first.EndTime {
+		t.Errorf("timestamps moved on a re-send: %v, want %v", fake.lastData.StartTime, first.StartTime)
+	}
+
+	seeked := *session
+	seeked.ViewOffset, seeked.ObservedAt, seeked.Seeked = 120000, time.Now(), true
+	a.updateDiscordFromSession(&seeked)
+	if want := seeked.ObservedAt.Add(-120 * time.Second); !fake.lastData.StartTime.Equal(want) {
+		t.Errorf("after a seek StartTime = %v, want %v", fake.lastData.StartTime, want)
+	}
+}
+
+func TestSetEnabledMediaTypes_ValidatesAndSaves(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if got := a.GetEnabledMediaTypes(); len(got) != 1 || got[0] != "music" {
+		t.Errorf("expected music-only default, got %v", got)
+	}
+	if err := a.SetEnabledMediaTypes(nil); err == nil {
+		t.Error("expected error when disabling every media type")
+	}
+	if err := a.SetEnabledMediaTypes([]string{"music", "bogus"}); err == nil {
+		t.Error("expected error for an unsupported media type")
+	}
+	if err := a.SetEnabledMediaTypes([]string{"movie", "tv"}); err != nil {
+		t.Fatalf("SetEnabledMediaTypes returned error: %v", err)
+	}
+	if got := a.GetEnabledMediaTypes(); len(got) != 2 || got[0] != "movie" {
+		t.Errorf("expected movie and tv enabled, got %v", got)
+	}
+}
+
+func TestSetPresenceFormatFor_StoresPerMediaType(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.SetPresenceFormatFor("movie", "{track}", "{year}"); err != nil {
+		t.Fatalf("SetPresenceFormatFor returned error: %v", err)
+	}
+	if err := a.SetPresenceFormatFor("music", "{artist}", "{album}"); err != nil {
+		t.Fatalf("SetPresenceFormatFor returned error: %v", err)
+	}
+	if got := a.GetPresenceFormatFor("movie"); got.DetailsFormat != "{track}" || got.StateFormat != "{year}" {
+		t.Errorf("unexpected movie format: %+v", got)
+	}
+	// Music keeps using the original format fields
+	if got := a.GetPresenceFormat(); got.DetailsFormat != "{artist}" || got.StateFormat != "{album}" {
+		t.Errorf("expected music format in the legacy fields, got %+v", got)
+	}
+	if got := a.GetPresenceFormatFor("tv"); got.DetailsFormat != "" || got.StateFormat != "" {
+		t.Errorf("expected empty TV format, got %+v", got)
+	}
+	if err := a.SetPresenceFormatFor("bogus", "", ""); err == nil {
+		t.Error("expected error for an unsupported media type")
+	}
+}
+
+func TestSetPresenceButtons_ValidatesAndStores(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	buttons := []config.PresenceButton{{Label: "Watch on Plex", URL: "{watchurl}"}}
+	if err := a.SetPresenceButtons("movie", buttons); err != nil {
+		t.Fatalf("SetPresenceButtons returned error: %v", err)
+	}
+	if got := a.GetPresenceButtons("movie"); len(got) != 1 || got[0].URL != "{watchurl}" {
+		t.Errorf("unexpected movie buttons: %+v", got)
+	}
+	if err := a.SetPresenceButtons("movie", []config.PresenceButton{{Label: "FTP", URL: "ftp://example.com"}}); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for a non-http URL, got %v", errors.INVALID_INPUT, err)
+	}
+	if err := a.SetPresenceButtons("bogus", nil); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for an unsupported media type, got %v", errors.INVALID_INPUT, err)
+	}
+
+	session := &plex.MediaSession{
+		MediaType: plex.MediaTypeMovie, Title: "The Matrix",
+		GUID: "plex://movie/5d7768ba96b655001fdc0408", Slug: "the-matrix",
+	}
+	data := a.presenceDataFor(session, "")
+	if data.WatchURL != "https://watch.plex.tv/movie/the-matrix" || len(data.Buttons) != 1 {
+		t.Errorf("expected the watch link and button in presence data, got %q %+v", data.WatchURL, data.Buttons)
+	}
+
+	if err := a.SetPresenceButtons("movie", nil); err != nil || len(a.GetPresenceButtons("movie")) != 0 {
+		t.Errorf("expected buttons removed, got %+v (err %v)", a.GetPresenceButtons("movie"), err)
+	}
+}
+
+func TestOnDiscordConnectionLost_ReportsAndRetries(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+	bus := events.NewRecordingBus()
+	a.bus = bus
+	a.discordRetry = retry.NewManager("Discord")
+	a.discordRetry.SetCallbacks(func() error { return nil }, func(retry.RetryState) {})
+	t.Cleanup(a.discordRetry.Stop)
+
+	a.onDiscordConnectionLost(errors.New(errors.DISCORD_NOT_RUNNING, "Discord connection lost"))
+
+	if bus.Count(events.DiscordDisconnected) != 1 {
+		t.Errorf("expected 1 DiscordDisconnected, got %d", bus.Count(events.DiscordDisconnected))
+	}
+	if !a.discordRetry.IsRetrying() {
+		t.Error("expected the Discord retry loop to start")
+	}
+}
+
+func TestSetDiscordInstance_StoresAndReconnects(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+	bus := events.NewRecordingBus()
+	a.bus = bus
+	a.discordRetry = retry.NewManager("Discord")
+	fake := &fakeDiscordPresence{connected: true}
+	a.discord = fake
+
+	inst := config.DiscordInstance{Release: discord.ReleaseCanary, UserID: "80351110224678912"}
+	if err := a.SetDiscordInstance(inst); err != nil {
+		t.Fatalf("SetDiscordInstance returned error: %v", err)
+	}
+	if a.GetDiscordInstance() != inst {
+		t.Errorf("expected %+v stored, got %+v", inst, a.GetDiscordInstance())
+	}
+	if fake.selector.Release != discord.ReleaseCanary || fake.selector.UserID != inst.UserID {
+		t.Errorf("expected the selector passed on, got %+v", fake.selector)
+	}
+	if fake.connects != 1 || bus.Count(events.DiscordConnected) != 1 {
+		t.Errorf("expected a reconnect, got %d connects and %d DiscordConnected", fake.connects, bus.Count(events.DiscordConnected))
+	}
+
+	if err := a.SetDiscordInstance(config.DiscordInstance{Release: "nightly"}); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for an unknown release, got %v", errors.INVALID_INPUT, err)
+	}
+}
+
+func TestSetDiscordBroadcast_ReconnectsToEveryClient(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+	bus := events.NewRecordingBus()
+	a.bus = bus
+	a.discordRetry = retry.NewManager("Discord")
+	fake := &fakeDiscordPresence{connected: true}
+	a.discord = fake
+
+	if err := a.SetDiscordBroadcast(true); err != nil {
+		t.Fatalf("SetDiscordBroadcast returned error: %v", err)
+	}
+	if !a.GetDiscordBroadcast() || !fake.broadcast {
+		t.Error("expected broadcast stored and passed on")
+	}
+	if fake.connects != 1 || bus.Count(events.DiscordConnected) != 1 {
+		t.Errorf("expected a reconnect, got %d connects and %d DiscordConnected", fake.connects, bus.Count(events.DiscordConnected))
+	}
+	if got := a.GetDiscordTargets(); len(got) != 2 {
+		t.Errorf("expected two targets, got %+v", got)
+	}
+
+	a.onDiscordTargetsChanged(a.GetDiscordTargets())
+	if bus.Count(events.DiscordTargetsUpdated) != 1 {
+		t.Errorf("expected 1 DiscordTargetsUpdated, got %d", bus.Count(events.DiscordTargetsUpdated))
+	}
+}
@@ -18,11 +18,16 @@ import (
 
 // PlexAPI abstracts the Plex server client used by App methods. It covers
 // the operations needed by validation, user selection, and session polling.
+// SetConnections supplies alternative URLs the client may fail over to, and
+// SetMachineIdentifier pins the client to a specific server.
 type PlexAPI interface {
+	SetConnections(uris []string)
+	SetMachineIdentifier(machineID string)
 	ValidateConnection() (*plex.ValidationResult, error)
 	GetUsers() ([]plex.PlexUser, error)
 	GetMusicSessions(userID string) ([]plex.MusicSession, error)
 	GetMediaSessions(userID string, mediaTypes []string) ([]plex.MediaSession, error)
+	GetLibrarySections() ([]plex.LibrarySection, error)
 }
 
 // PlexAPIFactory constructs a PlexAPI for a given token and server URL.
@@ -39,11 +44,10 @@ type DiscordPresence interface {
 	GetClientID() string
 	SetPresence(data *discord.PresenceData) error
 	ClearPresence() error
-	UpdatePresenceFromPlayback(
-		track, artist, album, state string,
-		duration, position int64,
-		artworkURL, player, detailsFormat, stateFormat, activityStyle, statusDisplay string,
-	) error
+	UpdatePresenceFromMedia(data *discord.PresenceData) error
+	SetInstance(sel discord.InstanceSelector)
+	SetBroadcast(enabled bool)
+	Targets() []discord.TargetStatus
 }
 
 // ArtworkResolver resolves a publicly reachable album-art URL for a track so
@@ -58,8 +62,15 @@ type ArtworkResolver interface {
 
 // TokenStore abstracts credential persistence. The production implementation
 // is backed by the OS keychain; tests can inject a map-based fake.
+//
+// Get/Set/Delete manage the account token. The *ServerToken methods manage
+// per-server access tokens keyed by machine identifier, needed for servers
+// shared by other accounts which don't accept the account token.
 type TokenStore interface {
 	Get() (string, error)
 	Set(token string) error
 	Delete() error
+	GetServerToken(machineID string) (string, error)
+	SetServerToken(machineID, token string) error
+	DeleteServerToken(machineID string) error
 }
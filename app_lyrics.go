@@ -0,0 +1,215 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"plexcord/internal/errors"
+	"plexcord/internal/lyrics"
+	"plexcord/internal/plex"
+)
+
+const (
+	// lyricsTick is how often the current lyric line is checked.
+	lyricsTick = time.Second
+
+	// lyricsMinInterval spaces presence updates made for a new lyric line,
+	// counting from the last update of any kind. Discord accepts 5 activity
+	// updates per 20 seconds; lines sung faster than this are skipped.
+	lyricsMinInterval = 4 * time.Second
+)
+
+// lyricsFollow is the lyrics state of the track being played.
+type lyricsFollow struct {
+	session *plex.MediaSession // Latest update of the track, for its position
+	lyrics  *lyrics.Lyrics     // Nil until loaded, or when the track has none
+	shown   string             // Line in the last presence update
+	stop    chan struct{}      // Closed when another track replaces this one
+}
+
+// lyricsObserver follows the lyrics of playing music. It must run before the
+// Discord observer so presence updates see the current track's lyrics.
+type lyricsObserver struct {
+	follow func(session *plex.MediaSession) // wraps followLyrics
+	stop   func()                           // wraps stopLyrics
+}
+
+func (o *lyricsObserver) OnUpdate(session *plex.MediaSession) {
+	o.follow(session)
+}
+
+func (o *lyricsObserver) OnStop() {
+	o.stop()
+}
+
+// followLyrics starts following the lyrics of session's track, or updates
+// the position of the one already followed. Non-music sessions, and every
+// session while lyrics are disabled, stop following.
+func (a *App) followLyrics(session *plex.MediaSession) {
+	if !a.config.LyricsEnabled || session.MediaType != plex.MediaTypeMusic {
+		a.stopLyrics()
+		return
+	}
+
+	a.lyricsMu.Lock()
+	defer a.lyricsMu.Unlock()
+	if f := a.lyrics; f != nil && f.session.RatingKey == session.RatingKey {
+		f.session = session
+		return
+	}
+	if a.lyrics != nil {
+		close(a.lyrics.stop)
+	}
+	f := &lyricsFollow{session: session, stop: make(chan struct{})}
+	a.lyrics = f
+	go a.loadLyrics(f)
+	go a.tickLyrics(f)
+}
+
+// stopLyrics stops following lyrics.
+func (a *App) stopLyrics() {
+	a.lyricsMu.Lock()
+	defer a.lyricsMu.Unlock()
+	if a.lyrics != nil {
+		close(a.lyrics.stop)
+		a.lyrics = nil
+	}
+}
+
+// loadLyrics fetches the lyrics of f's track: from its Plex server, else
+// from the lyrics folder. Runs in its own goroutine.
+func (a *App) loadLyrics(f *lyricsFollow) {
+	session := f.session
+	var found *lyrics.Lyrics
+	if client := a.clientForSession(session); client != nil {
+		text, err := client.GetLyrics(session.RatingKey)
+		if err != nil {
+			log.Printf("Lyrics lookup on Plex failed for %s: %v", session.Title, err)
+		}
+		found = lyrics.Parse(text)
+	}
+	if found == nil && a.config.LyricsFolder != "" {
+		l, err := lyrics.LoadSidecar(a.config.LyricsFolder, session.Artist, session.Title)
+		if err != nil {
+			log.Printf("Warning: Failed to read lyrics folder: %v", err)
+		}
+		found = l
+	}
+	if found == nil {
+		log.Printf("No synced lyrics for %s", session.Title)
+		return
+	}
+
+	a.lyricsMu.Lock()
+	defer a.lyricsMu.Unlock()
+	if a.lyrics == f {
+		f.lyrics = found
+	}
+}
+
+// tickLyrics re-issues presence whenever f's current line changes, as far
+// as the rate limit allows, until f is replaced or stopped.
+func (a *App) tickLyrics(f *lyricsFollow) {
+	ticker := time.NewTicker(lyricsTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+		}
+
+		a.lyricsMu.Lock()
+		session := f.session
+		changed := f.lyrics != nil && f.lyrics.LineAt(lyricsPosition(session)) != f.shown
+		a.lyricsMu.Unlock()
+		if changed {
+			a.refreshLyricPresence(session)
+		}
+	}
+}
+
+// refreshLyricPresence re-issues presence for session so it shows the
+// current lyric line, unless presence is paused or was updated too recently.
+func (a *App) refreshLyricPresence(session *plex.MediaSession) {
+	if a.IsPresencePaused() || session.State != "playing" {
+		return
+	}
+
+	a.discordMu.Lock()
+	defer a.discordMu.Unlock()
+	if !a.discord.IsConnected() || time.Since(a.presenceSentAt) < lyricsMinInterval {
+		return
+	}
+	if err := a.sendPresenceLocked(session, a.cachedSessionArtwork(session)); err != nil {
+		log.Printf("Warning: Failed to update Discord presence with lyrics: %v", err)
+	}
+}
+
+// lyricLine returns the lyric line to show for session and records it as
+// shown, or "" when its lyrics aren't known or it isn't playing.
+func (a *App) lyricLine(session *plex.MediaSession) string {
+	a.lyricsMu.Lock()
+	defer a.lyricsMu.Unlock()
+	f := a.lyrics
+	if f == nil || f.lyrics == nil || f.session.RatingKey != session.RatingKey || session.State != "playing" {
+		return ""
+	}
+	f.shown = f.lyrics.LineAt(lyricsPosition(session))
+	return f.shown
+}
+
+// lyricsPosition extrapolates a playing session's position from when it was
+// reported, since sessions are only refreshed on change.
+func lyricsPosition(session *plex.MediaSession) time.Duration {
+	position := time.Duration(session.ViewOffset) * time.Millisecond
+	if session.State == "playing" && !session.ObservedAt.IsZero() {
+		position += time.Since(session.ObservedAt)
+	}
+	if duration := time.Duration(session.Duration) * time.Millisecond; duration > 0 && position > duration {
+		position = duration
+	}
+	return position
+}
+
+// clientForSession returns the client of the server session plays from, or
+// nil when no running poller reports it.
+func (a *App) clientForSession(session *plex.MediaSession) *plex.Client {
+	if sp := a.pollerForSession(session); sp != nil {
+		return sp.client
+	}
+	return nil
+}
+
+// LyricsSettings is the synced lyrics configuration for the frontend.
+type LyricsSettings struct {
+	Enabled bool   `json:"enabled"`
+	Folder  string `json:"folder"` // Folder of .lrc files ("" = Plex only)
+}
+
+// GetLyricsSettings returns the synced lyrics settings.
+func (a *App) GetLyricsSettings() LyricsSettings {
+	return LyricsSettings{Enabled: a.config.LyricsEnabled, Folder: a.config.LyricsFolder}
+}
+
+// SetLyricsSettings enables or disables the lyric line in music presence
+// and sets the folder searched for .lrc files. Takes effect from the next
+// session update.
+func (a *App) SetLyricsSettings(settings LyricsSettings) error {
+	if settings.Folder != "" {
+		if info, err := os.Stat(settings.Folder); err != nil || !info.IsDir() {
+			return errors.New(errors.INVALID_INPUT, "lyrics folder not found: "+settings.Folder)
+		}
+	}
+	a.config.LyricsEnabled = settings.Enabled
+	a.config.LyricsFolder = settings.Folder
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save lyrics settings: %v", err)
+		return err
+	}
+	if !settings.Enabled {
+		a.stopLyrics()
+	}
+	return nil
+}
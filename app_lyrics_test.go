@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"plexcord/internal/config"
+	"plexcord/internal/errors"
+	"plexcord/internal/plex"
+)
+
+func TestFollowLyrics_ShowsSidecarLine(t *testing.T) {
+	dir := t.TempDir()
+	lrc := "[00:10.00]Out of touch\n[00:20.00]Out of reach\n"
+	if err := os.WriteFile(filepath.Join(dir, "Def Leppard - Hysteria.lrc"), []byte(lrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.LyricsEnabled = true
+	cfg.LyricsFolder = dir
+	a := newTestApp(cfg)
+	defer a.stopLyrics()
+
+	session := &plex.MediaSession{
+		RatingKey:  "5",
+		MediaType:  plex.MediaTypeMusic,
+		State:      "playing",
+		Title:      "Hysteria",
+		Artist:     "Def Leppard",
+		ViewOffset: 12000,
+		ObservedAt: time.Now(),
+	}
+	a.followLyrics(session)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for a.lyricLine(session) == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := a.lyricLine(session); got != "Out of touch" {
+		t.Errorf("lyricLine() = %q, want %q", got, "Out of touch")
+	}
+
+	paused := *session
+	paused.State = "paused"
+	a.followLyrics(&paused)
+	if got := a.lyricLine(&paused); got != "" {
+		t.Errorf("expected no lyric line while paused, got %q", got)
+	}
+
+	a.followLyrics(&plex.MediaSession{RatingKey: "6", MediaType: plex.MediaTypeMovie, State: "playing"})
+	if a.lyrics != nil {
+		t.Error("expected lyrics to stop following for a movie")
+	}
+}
+
+func TestSetLyricsSettings_ValidatesFolder(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.SetLyricsSettings(LyricsSettings{Enabled: true, Folder: filepath.Join(t.TempDir(), "missing")}); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for a missing folder, got %v", errors.INVALID_INPUT, err)
+	}
+	dir := t.TempDir()
+	if err := a.SetLyricsSettings(LyricsSettings{Enabled: true, Folder: dir}); err != nil {
+		t.Fatalf("SetLyricsSettings returned error: %v", err)
+	}
+	if got := a.GetLyricsSettings(); !got.Enabled || got.Folder != dir {
+		t.Errorf("unexpected settings: %+v", got)
+	}
+}
+
+func TestRefreshLyricPresence_KeepsTimestamps(t *testing.T) {
+	fake := &fakeDiscordPresence{connected: true}
+	a := newTestApp(config.DefaultConfig())
+	a.discord = fake
+
+	session := &plex.MediaSession{
+		SessionKey: "1",
+		RatingKey:  "5",
+		MediaType:  plex.MediaTypeMusic,
+		State:      "playing",
+		Title:      "Hysteria",
+		Artist:     "Def Leppard",
+		Duration:   354000,
+		ViewOffset: 12000,
+		ObservedAt: time.Now().Add(-30 * time.Second),
+	}
+	a.updateDiscordFromSession(session)
+	first := fake.lastData
+	if first == nil || first.StartTime == nil {
+		t.Fatal("expected a presence update with timestamps")
+	}
+
+	// The lyric line changed 30s after the last poll: presence is re-sent
+	// from the same session, whose offset is now 30s behind
+	a.presenceSentAt = time.Now().Add(-lyricsMinInterval)
+	a.refreshLyricPresence(session)
+	if fake.updateCount != 2 {
+		t.Fatalf("expected the lyric refresh to re-send presence, got %d updates", fake.updateCount)
+	}
+	if !fake.lastData.StartTime.Equal(*first.StartTime) || !fake.lastData.EndTime.Equal(*first.EndTime) {
+		t.Errorf("lyric refresh moved the timestamps to %v-%v, want %v-%v",
+			fake.lastData.StartTime, fake.lastData.EndTime, first.StartTime, first.EndTime)
+	}
+}
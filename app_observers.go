@@ -20,7 +20,7 @@ import (
 // can be added without modifying existing code (OCP).
 type SessionObserver interface {
 	// OnUpdate is called when a new or changed session is received.
-	OnUpdate(session *plex.MusicSession)
+	OnUpdate(session *plex.MediaSession)
 	// OnStop is called when playback stops (session becomes nil).
 	OnStop()
 }
@@ -30,14 +30,14 @@ type SessionObserver interface {
 // ----------------------------------------------------------------------------
 type sessionCacheObserver struct {
 	mu      *sync.RWMutex
-	current **plex.MusicSession
+	current **plex.MediaSession
 }
 
-func newSessionCacheObserver(mu *sync.RWMutex, current **plex.MusicSession) *sessionCacheObserver {
+func newSessionCacheObserver(mu *sync.RWMutex, current **plex.MediaSession) *sessionCacheObserver {
 	return &sessionCacheObserver{mu: mu, current: current}
 }
 
-func (o *sessionCacheObserver) OnUpdate(session *plex.MusicSession) {
+func (o *sessionCacheObserver) OnUpdate(session *plex.MediaSession) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	*o.current = session
@@ -50,7 +50,7 @@ func (o *sessionCacheObserver) OnStop() {
 }
 
 // ----------------------------------------------------------------------------
-// historyObserver records played tracks to the listening history
+// historyObserver records played items to the listening history
 // ----------------------------------------------------------------------------
 type historyObserver struct {
 	store *history.Store
@@ -60,14 +60,17 @@ func newHistoryObserver(store *history.Store) *historyObserver {
 	return &historyObserver{store: store}
 }
 
-func (o *historyObserver) OnUpdate(session *plex.MusicSession) {
-	if o.store == nil {
+func (o *historyObserver) OnUpdate(session *plex.MediaSession) {
+	// A seek is the same play continuing elsewhere in the item
+	if o.store == nil || session.Seeked {
 		return
 	}
 	o.store.Add(history.Entry{
-		Track:     session.Track,
+		MediaType: session.MediaType,
+		Track:     session.Title,
 		Artist:    session.Artist,
 		Album:     session.Album,
+		ShowTitle: session.ShowTitle,
 		Duration:  session.Duration,
 		StartedAt: time.Now(),
 		ThumbURL:  session.ThumbURL,
@@ -89,7 +92,7 @@ func newEventEmitterObserver(bus events.Bus) *eventEmitterObserver {
 	return &eventEmitterObserver{bus: bus}
 }
 
-func (o *eventEmitterObserver) OnUpdate(session *plex.MusicSession) {
+func (o *eventEmitterObserver) OnUpdate(session *plex.MediaSession) {
 	o.bus.Emit(events.PlaybackUpdated, session)
 }
 
@@ -106,7 +109,7 @@ func (o *eventEmitterObserver) OnStop() {
 // we delegate to small hook functions the App provides, keeping the
 // observer testable without the App.
 type discordPresenceObserver struct {
-	update        func(session *plex.MusicSession) // wraps updateDiscordFromSession
+	update        func(session *plex.MediaSession) // wraps updateDiscordFromSession
 	clearOnStop   func()                           // wraps clearDiscordOnStop
 	isManualPause func() bool                      // returns true when presence paused
 	scheduleHide  func()                           // schedules hide-when-paused timer
@@ -115,7 +118,7 @@ type discordPresenceObserver struct {
 	log           func(format string, args ...any)
 }
 
-func (o *discordPresenceObserver) OnUpdate(session *plex.MusicSession) {
+func (o *discordPresenceObserver) OnUpdate(session *plex.MediaSession) {
 	if o.isManualPause() {
 		// Manually paused — skip presence updates entirely
 		return
@@ -144,13 +147,17 @@ func (o *discordPresenceObserver) OnStop() {
 // runSessionPipeline consumes session updates from the channel and dispatches
 // each to the ordered list of observers. This replaces the previous
 // handleSessionUpdates god function. The loop exits when the channel closes.
-func runSessionPipeline(sessionCh <-chan *plex.MusicSession, observers []SessionObserver) {
-	var lastSession *plex.MusicSession
+func runSessionPipeline(sessionCh <-chan *plex.MediaSession, observers []SessionObserver) {
+	var lastSession *plex.MediaSession
 
 	for session := range sessionCh {
 		switch {
 		case session != nil:
-			log.Printf("Playback detected: %s - %s", session.Track, session.Artist)
+			if session.Seeked {
+				log.Printf("Seek detected: [%s] %s at %dms", session.MediaType, session.Title, session.ViewOffset)
+			} else {
+				log.Printf("Playback detected: [%s] %s", session.MediaType, session.Title)
+			}
 			for _, o := range observers {
 				o.OnUpdate(session)
 			}
@@ -5,18 +5,19 @@ import (
 	"testing"
 
 	"plexcord/internal/events"
+	"plexcord/internal/history"
 	"plexcord/internal/plex"
 )
 
 func TestSessionCacheObserver_StoresAndClearsSession(t *testing.T) {
 	var mu sync.RWMutex
-	var current *plex.MusicSession
+	var current *plex.MediaSession
 	obs := newSessionCacheObserver(&mu, &current)
 
-	session := &plex.MusicSession{Track: "Song", Artist: "Artist"}
+	session := &plex.MediaSession{Title: "Song", Artist: "Artist"}
 	obs.OnUpdate(session)
 
-	if current == nil || current.Track != "Song" {
+	if current == nil || current.Title != "Song" {
 		t.Errorf("expected cached session, got %v", current)
 	}
 
@@ -30,7 +31,7 @@ func TestEventEmitterObserver_EmitsEvents(t *testing.T) {
 	bus := events.NewRecordingBus()
 	obs := newEventEmitterObserver(bus)
 
-	session := &plex.MusicSession{Track: "Song"}
+	session := &plex.MediaSession{Title: "Song"}
 	obs.OnUpdate(session)
 	obs.OnStop()
 
@@ -46,7 +47,7 @@ func TestDiscordPresenceObserver_SkipsWhenManuallyPaused(t *testing.T) {
 	updateCalled := false
 	clearCalled := false
 	obs := &discordPresenceObserver{
-		update:        func(*plex.MusicSession) { updateCalled = true },
+		update:        func(*plex.MediaSession) { updateCalled = true },
 		clearOnStop:   func() { clearCalled = true },
 		isManualPause: func() bool { return true },
 		scheduleHide:  func() {},
@@ -55,7 +56,7 @@ func TestDiscordPresenceObserver_SkipsWhenManuallyPaused(t *testing.T) {
 		log:           func(string, ...any) {},
 	}
 
-	obs.OnUpdate(&plex.MusicSession{Session: plex.Session{State: "playing"}, Track: "Song"})
+	obs.OnUpdate(&plex.MediaSession{State: "playing", Title: "Song"})
 
 	if updateCalled {
 		t.Error("update should not be called when manually paused")
@@ -70,7 +71,7 @@ func TestDiscordPresenceObserver_SchedulesHideWhenPaused(t *testing.T) {
 	scheduleCalled := false
 	cancelCalled := false
 	obs := &discordPresenceObserver{
-		update:        func(*plex.MusicSession) { updateCalled = true },
+		update:        func(*plex.MediaSession) { updateCalled = true },
 		clearOnStop:   func() {},
 		isManualPause: func() bool { return false },
 		scheduleHide:  func() { scheduleCalled = true },
@@ -79,7 +80,7 @@ func TestDiscordPresenceObserver_SchedulesHideWhenPaused(t *testing.T) {
 		log:           func(string, ...any) {},
 	}
 
-	obs.OnUpdate(&plex.MusicSession{Session: plex.Session{State: "paused"}, Track: "Song"})
+	obs.OnUpdate(&plex.MediaSession{State: "paused", Title: "Song"})
 
 	if updateCalled {
 		t.Error("update should not be called when paused + hideOnPause")
@@ -96,7 +97,7 @@ func TestDiscordPresenceObserver_UpdatesOnPlayAfterPause(t *testing.T) {
 	updateCalled := false
 	cancelCalled := false
 	obs := &discordPresenceObserver{
-		update:        func(*plex.MusicSession) { updateCalled = true },
+		update:        func(*plex.MediaSession) { updateCalled = true },
 		clearOnStop:   func() {},
 		isManualPause: func() bool { return false },
 		scheduleHide:  func() {},
@@ -105,7 +106,7 @@ func TestDiscordPresenceObserver_UpdatesOnPlayAfterPause(t *testing.T) {
 		log:           func(string, ...any) {},
 	}
 
-	obs.OnUpdate(&plex.MusicSession{Session: plex.Session{State: "playing"}, Track: "Song"})
+	obs.OnUpdate(&plex.MediaSession{State: "playing", Title: "Song"})
 
 	if !updateCalled {
 		t.Error("update should be called when playing")
@@ -119,13 +120,13 @@ func TestRunSessionPipeline_DispatchesInOrder(t *testing.T) {
 	var order []string
 	recorder := func(name string) SessionObserver {
 		return &fakeObserver{
-			updateFn: func(*plex.MusicSession) { order = append(order, name+":update") },
+			updateFn: func(*plex.MediaSession) { order = append(order, name+":update") },
 			stopFn:   func() { order = append(order, name+":stop") },
 		}
 	}
 
-	ch := make(chan *plex.MusicSession, 3)
-	ch <- &plex.MusicSession{Track: "A"}
+	ch := make(chan *plex.MediaSession, 3)
+	ch <- &plex.MediaSession{Title: "A"}
 	ch <- nil
 	close(ch)
 
@@ -143,9 +144,21 @@ func TestRunSessionPipeline_DispatchesInOrder(t *testing.T) {
 }
 
 type fakeObserver struct {
-	updateFn func(*plex.MusicSession)
+	updateFn func(*plex.MediaSession)
 	stopFn   func()
 }
 
-func (f *fakeObserver) OnUpdate(s *plex.MusicSession) { f.updateFn(s) }
+func (f *fakeObserver) OnUpdate(s *plex.MediaSession) { f.updateFn(s) }
 func (f *fakeObserver) OnStop()                       { f.stopFn() }
+
+func TestHistoryObserver_IgnoresSeeks(t *testing.T) {
+	store := history.NewStore(t.TempDir(), 10)
+	obs := newHistoryObserver(store)
+
+	obs.OnUpdate(&plex.MediaSession{MediaType: plex.MediaTypeMusic, Title: "Song", Artist: "Artist"})
+	obs.OnUpdate(&plex.MediaSession{MediaType: plex.MediaTypeMusic, Title: "Other", Artist: "Artist", Seeked: true})
+
+	if got := store.GetRecent(10); len(got) != 1 || got[0].Track != "Song" {
+		t.Errorf("expected only the first play recorded, got %+v", got)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+
+	"plexcord/internal/errors"
+	"plexcord/internal/plex"
+)
+
+// PausePlayback pauses the current session on its player.
+func (a *App) PausePlayback() error {
+	return a.controlPlayback(plex.CommandPause)
+}
+
+// ResumePlayback resumes the current session on its player.
+func (a *App) ResumePlayback() error {
+	return a.controlPlayback(plex.CommandPlay)
+}
+
+// TogglePlayback pauses the current session if it is playing and resumes
+// it otherwise.
+func (a *App) TogglePlayback() error {
+	a.sessionMu.RLock()
+	session := a.currentSession
+	a.sessionMu.RUnlock()
+
+	if session != nil && session.State == "playing" {
+		return a.controlPlayback(plex.CommandPause)
+	}
+	return a.controlPlayback(plex.CommandPlay)
+}
+
+// SkipToNext skips to the next item of the current player's queue.
+func (a *App) SkipToNext() error {
+	return a.controlPlayback(plex.CommandSkipNext)
+}
+
+// SkipToPrevious goes back to the previous item of the current player's
+// queue (most players restart the current item first).
+func (a *App) SkipToPrevious() error {
+	return a.controlPlayback(plex.CommandSkipPrevious)
+}
+
+// controlPlayback sends a playback command to the player of the current
+// session. The resulting state change arrives through the session pipeline
+// like any other.
+func (a *App) controlPlayback(command string) error {
+	a.sessionMu.RLock()
+	session := a.currentSession
+	a.sessionMu.RUnlock()
+	if session == nil {
+		return errors.New(errors.PLAYBACK_UNAVAILABLE, "nothing is playing")
+	}
+	if session.PlayerID == "" {
+		return errors.New(errors.PLAYBACK_UNAVAILABLE, "the player of the current session can't be controlled")
+	}
+
+	sp := a.pollerForSession(session)
+	if sp == nil {
+		return errors.New(errors.PLAYBACK_UNAVAILABLE, "the current session's server is no longer polled")
+	}
+	if err := sp.command(session.PlayerID, session.MediaType, command); err != nil {
+		log.Printf("ERROR: Playback command %s to %s failed: %v", command, session.PlayerName, err)
+		return err
+	}
+	log.Printf("Playback command %s sent to %s", command, session.PlayerName)
+	return nil
+}
+
+// pollerForSession returns the source of the server session plays from, or
+// nil when no running source reports it.
+func (a *App) pollerForSession(session *plex.MediaSession) *serverPoller {
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+	for _, sp := range a.pollers {
+		for _, s := range sp.sessions() {
+			if s.SessionKey == session.SessionKey && s.RatingKey == session.RatingKey {
+				return sp
+			}
+		}
+	}
+	return nil
+}
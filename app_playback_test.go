@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"plexcord/internal/config"
+	"plexcord/internal/errors"
+	"plexcord/internal/plex"
+)
+
+func TestControlPlayback_RequiresAControllableSession(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.PausePlayback(); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s while nothing plays, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+	a.currentSession = &plex.MediaSession{SessionKey: "1", RatingKey: "5", State: "playing"}
+	if err := a.TogglePlayback(); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s for a session without a player identifier, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+	a.currentSession.PlayerID = "player-1"
+	if err := a.SkipToNext(); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s when the session's server isn't polled, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+}
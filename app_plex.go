@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"net/url"
+	"sync"
 	"time"
 
+	"plexcord/internal/config"
 	"plexcord/internal/errors"
 	"plexcord/internal/events"
 	"plexcord/internal/plex"
@@ -158,6 +160,36 @@ func (a *App) DiscoverPlexServers() ([]plex.Server, error) {
 	return servers, nil
 }
 
+// DiscoverRemotePlexServers lists the servers the signed-in account can reach
+// through the plex.tv resources API: owned servers outside the LAN and servers
+// shared by friends, which GDM discovery cannot find. Each server carries its
+// connection URIs (local, remote, relay) and its own access token; pass it to
+// AddResourceServer to add it with that token.
+func (a *App) DiscoverRemotePlexServers() ([]plex.Server, error) {
+	log.Printf("Starting remote Plex server discovery via plex.tv...")
+
+	token, err := a.tokens.Get()
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve token: %v", err)
+		return nil, errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+	}
+	if token == "" {
+		return nil, errors.New(errors.CONFIG_READ_FAILED, "plex token not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	servers, err := plex.DiscoverRemoteServers(ctx, token)
+	if err != nil {
+		log.Printf("ERROR: Remote discovery failed: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Remote discovery complete: found %d server(s)", len(servers))
+	return servers, nil
+}
+
 // ValidatePlexConnection validates the connection to a Plex server using the stored token.
 // This method is called from the setup wizard to verify that:
 // - The Plex server is reachable at the given URL
@@ -172,7 +204,7 @@ func (a *App) ValidatePlexConnection(serverURL string) (*plex.ValidationResult,
 	log.Printf("Validating Plex connection to: %s", serverURL)
 
 	// Retrieve token from keychain
-	token, err := a.tokens.Get()
+	token, err := a.plexTokenFor(serverURL)
 	if err != nil {
 		log.Printf("ERROR: Failed to retrieve token: %v", err)
 		return nil, errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
@@ -184,20 +216,29 @@ func (a *App) ValidatePlexConnection(serverURL string) (*plex.ValidationResult,
 	}
 
 	// Create Plex client and validate connection
+	known := a.serverConfigFor(serverURL)
 	client := a.plexFactory(token, serverURL)
+	client.SetConnections(known.Connections)
+	client.SetMachineIdentifier(known.MachineIdentifier)
 	result, err := client.ValidateConnection()
 	if err != nil {
 		log.Printf("ERROR: Connection validation failed: %v", err)
 		return nil, err
 	}
 
+	// Remember which server this is so later connects can check it's still
+	// the same machine and a re-scan can find it again after an IP change.
+	a.rememberMachineIdentifier(serverURL, result.MachineIdentifier)
+
 	log.Printf("Connection validated successfully: %s v%s (%d libraries)", result.ServerName, result.ServerVersion, result.LibraryCount)
 
 	// Update connection history
 	a.updatePlexConnectionTime()
 
-	// Stop any pending retries
-	a.stopPlexRetry()
+	// Stop any pending retries, unless another server still needs them
+	if !a.anyPollerInErrorState() {
+		a.stopPlexRetry()
+	}
 
 	return result, nil
 }
@@ -213,7 +254,7 @@ func (a *App) GetPlexUsers(serverURL string) ([]plex.PlexUser, error) {
 	log.Printf("Retrieving Plex users from: %s", serverURL)
 
 	// Retrieve token from keychain
-	token, err := a.tokens.Get()
+	token, err := a.plexTokenFor(serverURL)
 	if err != nil {
 		log.Printf("ERROR: Failed to retrieve token: %v", err)
 		return nil, errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
@@ -225,7 +266,10 @@ func (a *App) GetPlexUsers(serverURL string) ([]plex.PlexUser, error) {
 	}
 
 	// Create Plex client and get users
+	known := a.serverConfigFor(serverURL)
 	client := a.plexFactory(token, serverURL)
+	client.SetConnections(known.Connections)
+	client.SetMachineIdentifier(known.MachineIdentifier)
 	users, err := client.GetUsers()
 	if err != nil {
 		log.Printf("ERROR: Failed to retrieve users: %v", err)
@@ -290,115 +334,474 @@ func (a *App) SaveServerURL(serverURL string) error {
 	return nil
 }
 
-// StartSessionPolling begins polling the Plex server for music sessions.
-// This method creates a background poller that periodically checks for active
-// music playback and emits Wails events when the session state changes:
-//   - PlaybackUpdated: Emitted when music is playing or track changes
-//   - PlaybackStopped: Emitted when music playback stops
+// serverPoller pairs a running session source with the server it watches, so
+// errors and status can be reported per server. Owned servers are polled
+// (poller); shared servers are followed through the user's players
+// (companion), since their sessions endpoint needs the owner's token.
+type serverPoller struct {
+	server    config.ServerConfig
+	client    *plex.Client
+	poller    *plex.Poller
+	companion *plex.CompanionSource
+	control   *plex.PlayerController // Remote control through the server (polled servers)
+}
+
+// isRunning reports whether the server's session source is running.
+func (sp *serverPoller) isRunning() bool {
+	if sp.companion != nil {
+		return sp.companion.IsRunning()
+	}
+	return sp.poller.IsRunning()
+}
+
+// isInErrorState reports whether the server's session source is failing.
+func (sp *serverPoller) isInErrorState() bool {
+	if sp.companion != nil {
+		return sp.companion.IsInErrorState()
+	}
+	return sp.poller.IsInErrorState()
+}
+
+// stop stops the server's session source.
+func (sp *serverPoller) stop() {
+	if sp.companion != nil {
+		sp.companion.Stop()
+		return
+	}
+	sp.poller.Stop()
+}
+
+// setInterval updates the polling interval. Companion sources are pushed
+// timelines by the players and have no interval.
+func (sp *serverPoller) setInterval(interval time.Duration) {
+	if sp.poller != nil && sp.poller.IsRunning() {
+		sp.poller.SetInterval(interval)
+	}
+}
+
+// setSelectionPolicy changes how the source picks among concurrent sessions.
+func (sp *serverPoller) setSelectionPolicy(policy plex.SelectionPolicy) {
+	if sp.companion != nil {
+		sp.companion.SetSelectionPolicy(policy)
+		return
+	}
+	sp.poller.SetSelectionPolicy(policy)
+}
+
+// interval returns the poller's effective interval, or 0 for a Companion
+// source, which is pushed timelines rather than polling.
+func (sp *serverPoller) interval() time.Duration {
+	if sp.poller == nil {
+		return 0
+	}
+	return sp.poller.GetInterval()
+}
+
+// setAdaptive switches the poller's adaptive mode. No-op for Companion sources.
+func (sp *serverPoller) setAdaptive(enabled bool, ceiling time.Duration) {
+	if sp.poller != nil {
+		sp.poller.SetAdaptive(enabled, ceiling)
+	}
+}
+
+// command sends a playback command to one of the server's players: through
+// the server's player proxy, or straight to the player for Companion sources.
+func (sp *serverPoller) command(playerID, mediaType, command string) error {
+	if sp.companion != nil {
+		return sp.companion.Command(playerID, mediaType, command)
+	}
+	return sp.control.Command(playerID, mediaType, command)
+}
+
+// sessions returns every session the source currently sees for the user.
+func (sp *serverPoller) sessions() []plex.MediaSession {
+	if sp.companion != nil {
+		return sp.companion.Sessions()
+	}
+	return sp.poller.Sessions()
+}
+
+// StartSessionPolling begins polling every active Plex server for sessions of
+// the enabled media types (music only unless configured otherwise).
+// One background poller runs per active server (each with that server's own
+// user), and their sessions are merged by a plex.SessionArbiter so a single
+// session drives presence. Wails events are emitted when that session changes:
+//   - PlaybackUpdated: Emitted when media is playing or the item changes
+//   - PlaybackStopped: Emitted when playback stops
 //
 // Polling uses the configured interval (default 2 seconds per NFR4).
 // Each poll completes within 500ms (NFR5).
 // Returns an error if required configuration is missing.
 func (a *App) StartSessionPolling() error {
-	a.pollerMu.Lock()
-	defer a.pollerMu.Unlock()
-
 	// Check if already polling
-	if a.poller != nil && a.poller.IsRunning() {
+	a.pollerMu.Lock()
+	running := len(a.pollers) > 0
+	a.pollerMu.Unlock()
+	if running {
 		log.Printf("Session polling already running")
 		return nil
 	}
 
 	// Validate configuration
-	serverURL := a.activePlexServerURL()
-	if serverURL == "" {
+	if a.activePlexServerURL() == "" {
 		return errors.New(errors.CONFIG_READ_FAILED, "plex server URL not configured")
 	}
 
-	if a.config.SelectedPlexUserID == "" {
+	servers := a.activePlexServers()
+	if len(servers) == 0 {
 		return errors.New(errors.CONFIG_READ_FAILED, "plex user not selected")
 	}
 
-	// Retrieve token from keychain
-	token, err := a.tokens.Get()
-	if err != nil {
-		log.Printf("ERROR: Failed to retrieve token for polling: %v", err)
-		return errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+	// Retrieve each server's token from keychain
+	tokens := make([]string, len(servers))
+	for i, server := range servers {
+		token, err := a.plexTokenFor(server.URL)
+		if err != nil {
+			log.Printf("ERROR: Failed to retrieve token for polling: %v", err)
+			return errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+		}
+
+		if token == "" {
+			return errors.New(errors.CONFIG_READ_FAILED, "plex token not found")
+		}
+		tokens[i] = token
 	}
 
-	if token == "" {
-		return errors.New(errors.CONFIG_READ_FAILED, "plex token not found")
+	interval := a.pollingInterval()
+
+	// Create context for pollers
+	ctx, stop := context.WithCancel(context.Background())
+
+	// Preparing a server (racing its connections, probing notifications,
+	// discovering players) takes seconds: prepare them all in parallel and
+	// without pollerMu, which status and control calls need meanwhile.
+	mediaTypes := a.config.MediaTypesEnabled()
+	arbiter := plex.NewSessionArbiter()
+	prepared := make([]*serverPoller, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if server.Shared {
+				sp, err := a.newCompanionSource(ctx, arbiter, server, tokens[i])
+				if err != nil {
+					// Keep following the other servers
+					log.Printf("ERROR: Failed to follow shared server %s: %v", server.Name, err)
+					return
+				}
+				prepared[i] = sp
+				return
+			}
+			sp := a.newServerPoller(ctx, arbiter, server, tokens[i], interval)
+			sp.poller.SetMediaTypes(mediaTypes)
+			prepared[i] = sp
+		}()
+	}
+	wg.Wait()
+
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+
+	// Another call may have started polling in the meantime
+	if len(a.pollers) > 0 {
+		stop()
+		log.Printf("Session polling already running")
+		return nil
 	}
+	a.pollerCtx, a.pollerStop, a.arbiter = ctx, stop, arbiter
+
+	sources := make(map[string]<-chan *plex.MediaSession, len(servers))
+	for _, sp := range prepared {
+		if sp == nil {
+			continue
+		}
+		server := sp.server
+		if sp.companion != nil {
+			ch, err := sp.companion.Start(ctx)
+			if err != nil {
+				log.Printf("ERROR: Failed to follow shared server %s: %v", server.Name, err)
+				continue
+			}
+			a.pollers = append(a.pollers, sp)
+			sources[server.URL] = ch
+			log.Printf("Companion session source started: server=%s", server.URL)
+			continue
+		}
+
+		a.pollers = append(a.pollers, sp)
+		sources[server.URL] = sp.poller.StartMedia(ctx)
+		log.Printf("Session polling started: server=%s, user=%s, interval=%v, types=%v", server.URL, server.UserID, interval, mediaTypes)
+	}
+
+	// Merge the per-server streams and handle the selected session
+	sessionCh := arbiter.Run(ctx, sources)
+	go a.handleSessionUpdates(sessionCh)
 
-	// Create Plex client
-	client := plex.NewClient(token, serverURL)
+	return nil
+}
 
-	// Get polling interval from config (default 2 seconds for NFR4 compliance)
+// pollingInterval returns the configured polling interval (default 2
+// seconds for NFR4 compliance).
+func (a *App) pollingInterval() time.Duration {
 	interval := time.Duration(a.config.PollingInterval) * time.Second
 	if interval < time.Second {
 		interval = 2 * time.Second // Default to 2 seconds per NFR4: state changes detected within 2s
 	}
+	return interval
+}
 
-	// Create poller
-	a.poller = plex.NewPoller(client, a.config.SelectedPlexUserID, interval)
+// restartServerPoller replaces the session source of one server with a fresh
+// one, e.g. once the retry loop finds the failing server reachable again.
+// The other servers keep polling. No-op when the server isn't polled.
+func (a *App) restartServerPoller(server config.ServerConfig) error {
+	token, err := a.plexTokenFor(server.URL)
+	if err != nil {
+		return errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+	}
+	if token == "" {
+		return errors.New(errors.CONFIG_READ_FAILED, "plex token not found")
+	}
 
-	// Setup error callbacks for graceful Plex unavailability handling (Story 6.5)
-	a.poller.SetErrorCallbacks(
-		// onError: Called when Plex connection fails
-		func(err error) {
-			log.Printf("Plex connection error detected, starting recovery...")
+	a.pollerMu.Lock()
+	ctx, arbiter := a.pollerCtx, a.arbiter
+	a.pollerMu.Unlock()
+	if ctx == nil {
+		return nil
+	}
 
-			// Clear Discord presence so it doesn't show stale data
-			a.clearDiscordOnStop()
+	// Preparing takes seconds (see StartSessionPolling): not under pollerMu
+	var sp *serverPoller
+	if server.Shared {
+		sp, err = a.newCompanionSource(ctx, arbiter, server, token)
+		if err != nil {
+			return err
+		}
+	} else {
+		sp = a.newServerPoller(ctx, arbiter, server, token, a.pollingInterval())
+		sp.poller.SetMediaTypes(a.config.MediaTypesEnabled())
+	}
 
-			// Emit event for frontend to show error status
-			a.bus.Emit(events.PlexConnectionError, map[string]interface{}{
-				"error":     err.Error(),
-				"errorCode": errors.GetCode(err),
-			})
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+	index := -1
+	for i, running := range a.pollers {
+		if running.server.URL == server.URL {
+			index = i
+			break
+		}
+	}
+	if a.pollerCtx != ctx || index < 0 {
+		// Polling stopped or restarted meanwhile
+		sp.stop()
+		return nil
+	}
 
-			// Start automatic retry (backoff is handled by retry manager)
-			a.startPlexRetry(err)
-		},
-		// onRecovered: Called when Plex connection recovers
-		func() {
-			log.Printf("Plex connection recovered")
+	var ch <-chan *plex.MediaSession
+	if sp.companion != nil {
+		if ch, err = sp.companion.Start(ctx); err != nil {
+			return err
+		}
+	} else {
+		ch = sp.poller.StartMedia(ctx)
+	}
+	// Hand the arbiter the new stream before the old one closes
+	arbiter.Add(ctx, server.URL, ch)
+	old := a.pollers[index]
+	a.pollers[index] = sp
+	old.stop()
+	log.Printf("Session source restarted: server=%s", server.URL)
+	return nil
+}
 
-			// Stop retry and update connection history
-			a.stopPlexRetry()
-			a.updatePlexConnectionTime()
+// newServerPoller creates (but does not start) the poller for one server,
+// wiring its error callbacks to per-server events and the shared retry loop.
+// While the server fails, its session is withdrawn from arbiter.
+func (a *App) newServerPoller(ctx context.Context, arbiter *plex.SessionArbiter, server config.ServerConfig, token string, interval time.Duration) *serverPoller {
+	client := plex.NewClient(token, server.URL)
+	client.SetSessionFilter(a.sessionFilter(server))
+	sp := &serverPoller{
+		server:  server,
+		client:  client,
+		poller:  plex.NewPoller(client, server.UserID, interval),
+		control: plex.NewPlayerController(client),
+	}
+	sp.poller.SetMetadataEnricher(plex.NewMetadataEnricher(client))
+	sp.poller.SetLibraryClassifier(a.newLibraryClassifier(client, server))
+	sp.poller.SetPlayQueueResolver(plex.NewPlayQueueResolver(client))
+	sp.poller.SetSelectionPolicy(a.selectionPolicy())
+	sp.poller.SetAdaptive(a.config.AdaptivePolling, a.adaptiveCeiling())
+
+	// With alternative connections, start on whichever answers fastest; with
+	// a known machine identifier, make sure it's still the same server. The
+	// client re-races on its own whenever the selected one stops working.
+	client.SetMachineIdentifier(server.MachineIdentifier)
+	if len(server.Connections) > 0 || server.MachineIdentifier != "" {
+		client.SetConnections(server.Connections)
+		if selected, err := client.Race(ctx); err != nil {
+			log.Printf("No connection of %s answered, polling will retry: %v", server.Name, err)
+		} else {
+			log.Printf("Using connection %s for %s", selected, server.Name)
+		}
+	}
 
-			// Emit event for frontend to clear error status
-			a.bus.Emit(events.PlexConnectionRestored, nil)
+	// Setup error callbacks for graceful Plex unavailability handling (Story 6.5)
+	sp.poller.SetErrorCallbacks(
+		// onError: Called when this server's connection fails
+		func(err error) {
+			a.handleServerError(ctx, arbiter, server, err)
+
+			// The server may have moved to another IP: look for it by
+			// machine identifier in the background.
+			if errors.IsConnectionError(errors.GetCode(err)) {
+				a.startServerRelocation(server)
+			}
 		},
+		// onRecovered: Called when this server's connection recovers
+		func() { a.handleServerRecovered(server) },
 	)
 
-	// Create context for poller
-	a.pollerCtx, a.pollerStop = context.WithCancel(context.Background())
+	// Prefer event-driven updates via the notifications socket. Servers that
+	// don't support it (or block the upgrade) keep the interval polling.
+	notifications := plex.NewNotificationSource(client)
+	probeCtx, cancelProbe := context.WithTimeout(ctx, 3*time.Second)
+	if err := notifications.Probe(probeCtx); err != nil {
+		log.Printf("Plex notifications unavailable on %s, using interval polling: %v", server.URL, err)
+	} else {
+		sp.poller.SetNotificationSource(notifications)
+	}
+	cancelProbe()
 
-	// Start polling
-	sessionCh := a.poller.Start(a.pollerCtx)
+	return sp
+}
 
-	log.Printf("Session polling started: user=%s, interval=%v", a.config.SelectedPlexUserID, interval)
+// handleServerError withdraws a failing server's session and starts the
+// recovery: the frontend is told which server failed and the retry loop
+// begins (or keeps) retrying it.
+func (a *App) handleServerError(ctx context.Context, arbiter *plex.SessionArbiter, server config.ServerConfig, err error) {
+	log.Printf("Plex connection error detected on %s, starting recovery...", server.URL)
+
+	// Withdraw the server's session so it doesn't show stale data:
+	// another server's session takes over, and presence is cleared
+	// only when none is left.
+	arbiter.Drop(ctx, server.URL)
+
+	// Emit event for frontend to show error status
+	a.bus.Emit(events.PlexConnectionError, map[string]interface{}{
+		"error":      err.Error(),
+		"errorCode":  errors.GetCode(err),
+		"serverUrl":  server.URL,
+		"serverName": server.Name,
+	})
+
+	// Start automatic retry (backoff is handled by retry manager)
+	a.startPlexRetry(err)
+}
 
-	// Start goroutine to handle session updates
-	go a.handleSessionUpdates(sessionCh)
+// handleServerRecovered clears a server's error once its source works again.
+func (a *App) handleServerRecovered(server config.ServerConfig) {
+	log.Printf("Plex connection recovered: %s", server.URL)
 
-	return nil
+	// Only settle the shared retry loop once no server is failing
+	if !a.anyPollerInErrorState() {
+		a.stopPlexRetry()
+	}
+	a.updatePlexConnectionTime()
+
+	// Emit event for frontend to clear error status
+	a.bus.Emit(events.PlexConnectionRestored, map[string]interface{}{
+		"serverUrl":  server.URL,
+		"serverName": server.Name,
+	})
+}
+
+// newCompanionSource creates (but does not start) the source following
+// playback on a shared server by subscribing to the timelines of the
+// account's players. token is the server's own access token (for metadata
+// and artwork); the players accept the account token.
+func (a *App) newCompanionSource(ctx context.Context, arbiter *plex.SessionArbiter, server config.ServerConfig, token string) (*serverPoller, error) {
+	accountToken, err := a.tokens.Get()
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+	}
+
+	discoverCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	players, err := a.discoverPlayers(discoverCtx, accountToken)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	if len(players) == 0 {
+		log.Printf("Warning: No reachable Plex players found to follow %s", server.Name)
+	}
+
+	client := plex.NewClient(token, server.URL)
+	client.SetMachineIdentifier(server.MachineIdentifier)
+	client.SetConnections(server.Connections)
+	client.SetSessionFilter(a.sessionFilter(server))
+
+	companion := plex.NewCompanionSource(client, players, accountToken)
+	companion.SetMetadataEnricher(plex.NewMetadataEnricher(client))
+	companion.SetLibraryClassifier(a.newLibraryClassifier(client, server))
+	companion.SetPlayQueueResolver(plex.NewPlayQueueResolver(client))
+	companion.SetSelectionPolicy(a.selectionPolicy())
+	companion.SetMediaTypes(a.config.MediaTypesEnabled())
+	// No player accepting the subscription is this server's connection
+	// failing: it enters the retry loop like a failing poller does.
+	companion.SetErrorCallbacks(
+		func(err error) { a.handleServerError(ctx, arbiter, server, err) },
+		func() { a.handleServerRecovered(server) },
+	)
+
+	return &serverPoller{server: server, client: client, companion: companion}, nil
+}
+
+// newLibraryClassifier creates the audiobook/podcast classifier of a
+// server's session source from the library IDs configured for that server.
+func (a *App) newLibraryClassifier(client *plex.Client, server config.ServerConfig) *plex.LibraryClassifier {
+	classification := a.config.LibraryClassificationFor(server.MachineIdentifier)
+	return plex.NewLibraryClassifier(client, classification.Audiobooks, classification.Podcasts)
+}
+
+// isServerPolled reports whether the server at serverURL has a running
+// session source.
+func (a *App) isServerPolled(serverURL string) bool {
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+	for _, sp := range a.pollers {
+		if sp.server.URL == serverURL {
+			return true
+		}
+	}
+	return false
+}
+
+// anyPollerInErrorState reports whether any server's poller is failing.
+// Called from poller callbacks, which run without pollerMu held.
+func (a *App) anyPollerInErrorState() bool {
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+	for _, sp := range a.pollers {
+		if sp.isInErrorState() {
+			return true
+		}
+	}
+	return false
 }
 
 // handleSessionUpdates constructs the observer pipeline and runs it.
 // The actual event handling is delegated to individual observers for
 // separation of concerns; see app_observers.go.
 //
-// Observer order matters: cache → history → discord → events. The
+// Observer order matters: cache → history → lyrics → discord → events. The
 // discord observer is gated by the manual-pause flag and the
 // hide-when-paused config, and the event emitter always fires last so
 // the frontend sees the state after all side effects have run.
-func (a *App) handleSessionUpdates(sessionCh <-chan *plex.MusicSession) {
+func (a *App) handleSessionUpdates(sessionCh <-chan *plex.MediaSession) {
 	observers := []SessionObserver{
 		newSessionCacheObserver(&a.sessionMu, &a.currentSession),
 		newHistoryObserver(a.history),
+		&lyricsObserver{follow: a.followLyrics, stop: a.stopLyrics},
 		&discordPresenceObserver{
 			update:        a.updateDiscordFromSession,
 			clearOnStop:   a.clearDiscordOnStop,
@@ -428,19 +831,22 @@ func (a *App) StopSessionPolling() {
 	a.pollerMu.Lock()
 	defer a.pollerMu.Unlock()
 
-	if a.poller == nil {
+	if len(a.pollers) == 0 {
 		return
 	}
 
-	// Cancel context and stop poller
+	// Cancel context and stop pollers
 	if a.pollerStop != nil {
 		a.pollerStop()
 	}
 
-	a.poller.Stop()
-	a.poller = nil
+	for _, sp := range a.pollers {
+		sp.stop()
+	}
+	a.pollers = nil
 	a.pollerCtx = nil
 	a.pollerStop = nil
+	a.arbiter = nil
 
 	log.Printf("Session polling stopped")
 }
@@ -450,17 +856,42 @@ func (a *App) IsPollingActive() bool {
 	a.pollerMu.Lock()
 	defer a.pollerMu.Unlock()
 
-	return a.poller != nil && a.poller.IsRunning()
+	for _, sp := range a.pollers {
+		if sp.isRunning() {
+			return true
+		}
+	}
+	return false
 }
 
 // PlexConnectionStatus represents the current Plex connection status.
+// The top-level fields summarise all servers (Connected while at least one
+// server is reachable); Servers carries the per-server breakdown.
 type PlexConnectionStatus struct {
-	ServerURL    string `json:"serverUrl"`
-	UserID       string `json:"userId"`
-	UserName     string `json:"userName"`
-	Connected    bool   `json:"connected"`
-	Polling      bool   `json:"polling"`
-	InErrorState bool   `json:"inErrorState"`
+	ServerURL    string             `json:"serverUrl"`
+	UserID       string             `json:"userId"`
+	UserName     string             `json:"userName"`
+	Servers      []PlexServerStatus `json:"servers"`
+	Connected    bool               `json:"connected"`
+	Polling      bool               `json:"polling"`
+	InErrorState bool               `json:"inErrorState"`
+}
+
+// PlexServerStatus is the connection status of a single polled server.
+// ActiveURL is the connection currently in use, which differs from URL after
+// a failover to one of the server's alternative connections.
+type PlexServerStatus struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	ActiveURL string `json:"activeUrl"`
+	UserID    string `json:"userId"`
+	UserName  string `json:"userName"`
+	Connected bool   `json:"connected"`
+	Polling   bool   `json:"polling"`
+	// PollingInterval is the effective interval in seconds, which adaptive
+	// polling raises while idle. 0 for servers followed through Companion.
+	PollingInterval int  `json:"pollingInterval"`
+	InErrorState    bool `json:"inErrorState"`
 }
 
 // GetPlexConnectionStatus returns the current Plex connection status (Story 6.5).
@@ -472,13 +903,32 @@ func (a *App) GetPlexConnectionStatus() PlexConnectionStatus {
 		ServerURL: a.activePlexServerURL(),
 		UserID:    a.config.SelectedPlexUserID,
 		UserName:  a.config.SelectedPlexUserName,
+		Servers:   make([]PlexServerStatus, 0, len(a.pollers)),
 	}
 
-	if a.poller != nil {
-		status.Polling = a.poller.IsRunning()
-		status.InErrorState = a.poller.IsInErrorState()
-		status.Connected = status.Polling && !status.InErrorState
+	failing := 0
+	for _, sp := range a.pollers {
+		s := PlexServerStatus{
+			Name:         sp.server.Name,
+			URL:          sp.server.URL,
+			ActiveURL:    sp.client.ServerURL(),
+			UserID:       sp.server.UserID,
+			UserName:     sp.server.UserName,
+			Polling:      sp.isRunning(),
+			InErrorState: sp.isInErrorState(),
+
+			PollingInterval: int(sp.interval() / time.Second),
+		}
+		s.Connected = s.Polling && !s.InErrorState
+		if s.InErrorState {
+			failing++
+		}
+		status.Polling = status.Polling || s.Polling
+		status.Connected = status.Connected || s.Connected
+		status.Servers = append(status.Servers, s)
 	}
+	// The dashboard's error banner only applies when no server is usable
+	status.InErrorState = len(a.pollers) > 0 && failing == len(a.pollers)
 
 	return status
 }
@@ -504,8 +954,10 @@ func (a *App) SetPollingInterval(intervalSeconds int) error {
 
 	// Update running poller if active
 	a.pollerMu.Lock()
-	if a.poller != nil && a.poller.IsRunning() {
-		a.poller.SetInterval(time.Duration(intervalSeconds) * time.Second)
+	for _, sp := range a.pollers {
+		sp.setInterval(time.Duration(intervalSeconds) * time.Second)
+	}
+	if len(a.pollers) > 0 {
 		log.Printf("Polling interval updated to %d seconds", intervalSeconds)
 	}
 	a.pollerMu.Unlock()
@@ -522,24 +974,242 @@ func (a *App) GetPollingInterval() int {
 	return a.config.PollingInterval
 }
 
-// GetCurrentSession returns the current music session if music is playing.
-// Returns nil if no music is currently playing.
+// selectionPolicy returns the configured session selection policy.
+func (a *App) selectionPolicy() plex.SelectionPolicy {
+	s := a.config.SessionSelection
+	return plex.SelectionPolicy{
+		PreferredPlayer: s.PreferredPlayer,
+		PreferPlaying:   s.PreferPlaying,
+		PreferMedia:     s.PreferMedia,
+		PreferRecent:    s.PreferRecent,
+	}
+}
+
+// GetSessionSelection returns how one of several concurrent sessions is
+// chosen for presence.
+func (a *App) GetSessionSelection() config.SessionSelection {
+	return a.config.SessionSelection
+}
+
+// SetSessionSelection changes how one of several concurrent sessions is
+// chosen. Running pollers pick it up on their next poll.
+func (a *App) SetSessionSelection(selection config.SessionSelection) error {
+	switch selection.PreferMedia {
+	case "", plex.PreferVideo, plex.PreferAudio:
+	default:
+		return errors.New(errors.INVALID_INPUT, "invalid media preference: "+selection.PreferMedia)
+	}
+
+	a.config.SessionSelection = selection
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save session selection: %v", err)
+		return err
+	}
+
+	a.pollerMu.Lock()
+	for _, sp := range a.pollers {
+		sp.setSelectionPolicy(a.selectionPolicy())
+	}
+	a.pollerMu.Unlock()
+	return nil
+}
+
+// sessionFilter returns the configured session filter rules for a server:
+// the player rules plus that server's library rules.
+func (a *App) sessionFilter(server config.ServerConfig) *plex.SessionFilter {
+	f := a.config.SessionFilters
+	libraries := f.LibraryRulesFor(server.MachineIdentifier)
+	return &plex.SessionFilter{
+		IncludePlayers:   f.IncludePlayers,
+		ExcludePlayers:   f.ExcludePlayers,
+		IncludeProducts:  f.IncludeProducts,
+		ExcludeProducts:  f.ExcludeProducts,
+		IncludePlayerIDs: f.IncludePlayerIDs,
+		ExcludePlayerIDs: f.ExcludePlayerIDs,
+		IncludeLibraries: libraries.Include,
+		ExcludeLibraries: libraries.Exclude,
+	}
+}
+
+// GetSessionFilters returns the player and per-server library filter rules.
+func (a *App) GetSessionFilters() config.SessionFilters {
+	return a.config.SessionFilters
+}
+
+// SetSessionFilters changes the player and per-server library filter rules.
+// Running pollers apply them from their next poll.
+func (a *App) SetSessionFilters(filters config.SessionFilters) error {
+	a.config.SessionFilters = filters
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save session filters: %v", err)
+		return err
+	}
+
+	a.pollerMu.Lock()
+	for _, sp := range a.pollers {
+		sp.client.SetSessionFilter(a.sessionFilter(sp.server))
+	}
+	a.pollerMu.Unlock()
+	return nil
+}
+
+// GetAllSessions returns every session of the monitored users across all
+// polled servers, including those not shown on Discord. Empty when polling
+// is stopped.
+func (a *App) GetAllSessions() []plex.MediaSession {
+	a.pollerMu.Lock()
+	defer a.pollerMu.Unlock()
+
+	all := make([]plex.MediaSession, 0)
+	for _, sp := range a.pollers {
+		all = append(all, sp.sessions()...)
+	}
+	return all
+}
+
+// GetLibrarySections lists the libraries of a Plex server, so the user can
+// pick which ones hold audiobooks or podcasts.
+func (a *App) GetLibrarySections(serverURL string) ([]plex.LibrarySection, error) {
+	token, err := a.plexTokenFor(serverURL)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.CONFIG_READ_FAILED, "failed to retrieve token")
+	}
+	if token == "" {
+		return nil, errors.New(errors.CONFIG_READ_FAILED, "plex token not found")
+	}
+
+	known := a.serverConfigFor(serverURL)
+	client := a.plexFactory(token, serverURL)
+	client.SetConnections(known.Connections)
+	client.SetMachineIdentifier(known.MachineIdentifier)
+	sections, err := client.GetLibrarySections()
+	if err != nil {
+		log.Printf("ERROR: Failed to retrieve library sections: %v", err)
+		return nil, err
+	}
+	return sections, nil
+}
+
+// GetLibraryClassification returns the libraries chosen as audiobook and
+// podcast libraries on the server with the given machine identifier.
+func (a *App) GetLibraryClassification(machineID string) config.LibraryClassification {
+	return a.config.LibraryClassificationFor(machineID)
+}
+
+// SetLibraryClassification chooses the audiobook and podcast libraries of
+// the server with the given machine identifier, and restarts that server's
+// session source if it is polled so sessions are classified accordingly.
+func (a *App) SetLibraryClassification(machineID string, classification config.LibraryClassification) error {
+	if machineID == "" {
+		return errors.New(errors.INVALID_INPUT, "server machine identifier is required")
+	}
+
+	classifications := make(map[string]config.LibraryClassification, len(a.config.LibraryClassifications)+1)
+	for id, c := range a.config.LibraryClassifications {
+		classifications[id] = c
+	}
+	if len(classification.Audiobooks) == 0 && len(classification.Podcasts) == 0 {
+		delete(classifications, machineID)
+	} else {
+		classifications[machineID] = classification
+	}
+	a.config.LibraryClassifications = classifications
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save library classification: %v", err)
+		return err
+	}
+	log.Printf("Library classification of %s set: audiobooks=%v, podcasts=%v", machineID, classification.Audiobooks, classification.Podcasts)
+
+	for _, server := range a.config.Servers {
+		if server.MachineIdentifier != machineID || !a.isServerPolled(server.URL) {
+			continue
+		}
+		if err := a.restartServerPoller(server); err != nil {
+			log.Printf("ERROR: Failed to restart %s with new library classification: %v", server.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// adaptiveCeiling returns the configured idle ceiling of adaptive polling.
+func (a *App) adaptiveCeiling() time.Duration {
+	if a.config.AdaptivePollingMax <= 0 {
+		return plex.DefaultAdaptiveCeiling
+	}
+	return time.Duration(a.config.AdaptivePollingMax) * time.Second
+}
+
+// AdaptivePolling is the adaptive polling configuration for the frontend.
+type AdaptivePolling struct {
+	Enabled    bool `json:"enabled"`
+	MaxSeconds int  `json:"maxSeconds"` // Idle ceiling
+}
+
+// GetAdaptivePolling returns the adaptive polling settings, with the default
+// ceiling filled in.
+func (a *App) GetAdaptivePolling() AdaptivePolling {
+	return AdaptivePolling{
+		Enabled:    a.config.AdaptivePolling,
+		MaxSeconds: int(a.adaptiveCeiling() / time.Second),
+	}
+}
+
+// SetAdaptivePolling enables or disables adaptive polling: fast polling
+// during playback, backing off up to maxSeconds (clamped to 60) when idle.
+// Running pollers pick it up on their next poll.
+func (a *App) SetAdaptivePolling(enabled bool, maxSeconds int) error {
+	if maxSeconds > 60 {
+		maxSeconds = 60
+	}
+	if maxSeconds < 0 {
+		maxSeconds = 0
+	}
+
+	a.config.AdaptivePolling = enabled
+	a.config.AdaptivePollingMax = maxSeconds
+	if err := a.saveConfig(); err != nil {
+		log.Printf("ERROR: Failed to save adaptive polling: %v", err)
+		return err
+	}
+
+	a.pollerMu.Lock()
+	for _, sp := range a.pollers {
+		sp.setAdaptive(enabled, a.adaptiveCeiling())
+	}
+	a.pollerMu.Unlock()
+	return nil
+}
+
+// GetCurrentSession returns the current media session if something is playing.
+// Returns nil if nothing of an enabled media type is currently playing.
 // This is used by the frontend to restore playback state after page refresh.
-func (a *App) GetCurrentSession() *plex.MusicSession {
+func (a *App) GetCurrentSession() *plex.MediaSession {
 	a.sessionMu.RLock()
 	defer a.sessionMu.RUnlock()
 	return a.currentSession
 }
 
+// GetUpNext returns what plays after the current session in its play queue,
+// nearest first. Empty when nothing plays or the queue is unknown.
+func (a *App) GetUpNext() []plex.PlayQueueItem {
+	a.sessionMu.RLock()
+	defer a.sessionMu.RUnlock()
+	if a.currentSession == nil {
+		return []plex.PlayQueueItem{}
+	}
+	return append([]plex.PlayQueueItem{}, a.currentSession.UpNext...)
+}
+
 // autoConnectPlex attempts to restore the Plex connection using persisted config.
 // This mirrors Discord auto-connect behavior by validating and restarting polling.
 func (a *App) autoConnectPlex() {
-	serverURL := a.activePlexServerURL()
-	if serverURL == "" {
+	if a.activePlexServerURL() == "" {
 		log.Printf("Auto-connect skipped: Plex server URL not configured")
 		return
 	}
-	if a.config.SelectedPlexUserID == "" {
+	servers := a.activePlexServers()
+	if len(servers) == 0 {
 		log.Printf("Auto-connect skipped: Plex user not selected")
 		return
 	}
@@ -556,7 +1226,7 @@ func (a *App) autoConnectPlex() {
 	}
 
 	log.Printf("Auto-connecting to Plex on startup...")
-	if _, err := a.ValidatePlexConnection(serverURL); err != nil {
+	if err := a.validatePlexServers(servers); err != nil {
 		log.Printf("Warning: Failed to validate Plex connection on startup: %v", err)
 		a.startPlexRetry(err)
 		return
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"plexcord/internal/config"
+	"plexcord/internal/errors"
+	"plexcord/internal/events"
+	"plexcord/internal/plex"
+	"plexcord/internal/retry"
+)
+
+// plexSessionsServer serves one playing track for user 1 on
+// /status/sessions, plus the endpoints validation checks, while healthy, and
+// 500s once failing is set.
+func plexSessionsServer(t *testing.T, title string, delay time.Duration, failing *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing != nil && failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		switch r.URL.Path {
+		case "/identity":
+			w.Write([]byte(`<MediaContainer machineIdentifier="` + title + `" version="1.40"/>`))
+			return
+		case "/library/sections/":
+			w.Write([]byte(`<MediaContainer size="1"/>`))
+			return
+		case "/status/sessions":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<MediaContainer size="1">
+  <Track sessionKey="1" ratingKey="10" type="track" title="` + title + `" grandparentTitle="Artist" parentTitle="Album">
+    <User id="1" title="me"/>
+    <Player state="playing" title="Player"/>
+  </Track>
+</MediaContainer>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStartSessionPolling_FallsBackWhenOneServerFails(t *testing.T) {
+	var failing atomic.Bool
+	healthy := plexSessionsServer(t, "Healthy Song", 0, nil)
+	// Answers last, so its session is the most recent and shown first
+	flaky := plexSessionsServer(t, "Flaky Song", 200*time.Millisecond, &failing)
+
+	app := newTestApp(&config.Config{
+		PollingInterval: 1,
+		Servers: []config.ServerConfig{
+			{Name: "Healthy", URL: healthy.URL, UserID: "1", Active: true},
+			{Name: "Flaky", URL: flaky.URL, UserID: "1", Active: true},
+		},
+	})
+	app.tokens = newFakeTokenStore("account-token")
+	bus := events.NewRecordingBus()
+	app.bus = bus
+	app.discord = &fakeDiscordPresence{connected: true}
+	app.plexRetry = retry.NewManager("Plex")
+	app.plexRetry.SetCallbacks(func() error { return nil }, func(retry.RetryState) {})
+	t.Cleanup(app.plexRetry.Stop)
+
+	if err := app.StartSessionPolling(); err != nil {
+		t.Fatalf("StartSessionPolling returned error: %v", err)
+	}
+	defer app.StopSessionPolling()
+
+	waitForTitle := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) {
+			app.sessionMu.RLock()
+			session := app.currentSession
+			app.sessionMu.RUnlock()
+			if session != nil && session.Title == want {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("timeout waiting for %q to be the current session", want)
+	}
+
+	waitForTitle("Flaky Song")
+	failing.Store(true)
+	// The healthy server keeps playing the same track, so it has nothing
+	// new to report: the failing server's session must still give way
+	waitForTitle("Healthy Song")
+
+	if n := bus.Count(events.PlaybackStopped); n != 0 {
+		t.Errorf("expected no PlaybackStopped while a server still plays, got %d", n)
+	}
+}
+
+func TestRetryFailingServers_RestartsOnlyTheFailingServer(t *testing.T) {
+	var failing atomic.Bool
+	healthy := plexSessionsServer(t, "Healthy Song", 0, nil)
+	flaky := plexSessionsServer(t, "Flaky Song", 0, &failing)
+	failing.Store(true)
+
+	app := newTestApp(&config.Config{
+		PollingInterval: 1,
+		Servers: []config.ServerConfig{
+			{Name: "Healthy", URL: healthy.URL, UserID: "1", Active: true},
+			{Name: "Flaky", URL: flaky.URL, UserID: "1", Active: true},
+		},
+	})
+	app.tokens = newFakeTokenStore("account-token")
+	app.plexFactory = newPlexClientFactory()
+	bus := events.NewRecordingBus()
+	app.bus = bus
+	app.discord = &fakeDiscordPresence{connected: true}
+	app.plexRetry = retry.NewManager("Plex")
+	app.plexRetry.SetCallbacks(func() error { return nil }, func(retry.RetryState) {})
+	t.Cleanup(app.plexRetry.Stop)
+
+	if err := app.StartSessionPolling(); err != nil {
+		t.Fatalf("StartSessionPolling returned error: %v", err)
+	}
+	defer app.StopSessionPolling()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !app.anyPollerInErrorState() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for the flaky server to fail")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	app.pollerMu.Lock()
+	healthyPoller, flakyPoller := app.pollers[0], app.pollers[1]
+	app.pollerMu.Unlock()
+
+	// The healthy server answering doesn't count as a recovery
+	if err := app.retryFailingServers(); err == nil {
+		t.Fatal("expected the retry to fail while the flaky server is down")
+	}
+
+	failing.Store(false)
+	if err := app.retryFailingServers(); err != nil {
+		t.Fatalf("expected the retry to succeed once the server is back, got %v", err)
+	}
+	app.pollerMu.Lock()
+	restarted := app.pollers
+	app.pollerMu.Unlock()
+	if restarted[0] != healthyPoller {
+		t.Error("expected the healthy server's poller to keep running untouched")
+	}
+	if restarted[1] == flakyPoller || !restarted[1].isRunning() || flakyPoller.isRunning() {
+		t.Error("expected the flaky server's poller to be replaced by a running one")
+	}
+	if n := bus.Count(events.PlexConnectionRestored); n != 1 {
+		t.Errorf("expected one PlexConnectionRestored, got %d", n)
+	}
+}
+
+func TestStartSessionPolling_PreparesServersWithoutHoldingLock(t *testing.T) {
+	// Every request but the sessions poll (notably the notifications
+	// probe) answers slowly
+	const slow = 400 * time.Millisecond
+	slowServer := func() *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/status/sessions" {
+				time.Sleep(slow)
+			}
+			http.NotFound(w, r)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	first, second := slowServer(), slowServer()
+
+	app := newTestApp(&config.Config{
+		PollingInterval: 1,
+		Servers: []config.ServerConfig{
+			{Name: "First", URL: first.URL, UserID: "1", Active: true},
+			{Name: "Second", URL: second.URL, UserID: "1", Active: true},
+		},
+	})
+	app.tokens = newFakeTokenStore("account-token")
+	app.bus = events.NewRecordingBus()
+	app.discord = &fakeDiscordPresence{connected: true}
+	app.plexRetry = retry.NewManager("Plex")
+	app.plexRetry.SetCallbacks(func() error { return nil }, func(retry.RetryState) {})
+	t.Cleanup(app.plexRetry.Stop)
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- app.StartSessionPolling() }()
+	defer app.StopSessionPolling()
+
+	// pollerMu stays available while the servers are being prepared
+	time.Sleep(slow / 4)
+	locked := time.Now()
+	app.anyPollerInErrorState()
+	if waited := time.Since(locked); waited > slow/4 {
+		t.Errorf("pollerMu was held for %v during startup", waited)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("StartSessionPolling returned error: %v", err)
+	}
+	// Both servers were prepared in parallel, not one after the other
+	if elapsed := time.Since(start); elapsed >= 2*slow {
+		t.Errorf("StartSessionPolling took %v, expected the servers to be prepared in parallel", elapsed)
+	}
+	app.pollerMu.Lock()
+	n := len(app.pollers)
+	app.pollerMu.Unlock()
+	if n != 2 {
+		t.Errorf("expected 2 pollers, got %d", n)
+	}
+}
+
+func TestSetSessionSelection_ValidatesAndSaves(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.SetSessionSelection(config.SessionSelection{PreferMedia: "hologram"}); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for an unknown media preference, got %v", errors.INVALID_INPUT, err)
+	}
+
+	want := config.SessionSelection{PreferredPlayer: "Plexamp", PreferPlaying: true, PreferMedia: "video"}
+	if err := a.SetSessionSelection(want); err != nil {
+		t.Fatalf("SetSessionSelection returned error: %v", err)
+	}
+	if got := a.GetSessionSelection(); got != want {
+		t.Errorf("GetSessionSelection() = %+v, want %+v", got, want)
+	}
+	if policy := a.selectionPolicy(); policy.PreferredPlayer != "Plexamp" || policy.PreferMedia != "video" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+	if got := a.GetAllSessions(); len(got) != 0 {
+		t.Errorf("expected no sessions while polling is stopped, got %d", len(got))
+	}
+}
+
+func TestSetAdaptivePolling_ClampsAndDefaults(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if got := a.GetAdaptivePolling(); got.Enabled || got.MaxSeconds != 30 {
+		t.Errorf("expected adaptive polling off with the default ceiling, got %+v", got)
+	}
+	if err := a.SetAdaptivePolling(true, 300); err != nil {
+		t.Fatalf("SetAdaptivePolling returned error: %v", err)
+	}
+	if got := a.GetAdaptivePolling(); !got.Enabled || got.MaxSeconds != 60 {
+		t.Errorf("expected the ceiling clamped to 60s, got %+v", got)
+	}
+}
+
+func TestSetLibraryClassification_IsPerServer(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.SetLibraryClassification("", config.LibraryClassification{Audiobooks: []string{"4"}}); err == nil {
+		t.Error("expected error without a server machine identifier")
+	}
+	if err := a.SetLibraryClassification("server-a", config.LibraryClassification{Audiobooks: []string{"4"}}); err != nil {
+		t.Fatalf("SetLibraryClassification returned error: %v", err)
+	}
+	if got := a.GetLibraryClassification("server-a"); len(got.Audiobooks) != 1 || got.Audiobooks[0] != "4" {
+		t.Errorf("GetLibraryClassification(server-a) = %+v", got)
+	}
+	if got := a.GetLibraryClassification("server-b"); len(got.Audiobooks) != 0 {
+		t.Errorf("server-b got server-a's libraries: %+v", got)
+	}
+
+	// Section 4 is an audiobook library on server A only
+	client := plex.NewClient("token", "http://127.0.0.1:1")
+	for _, tc := range []struct {
+		server config.ServerConfig
+		want   string
+	}{
+		{config.ServerConfig{MachineIdentifier: "server-a"}, plex.MediaTypeAudiobook},
+		{config.ServerConfig{MachineIdentifier: "server-b"}, plex.MediaTypeMusic},
+	} {
+		session := plex.MediaSession{MediaType: plex.MediaTypeMusic, LibrarySectionID: "4"}
+		a.newLibraryClassifier(client, tc.server).Classify(&session)
+		if session.MediaType != tc.want {
+			t.Errorf("%s: media type = %q, want %q", tc.server.MachineIdentifier, session.MediaType, tc.want)
+		}
+	}
+}
+
+func TestSessionFilter_LibraryRulesArePerServer(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SessionFilters = config.SessionFilters{
+		ExcludePlayers: []string{"Bedroom"},
+		Libraries: map[string]config.LibraryRules{
+			"server-a": {Exclude: []string{"4"}},
+		},
+	}
+	a := newTestApp(cfg)
+
+	entry := &plex.SessionEntry{LibrarySectionID: "4"}
+	if a.sessionFilter(config.ServerConfig{MachineIdentifier: "server-a"}).Allows(entry) {
+		t.Error("server A's library 4 should be excluded")
+	}
+	if !a.sessionFilter(config.ServerConfig{MachineIdentifier: "server-b"}).Allows(entry) {
+		t.Error("server B's library 4 is another library and should be allowed")
+	}
+
+	entry.Player.Title = "Bedroom"
+	if a.sessionFilter(config.ServerConfig{MachineIdentifier: "server-b"}).Allows(entry) {
+		t.Error("player rules should apply to every server")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+
+	"plexcord/internal/errors"
+	"plexcord/internal/events"
+	"plexcord/internal/plex"
+)
+
+// RateCurrentTrack rates the item being played from 1 to 5 stars; 0 clears
+// its rating. The new rating shows in presence right away.
+func (a *App) RateCurrentTrack(stars int) error {
+	if stars < 0 || stars > 5 {
+		return errors.New(errors.INVALID_INPUT, "rating must be between 0 and 5 stars")
+	}
+	session, client, err := a.currentLibraryItem()
+	if err != nil {
+		return err
+	}
+
+	// Plex rates out of 10; a negative rating clears it
+	rating := float64(stars * 2)
+	if stars == 0 {
+		rating = -1
+	}
+	if err := client.Rate(session.RatingKey, rating); err != nil {
+		log.Printf("ERROR: Failed to rate %s: %v", session.Title, err)
+		return err
+	}
+	log.Printf("Rated %s %d stars", session.Title, stars)
+
+	a.applyRating(session, max(rating, 0))
+	return nil
+}
+
+// MarkCurrentPlayed marks the item being played as played.
+func (a *App) MarkCurrentPlayed() error {
+	return a.markCurrent(true)
+}
+
+// MarkCurrentUnplayed marks the item being played as unplayed.
+func (a *App) MarkCurrentUnplayed() error {
+	return a.markCurrent(false)
+}
+
+// markCurrent scrobbles or unscrobbles the item being played.
+func (a *App) markCurrent(played bool) error {
+	session, client, err := a.currentLibraryItem()
+	if err != nil {
+		return err
+	}
+
+	mark, state := client.Unscrobble, "unplayed"
+	if played {
+		mark, state = client.Scrobble, "played"
+	}
+	if err := mark(session.RatingKey); err != nil {
+		log.Printf("ERROR: Failed to mark %s %s: %v", session.Title, state, err)
+		return err
+	}
+	log.Printf("Marked %s %s", session.Title, state)
+	return nil
+}
+
+// currentLibraryItem returns the current session and the client of the
+// server whose library it plays from.
+func (a *App) currentLibraryItem() (*plex.MediaSession, *plex.Client, error) {
+	a.sessionMu.RLock()
+	session := a.currentSession
+	a.sessionMu.RUnlock()
+	if session == nil {
+		return nil, nil, errors.New(errors.PLAYBACK_UNAVAILABLE, "nothing is playing")
+	}
+	if session.RatingKey == "" {
+		return nil, nil, errors.New(errors.PLAYBACK_UNAVAILABLE, "the current item isn't in a library")
+	}
+
+	client := a.clientForSession(session)
+	if client == nil {
+		return nil, nil, errors.New(errors.PLAYBACK_UNAVAILABLE, "the current session's server is no longer polled")
+	}
+	return session, client, nil
+}
+
+// applyRating records a rating just set on session's item so presence and
+// the frontend show it before the next poll reports it, unless another item
+// has started since.
+func (a *App) applyRating(session *plex.MediaSession, rating float64) {
+	a.sessionMu.Lock()
+	if a.currentSession == nil || a.currentSession.RatingKey != session.RatingKey {
+		a.sessionMu.Unlock()
+		return
+	}
+	updated := *a.currentSession
+	updated.UserRating = rating
+	// Same position as last reported: presence keeps its timestamps
+	updated.Seeked = false
+	a.currentSession = &updated
+	a.sessionMu.Unlock()
+
+	if a.discord != nil && !a.IsPresencePaused() && updated.State == "playing" {
+		a.updateDiscordFromSession(&updated)
+	}
+	if a.bus != nil {
+		a.bus.Emit(events.PlaybackUpdated, &updated)
+	}
+}
+
+// rateFromTray is the tray's rating callback.
+func (a *App) rateFromTray(stars int) {
+	if err := a.RateCurrentTrack(stars); err != nil {
+		log.Printf("Warning: Tray rating failed: %v", err)
+	}
+}
+
+// markPlayedFromTray is the tray's played/unplayed callback.
+func (a *App) markPlayedFromTray(played bool) {
+	if err := a.markCurrent(played); err != nil {
+		log.Printf("Warning: Tray mark as played failed: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"plexcord/internal/config"
+	"plexcord/internal/errors"
+	"plexcord/internal/plex"
+)
+
+func TestRateCurrentTrack_RequiresALibraryItem(t *testing.T) {
+	a := newTestApp(config.DefaultConfig())
+
+	if err := a.RateCurrentTrack(4); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s while nothing plays, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+	a.currentSession = &plex.MediaSession{SessionKey: "1", State: "playing"}
+	if err := a.MarkCurrentPlayed(); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s for an item outside a library, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+	a.currentSession.RatingKey = "5"
+	if err := a.RateCurrentTrack(6); errors.GetCode(err) != errors.INVALID_INPUT {
+		t.Errorf("expected %s for more than 5 stars, got %v", errors.INVALID_INPUT, err)
+	}
+	if err := a.MarkCurrentPlayed(); errors.GetCode(err) != errors.PLAYBACK_UNAVAILABLE {
+		t.Errorf("expected %s when the session's server isn't polled, got %v", errors.PLAYBACK_UNAVAILABLE, err)
+	}
+}
+
+func TestApplyRating_KeepsPresenceTimestamps(t *testing.T) {
+	fake := &fakeDiscordPresence{connected: true}
+	a := newTestApp(config.DefaultConfig())
+	a.discord = fake
+
+	session := &plex.MediaSession{
+		SessionKey: "1",
+		RatingKey:  "5",
+		MediaType:  plex.MediaTypeMusic,
+		State:      "playing",
+		Title:      "Hysteria",
+		Duration:   354000,
+		ViewOffset: 60000,
+		ObservedAt: time.Now().Add(-45 * time.Second),
+		Seeked:     true, // The last poll was a seek
+	}
+	a.currentSession = session
+	a.updateDiscordFromSession(session)
+	first := fake.lastData
+	if first == nil || first.StartTime == nil {
+		t.Fatal("expected a presence update with timestamps")
+	}
+
+	a.applyRating(session, 8)
+	if fake.updateCount != 2 || fake.lastData.Rating != 8 {
+		t.Fatalf("expected presence re-sent with the rating, got %d updates, rating %v", fake.updateCount, fake.lastData.Rating)
+	}
+	if !fake.lastData.StartTime.Equal(*first.StartTime) || !fake.lastData.EndTime.Equal(*first.EndTime) {
+		t.Errorf("rating moved the timestamps to %v-%v, want %v-%v",
+			fake.lastData.StartTime, fake.lastData.EndTime, first.StartTime, first.EndTime)
+	}
+}
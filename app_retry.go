@@ -1,6 +1,9 @@
 package main
 
 import (
+	"log"
+
+	"plexcord/internal/config"
 	"plexcord/internal/errors"
 	"plexcord/internal/events"
 	"plexcord/internal/retry"
@@ -12,19 +15,26 @@ func (a *App) setupRetryCallbacks() {
 	// Plex retry callback
 	a.plexRetry.SetCallbacks(
 		func() error {
-			// Nothing to connect to yet: no active server or no user
+			// Nothing to connect to yet: no active server with a user
 			// selected. Returning nil stops the retry loop so the dashboard
 			// settles on "Not Connected" instead of retrying a target that
 			// can never succeed (e.g. a server added via the Settings dialog
 			// before a user has been selected).
-			serverURL := a.activePlexServerURL()
-			if serverURL == "" || a.config.SelectedPlexUserID == "" {
+			servers := a.activePlexServers()
+			if len(servers) == 0 {
 				a.stopPlexRetry()
 				return nil
 			}
 
-			// Try to reconnect to the active Plex server.
-			if _, err := a.ValidatePlexConnection(serverURL); err != nil {
+			// While other servers poll, retry only the failing ones;
+			// otherwise start polling once any server answers.
+			var err error
+			if a.IsPollingActive() {
+				err = a.retryFailingServers()
+			} else if err = a.validatePlexServers(servers); err == nil {
+				return a.StartSessionPolling()
+			}
+			if err != nil {
 				// Auth/config problems won't be fixed by retrying — stop the
 				// loop rather than spin forever.
 				code := errors.GetCode(err)
@@ -34,8 +44,7 @@ func (a *App) setupRetryCallbacks() {
 				}
 				return err
 			}
-			// Also restart polling if it was running
-			return a.StartSessionPolling()
+			return nil
 		},
 		func(state retry.RetryState) {
 			// Emit retry state change event
@@ -56,6 +65,39 @@ func (a *App) setupRetryCallbacks() {
 	)
 }
 
+// retryFailingServers validates each server whose session source is failing
+// and restarts the source of those that answer again. It fails while any of
+// them is still unreachable, so the retry loop keeps going for it.
+func (a *App) retryFailingServers() error {
+	a.pollerMu.Lock()
+	var failing []config.ServerConfig
+	for _, sp := range a.pollers {
+		if sp.isInErrorState() {
+			failing = append(failing, sp.server)
+		}
+	}
+	a.pollerMu.Unlock()
+
+	var lastErr error
+	for _, server := range failing {
+		if _, err := a.ValidatePlexConnection(server.URL); err != nil {
+			lastErr = err
+			continue
+		}
+		if err := a.restartServerPoller(server); err != nil {
+			log.Printf("ERROR: Failed to restart session source of %s: %v", server.Name, err)
+			lastErr = err
+			continue
+		}
+		// The fresh source starts healthy and won't report the recovery
+		a.bus.Emit(events.PlexConnectionRestored, map[string]interface{}{
+			"serverUrl":  server.URL,
+			"serverName": server.Name,
+		})
+	}
+	return lastErr
+}
+
 // GetPlexRetryState returns the current Plex retry state.
 func (a *App) GetPlexRetryState() retry.RetryState {
 	return a.plexRetry.GetState()
@@ -74,7 +116,7 @@ func (a *App) GetDiscordRetryState() retry.RetryState {
 // the Reconnect button appeared completely unresponsive whenever Plex hadn't
 // been set up yet, since the retry callback would just stop the loop.
 func (a *App) RetryPlexConnection() error {
-	if a.activePlexServerURL() == "" || a.config.SelectedPlexUserID == "" {
+	if len(a.activePlexServers()) == 0 {
 		return errors.New(errors.PLEX_NOT_CONFIGURED, "No Plex server or user configured")
 	}
 	a.plexRetry.ManualRetry()
@@ -105,6 +147,11 @@ func (a *App) stopPlexRetry() {
 	a.plexRetry.Reset()
 }
 
+// startDiscordRetry begins automatic retry for a lost Discord connection.
+func (a *App) startDiscordRetry(err error) {
+	a.discordRetry.Start(err, errors.GetCode(err))
+}
+
 // stopDiscordRetry stops automatic Discord retry on success.
 func (a *App) stopDiscordRetry() {
 	a.discordRetry.Reset()
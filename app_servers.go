@@ -3,9 +3,12 @@ package main
 import (
 	"log"
 	"net/url"
+	"time"
 
 	"plexcord/internal/config"
 	"plexcord/internal/errors"
+	"plexcord/internal/events"
+	"plexcord/internal/plex"
 )
 
 // GetServers returns the list of configured Plex servers from config.
@@ -46,22 +49,269 @@ func (a *App) activePlexServerURL() string {
 	return a.config.ServerURL
 }
 
+// activePlexServers returns every server PlexCord should poll: each Active
+// entry in config.Servers, or the legacy single ServerURL when none are
+// active. Entries without their own UserID inherit the globally selected
+// Plex user; entries that still have no user are skipped since the poller
+// could never match a session for them.
+func (a *App) activePlexServers() []config.ServerConfig {
+	var servers []config.ServerConfig
+	for _, s := range a.config.Servers {
+		if s.Active && s.URL != "" {
+			servers = append(servers, s)
+		}
+	}
+	if len(servers) == 0 && a.config.ServerURL != "" {
+		servers = append(servers, config.ServerConfig{
+			Name:     "Plex",
+			URL:      a.config.ServerURL,
+			UserID:   a.config.SelectedPlexUserID,
+			UserName: a.config.SelectedPlexUserName,
+			Active:   true,
+		})
+	}
+
+	out := servers[:0]
+	for _, s := range servers {
+		if s.UserID == "" {
+			s.UserID = a.config.SelectedPlexUserID
+			s.UserName = a.config.SelectedPlexUserName
+		}
+		if s.UserID == "" {
+			log.Printf("Skipping server %s: no Plex user selected", s.URL)
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// validatePlexServers validates each server and succeeds if at least one is
+// reachable: polling can start with that one while the pollers of the others
+// report their own errors and recover independently. When every server
+// fails, the last error is returned.
+func (a *App) validatePlexServers(servers []config.ServerConfig) error {
+	var lastErr error
+	for _, server := range servers {
+		if _, err := a.ValidatePlexConnection(server.URL); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	return lastErr
+}
+
+// plexTokenFor returns the token to use for the server at serverURL: the
+// server's own access token when one was stored for it (shared servers added
+// through remote discovery), otherwise the account token.
+func (a *App) plexTokenFor(serverURL string) (string, error) {
+	for _, s := range a.config.Servers {
+		if s.URL != serverURL || s.MachineIdentifier == "" {
+			continue
+		}
+		token, err := a.tokens.GetServerToken(s.MachineIdentifier)
+		if err != nil {
+			log.Printf("Warning: Failed to retrieve token for server %s, using account token: %v", s.Name, err)
+			break
+		}
+		if token != "" {
+			return token, nil
+		}
+		break
+	}
+	return a.tokens.Get()
+}
+
+// serverConfigFor returns the configured server at serverURL, or a zero
+// value when the URL isn't in the server list (e.g. during setup).
+func (a *App) serverConfigFor(serverURL string) config.ServerConfig {
+	for _, s := range a.config.Servers {
+		if s.URL == serverURL {
+			return s
+		}
+	}
+	return config.ServerConfig{}
+}
+
+// rememberMachineIdentifier records the machine identifier of the server at
+// serverURL the first time it is validated. An identifier that's already
+// recorded is never overwritten: a different answer is a mismatch, which
+// the client reports before validation succeeds.
+func (a *App) rememberMachineIdentifier(serverURL, machineID string) {
+	if machineID == "" || a.cfgStore == nil {
+		return
+	}
+	var changed bool
+	if err := a.cfgStore.Update(func(c *config.Config) {
+		for i := range c.Servers {
+			if c.Servers[i].URL == serverURL && c.Servers[i].MachineIdentifier == "" {
+				c.Servers[i].MachineIdentifier = machineID
+				changed = true
+			}
+		}
+	}); err != nil {
+		log.Printf("Warning: Failed to save server machine identifier: %v", err)
+		return
+	}
+	if changed {
+		log.Printf("Recorded machine identifier %s for %s", machineID, serverURL)
+	}
+}
+
+// startServerRelocation re-scans the LAN in the background for a server that
+// stopped answering at its saved URL. At most one scan per server runs at a
+// time; servers without a machine identifier can't be recognised and are
+// skipped.
+func (a *App) startServerRelocation(server config.ServerConfig) {
+	if server.MachineIdentifier == "" {
+		return
+	}
+	a.relocateMu.Lock()
+	if a.relocating == nil {
+		a.relocating = make(map[string]bool)
+	}
+	if a.relocating[server.MachineIdentifier] {
+		a.relocateMu.Unlock()
+		return
+	}
+	a.relocating[server.MachineIdentifier] = true
+	a.relocateMu.Unlock()
+
+	go func() {
+		defer func() {
+			a.relocateMu.Lock()
+			delete(a.relocating, server.MachineIdentifier)
+			a.relocateMu.Unlock()
+		}()
+		if a.relocateServer(server) {
+			// Restart polling so the server's poller picks up the new URL
+			a.StopSessionPolling()
+			if err := a.StartSessionPolling(); err != nil {
+				log.Printf("Warning: Failed to restart polling after server relocation: %v", err)
+			}
+		}
+	}()
+}
+
+// relocateServer scans the LAN for the server's machine identifier and, if it
+// answers at a different address, updates its URL in the config. Returns
+// true when the URL changed.
+func (a *App) relocateServer(server config.ServerConfig) bool {
+	scan := a.scanLAN
+	if scan == nil {
+		scan = plex.DiscoverServers
+	}
+	found, err := scan(5 * time.Second)
+	if err != nil {
+		log.Printf("Warning: Re-scan for %s failed: %v", server.Name, err)
+		return false
+	}
+
+	newURL := ""
+	for i := range found {
+		if found[i].ID == server.MachineIdentifier {
+			newURL = found[i].URL()
+			break
+		}
+	}
+	if newURL == "" || newURL == server.URL {
+		return false
+	}
+
+	var moved bool
+	if err := a.cfgStore.Update(func(c *config.Config) {
+		for i := range c.Servers {
+			s := &c.Servers[i]
+			if s.MachineIdentifier != server.MachineIdentifier || s.URL != server.URL {
+				continue
+			}
+			// Don't collide with an entry that already uses the new URL
+			for _, other := range c.Servers {
+				if other.URL == newURL {
+					return
+				}
+			}
+			s.URL = newURL
+			moved = true
+		}
+		if moved && c.ServerURL == server.URL {
+			c.ServerURL = newURL
+		}
+	}); err != nil {
+		log.Printf("ERROR: Failed to save relocated server URL: %v", err)
+		return false
+	}
+	if !moved {
+		return false
+	}
+
+	log.Printf("Server %s moved from %s to %s", server.Name, server.URL, newURL)
+	a.bus.Emit(events.PlexServerRelocated, map[string]interface{}{
+		"serverName": server.Name,
+		"oldUrl":     server.URL,
+		"newUrl":     newURL,
+	})
+	return true
+}
+
 // AddServer appends a new server to the configuration. The URL must use
 // http or https and must be unique within the existing server list.
 // userID and userName are optional and may be filled in later via the
 // per-server user-selection flow.
+func (a *App) AddServer(name, serverURL, userID, userName string) error {
+	return a.addServer(config.ServerConfig{
+		Name:     name,
+		URL:      serverURL,
+		UserID:   userID,
+		UserName: userName,
+		Active:   true,
+	})
+}
+
+// AddResourceServer adds a server returned by DiscoverRemotePlexServers.
+// Its preferred connection URI becomes the server URL (the others are kept
+// as failover connections), its machine
+// identifier is recorded, and its access token is stored in the keychain so
+// shared servers (which reject the account token) can be polled. Servers the
+// account doesn't own are marked Shared so they are followed through
+// Companion timelines.
+func (a *App) AddResourceServer(server plex.Server, userID, userName string) error {
+	if server.AccessToken != "" {
+		if server.ID == "" {
+			return errors.New(errors.CONFIG_WRITE_FAILED, "server identifier is required to store its token")
+		}
+		if err := a.tokens.SetServerToken(server.ID, server.AccessToken); err != nil {
+			log.Printf("ERROR: Failed to store server token: %v", err)
+			return err
+		}
+	}
+
+	return a.addServer(config.ServerConfig{
+		Name:              server.Name,
+		URL:               server.URL(),
+		UserID:            userID,
+		UserName:          userName,
+		MachineIdentifier: server.ID,
+		Connections:       server.ConnectionURIs(),
+		Shared:            !server.Owned,
+		Active:            true,
+	})
+}
+
+// addServer validates and appends a server entry.
 //
 // The uniqueness check and the append happen inside a single
 // cfgStore.Update so two concurrent AddServer calls with the same URL
 // can't both pass the check and end up adding duplicates.
-func (a *App) AddServer(name, serverURL, userID, userName string) error {
-	if name == "" {
+func (a *App) addServer(server config.ServerConfig) error {
+	if server.Name == "" {
 		return errors.New(errors.CONFIG_WRITE_FAILED, "server name cannot be empty")
 	}
-	if serverURL == "" {
+	if server.URL == "" {
 		return errors.New(errors.CONFIG_WRITE_FAILED, "server URL cannot be empty")
 	}
-	parsed, err := url.Parse(serverURL)
+	parsed, err := url.Parse(server.URL)
 	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
 		return errors.New(errors.CONFIG_WRITE_FAILED, "server URL must use http or https scheme")
 	}
@@ -69,18 +319,12 @@ func (a *App) AddServer(name, serverURL, userID, userName string) error {
 	var dup bool
 	if err := a.cfgStore.Update(func(c *config.Config) {
 		for _, s := range c.Servers {
-			if s.URL == serverURL {
+			if s.URL == server.URL {
 				dup = true
 				return
 			}
 		}
-		c.Servers = append(c.Servers, config.ServerConfig{
-			Name:     name,
-			URL:      serverURL,
-			UserID:   userID,
-			UserName: userName,
-			Active:   true,
-		})
+		c.Servers = append(c.Servers, server)
 	}); err != nil {
 		log.Printf("ERROR: Failed to save server: %v", err)
 		return err
@@ -88,7 +332,7 @@ func (a *App) AddServer(name, serverURL, userID, userName string) error {
 	if dup {
 		return errors.New(errors.CONFIG_WRITE_FAILED, "server with this URL already exists")
 	}
-	log.Printf("Server added: %s (%s)", name, serverURL)
+	log.Printf("Server added: %s (%s)", server.Name, server.URL)
 	return nil
 }
 
@@ -100,9 +344,11 @@ func (a *App) RemoveServer(serverURL string) error {
 	}
 
 	var found bool
+	var removed config.ServerConfig
 	if err := a.cfgStore.Update(func(c *config.Config) {
 		for i, s := range c.Servers {
 			if s.URL == serverURL {
+				removed = s
 				c.Servers = append(c.Servers[:i], c.Servers[i+1:]...)
 				found = true
 				return
@@ -115,10 +361,66 @@ func (a *App) RemoveServer(serverURL string) error {
 	if !found {
 		return errors.New(errors.CONFIG_WRITE_FAILED, "server not found")
 	}
+	a.deleteServerTokenIfUnused(removed.MachineIdentifier)
 	log.Printf("Server removed: %s", serverURL)
 	return nil
 }
 
+// deleteServerTokenIfUnused removes a server's stored access token once no
+// remaining server entry (e.g. another URL for the same machine) needs it.
+func (a *App) deleteServerTokenIfUnused(machineID string) {
+	if machineID == "" {
+		return
+	}
+	for _, s := range a.config.Servers {
+		if s.MachineIdentifier == machineID {
+			return
+		}
+	}
+	if err := a.tokens.DeleteServerToken(machineID); err != nil {
+		log.Printf("Warning: Failed to delete server token: %v", err)
+	}
+}
+
+// SetServerConnections replaces the alternative connection URLs of the server
+// at serverURL (e.g. its LAN address plus a remote or plex.direct address).
+// Each must use http or https. Takes effect the next time polling starts.
+func (a *App) SetServerConnections(serverURL string, connections []string) error {
+	if serverURL == "" {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "server URL cannot be empty")
+	}
+	cleaned := make([]string, 0, len(connections))
+	for _, c := range connections {
+		if c == "" {
+			continue
+		}
+		parsed, err := url.Parse(c)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return errors.New(errors.CONFIG_WRITE_FAILED, "connection URL must use http or https scheme")
+		}
+		cleaned = append(cleaned, c)
+	}
+
+	var found bool
+	if err := a.cfgStore.Update(func(c *config.Config) {
+		for i := range c.Servers {
+			if c.Servers[i].URL == serverURL {
+				c.Servers[i].Connections = cleaned
+				found = true
+				return
+			}
+		}
+	}); err != nil {
+		log.Printf("ERROR: Failed to save server connections: %v", err)
+		return err
+	}
+	if !found {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "server not found")
+	}
+	log.Printf("Server %s connections updated (%d)", serverURL, len(cleaned))
+	return nil
+}
+
 // SetServerActive toggles a server's Active flag.
 // Returns an error if no server with that URL is configured.
 func (a *App) SetServerActive(serverURL string, active bool) error {
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"plexcord/internal/config"
+	"plexcord/internal/events"
+	"plexcord/internal/plex"
+	"plexcord/internal/retry"
 )
 
 // newTestApp builds an App backed by an in-memory config store that never
@@ -68,3 +75,207 @@ func TestAddServer_SurfacesThroughConnectionStatus(t *testing.T) {
 		t.Errorf("expected Connected=false with no user selected/poller running")
 	}
 }
+
+func TestActivePlexServers_ReturnsEveryActiveServerWithOwnUser(t *testing.T) {
+	app := newTestApp(&config.Config{
+		SelectedPlexUserID: "global",
+		Servers: []config.ServerConfig{
+			{Name: "Home", URL: "http://home:32400", UserID: "1", Active: true},
+			{Name: "Disabled", URL: "http://disabled:32400", UserID: "2", Active: false},
+			{Name: "Friend", URL: "http://friend:32400", Active: true},
+		},
+	})
+
+	servers := app.activePlexServers()
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 active servers, got %d", len(servers))
+	}
+	if servers[0].URL != "http://home:32400" || servers[0].UserID != "1" {
+		t.Errorf("expected home server with its own user, got %+v", servers[0])
+	}
+	if servers[1].URL != "http://friend:32400" || servers[1].UserID != "global" {
+		t.Errorf("expected friend server to inherit the selected user, got %+v", servers[1])
+	}
+}
+
+func TestActivePlexServers_LegacyFallbackAndMissingUser(t *testing.T) {
+	app := newTestApp(&config.Config{
+		ServerURL:          "http://legacy:32400",
+		SelectedPlexUserID: "1",
+	})
+	servers := app.activePlexServers()
+	if len(servers) != 1 || servers[0].URL != "http://legacy:32400" || servers[0].UserID != "1" {
+		t.Errorf("expected legacy server fallback, got %+v", servers)
+	}
+
+	// A server with no user anywhere can't be polled
+	app = newTestApp(&config.Config{
+		Servers: []config.ServerConfig{{Name: "NoUser", URL: "http://nouser:32400", Active: true}},
+	})
+	if servers := app.activePlexServers(); len(servers) != 0 {
+		t.Errorf("expected servers without a user to be skipped, got %+v", servers)
+	}
+}
+
+// fakeTokenStore is an in-memory TokenStore.
+type fakeTokenStore struct {
+	account string
+	servers map[string]string
+}
+
+func newFakeTokenStore(account string) *fakeTokenStore {
+	return &fakeTokenStore{account: account, servers: map[string]string{}}
+}
+
+func (f *fakeTokenStore) Get() (string, error)   { return f.account, nil }
+func (f *fakeTokenStore) Set(token string) error { f.account = token; return nil }
+func (f *fakeTokenStore) Delete() error          { f.account = ""; return nil }
+
+func (f *fakeTokenStore) GetServerToken(machineID string) (string, error) {
+	return f.servers[machineID], nil
+}
+
+func (f *fakeTokenStore) SetServerToken(machineID, token string) error {
+	f.servers[machineID] = token
+	return nil
+}
+
+func (f *fakeTokenStore) DeleteServerToken(machineID string) error {
+	delete(f.servers, machineID)
+	return nil
+}
+
+func TestAddResourceServer_StoresTokenAndPreferredURL(t *testing.T) {
+	app := newTestApp(&config.Config{})
+	tokens := newFakeTokenStore("account-token")
+	app.tokens = tokens
+
+	server := plex.Server{
+		ID:          "abc123",
+		Name:        "Friend's Server",
+		AccessToken: "shared-token",
+		Connections: []plex.ServerConnection{
+			{URI: "https://relay.plex.direct:8443", Relay: true},
+			{URI: "https://remote.plex.direct:32400"},
+		},
+	}
+	if err := app.AddResourceServer(server, "7", "me"); err != nil {
+		t.Fatalf("AddResourceServer returned error: %v", err)
+	}
+
+	servers := app.GetServers()
+	if len(servers) != 1 {
+		t.Fatalf("expected 1 server, got %d", len(servers))
+	}
+	if servers[0].URL != "https://remote.plex.direct:32400" || servers[0].MachineIdentifier != "abc123" {
+		t.Errorf("unexpected server entry: %+v", servers[0])
+	}
+	if !servers[0].Shared {
+		t.Error("expected a server the account doesn't own to be marked shared")
+	}
+
+	token, err := app.plexTokenFor("https://remote.plex.direct:32400")
+	if err != nil || token != "shared-token" {
+		t.Errorf("expected per-server token, got %q, %v", token, err)
+	}
+	// Servers without a stored token use the account token
+	if token, _ := app.plexTokenFor("http://other:32400"); token != "account-token" {
+		t.Errorf("expected account token fallback, got %q", token)
+	}
+
+	if err := app.RemoveServer("https://remote.plex.direct:32400"); err != nil {
+		t.Fatalf("RemoveServer returned error: %v", err)
+	}
+	if _, ok := tokens.servers["abc123"]; ok {
+		t.Error("expected server token to be deleted with the server")
+	}
+}
+
+func TestValidatePlexConnection_RemembersMachineIdentifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<MediaContainer machineIdentifier="machine-1" size="1"/>`))
+	}))
+	defer server.Close()
+
+	app := newTestApp(&config.Config{
+		Servers: []config.ServerConfig{{Name: "Home", URL: server.URL, Active: true}},
+	})
+	app.tokens = newFakeTokenStore("account-token")
+	app.plexFactory = newPlexClientFactory()
+	app.plexRetry = retry.NewManager("Plex")
+
+	if _, err := app.ValidatePlexConnection(server.URL); err != nil {
+		t.Fatalf("ValidatePlexConnection returned error: %v", err)
+	}
+	if got := app.GetServers()[0].MachineIdentifier; got != "machine-1" {
+		t.Errorf("expected machine identifier to be recorded, got %q", got)
+	}
+}
+
+func TestRelocateServer_UpdatesURLByMachineIdentifier(t *testing.T) {
+	app := newTestApp(&config.Config{
+		ServerURL: "http://192.168.1.10:32400",
+		Servers: []config.ServerConfig{
+			{Name: "Home", URL: "http://192.168.1.10:32400", MachineIdentifier: "machine-1", Active: true},
+		},
+	})
+	bus := events.NewRecordingBus()
+	app.bus = bus
+	app.scanLAN = func(time.Duration) ([]plex.Server, error) {
+		return []plex.Server{
+			{ID: "other", Address: "192.168.1.10", Port: "32400"},
+			{ID: "machine-1", Address: "192.168.1.42", Port: "32400"},
+		}, nil
+	}
+
+	if !app.relocateServer(app.GetServers()[0]) {
+		t.Fatal("expected server to be relocated")
+	}
+	if got := app.GetServers()[0].URL; got != "http://192.168.1.42:32400" {
+		t.Errorf("expected URL to follow the machine identifier, got %q", got)
+	}
+	if app.config.ServerURL != "http://192.168.1.42:32400" {
+		t.Errorf("expected legacy ServerURL to follow too, got %q", app.config.ServerURL)
+	}
+	if bus.Count(events.PlexServerRelocated) != 1 {
+		t.Errorf("expected one %s event", events.PlexServerRelocated)
+	}
+
+	// Not found on the LAN: nothing changes
+	app.scanLAN = func(time.Duration) ([]plex.Server, error) { return nil, nil }
+	if app.relocateServer(app.GetServers()[0]) {
+		t.Error("expected no relocation when the server isn't found")
+	}
+}
+
+func TestStartSessionPolling_FollowsSharedServerThroughCompanion(t *testing.T) {
+	app := newTestApp(&config.Config{
+		Servers: []config.ServerConfig{
+			{Name: "Friend", URL: "http://127.0.0.1:1", UserID: "7", MachineIdentifier: "abc123", Shared: true, Active: true},
+		},
+	})
+	app.tokens = newFakeTokenStore("account-token")
+	app.bus = events.NewRecordingBus()
+
+	var gotToken string
+	app.discoverPlayers = func(_ context.Context, token string) ([]plex.CompanionPlayer, error) {
+		gotToken = token
+		return nil, nil
+	}
+
+	if err := app.StartSessionPolling(); err != nil {
+		t.Fatalf("StartSessionPolling returned error: %v", err)
+	}
+	defer app.StopSessionPolling()
+
+	if gotToken != "account-token" {
+		t.Errorf("expected players to be discovered with the account token, got %q", gotToken)
+	}
+	if len(app.pollers) != 1 || app.pollers[0].companion == nil || app.pollers[0].poller != nil {
+		t.Fatalf("expected a companion source for the shared server, got %+v", app.pollers)
+	}
+	status := app.GetPlexConnectionStatus()
+	if len(status.Servers) != 1 || !status.Servers[0].Polling {
+		t.Errorf("expected the shared server to report as polling, got %+v", status.Servers)
+	}
+}
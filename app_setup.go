@@ -110,7 +110,15 @@ func (a *App) ResetApplication() {
 	}
 	a.discordMu.Unlock()
 
-	// 3. Remove Plex token from secure storage
+	// 3. Remove Plex tokens (account and per-server) from secure storage
+	for _, server := range a.config.Servers {
+		if server.MachineIdentifier == "" {
+			continue
+		}
+		if err := a.tokens.DeleteServerToken(server.MachineIdentifier); err != nil {
+			log.Printf("Warning: Failed to delete token for server %s: %v", server.Name, err)
+		}
+	}
 	if err := a.tokens.Delete(); err != nil {
 		log.Printf("Warning: Failed to delete Plex token: %v", err)
 		// Continue with reset - token deletion failure is not critical
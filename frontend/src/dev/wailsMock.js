@@ -43,7 +43,8 @@ export function installWailsMock() {
             ? null
             : {
                   sessionKey: 'mock-1',
-                  track: 'Midnight City',
+                  mediaType: 'music',
+                  title: 'Midnight City',
                   artist: 'M83',
                   album: 'Hurry Up, We’re Dreaming',
                   thumb: '/library/thumb/mock',
@@ -75,6 +76,7 @@ export function installWailsMock() {
         pollingInterval: 5,
         presenceFormat: { detailsFormat: '{track}', stateFormat: 'by {artist}' },
         discordClientId: '',
+        discordBroadcast: false,
         servers: empty ? [] : [{ name: 'Home Server', url: 'http://192.168.1.10:32400', userId: '1', userName: 'demo-user', active: true }]
     };
 
@@ -123,6 +125,7 @@ export function installWailsMock() {
             polling: !empty && !plexError,
             inErrorState: plexError,
             serverUrl: empty ? '' : 'http://192.168.1.10:32400',
+            servers: empty ? [] : [{ url: 'http://192.168.1.10:32400', name: 'Demo Server', inErrorState: plexError }],
             userId: empty ? '' : '1',
             userName: empty ? '' : 'demo-user'
         }),
@@ -181,6 +184,17 @@ export function installWailsMock() {
         SaveDiscordClientID: (id) => {
             state.discordClientId = id;
         },
+        GetDiscordBroadcast: () => state.discordBroadcast,
+        SetDiscordBroadcast: (enabled) => {
+            state.discordBroadcast = enabled;
+        },
+        // Like the backend: the chosen client, or every client found in broadcast mode
+        GetDiscordTargets: () => {
+            if (empty) return [];
+            const desktop = { path: '/run/user/1000/discord-ipc-0', release: 'stable', username: 'demo-user', connected: true };
+            if (!state.discordBroadcast) return [desktop];
+            return [desktop, { path: '/run/user/1000/discord-ipc-1', release: '', connected: true }];
+        },
         // Backend validates a Discord snowflake (17–20 digits); '' = default
         ValidateDiscordClientID: (id) => {
             if (id && !/^\d{17,20}$/.test(id)) {
@@ -195,6 +209,14 @@ export function installWailsMock() {
             }
             state.servers.push({ name, url, userId: userId || '', userName: userName || '', active: true });
         },
+        AddResourceServer: (server, userId, userName) => {
+            const conns = server.connections || [];
+            const url = (conns.find((c) => c.local) || conns[0])?.uri || `http://${server.address}:${server.port}`;
+            if (state.servers.some((s) => s.url === url)) {
+                throw new Error('a server with this URL already exists');
+            }
+            state.servers.push({ name: server.name, url, userId: userId || '', userName: userName || '', machineIdentifier: server.id, shared: !server.owned, active: true });
+        },
         RemoveServer: (url) => {
             state.servers = state.servers.filter((s) => s.url !== url);
         },
@@ -224,6 +246,34 @@ export function installWailsMock() {
                       { id: 'srv-1', name: 'Home Server', address: '192.168.1.10', port: '32400', version: '1.40.0.7998', isLocal: true },
                       { id: 'srv-2', name: 'Remote NAS', address: 'plex.example.com', port: '32400', version: '1.40.0.7998', isLocal: false }
                   ],
+        DiscoverRemotePlexServers: () =>
+            empty
+                ? []
+                : [
+                      {
+                          id: 'srv-1',
+                          name: 'Home Server',
+                          address: '192.168.1.10',
+                          port: '32400',
+                          version: '1.40.0.7998',
+                          accessToken: 'mock-server-token',
+                          owned: true,
+                          isLocal: true,
+                          connections: [{ uri: 'https://192-168-1-10.abc.plex.direct:32400', protocol: 'https', address: '192.168.1.10', port: 32400, local: true, relay: false }]
+                      },
+                      {
+                          id: 'srv-3',
+                          name: "Friend's Server",
+                          address: '203.0.113.7',
+                          port: '32400',
+                          version: '1.40.0.7998',
+                          accessToken: 'mock-shared-token',
+                          owner: 'friend',
+                          owned: false,
+                          isLocal: false,
+                          connections: [{ uri: 'https://203-0-113-7.def.plex.direct:32400', protocol: 'https', address: '203.0.113.7', port: 32400, local: false, relay: false }]
+                      }
+                  ],
         // PIN auth: authorizes on the second status poll (~4s), like a user
         // completing the code at plex.tv/link.
         StartPlexPINAuth: () => {
@@ -16,6 +16,9 @@ vi.mock('../../../wailsjs/go/main/App', () => ({
   GetDiscordRetryState: vi.fn().mockResolvedValue(null),
   RetryDiscordConnection: vi.fn().mockResolvedValue(undefined),
   ConnectDiscord: vi.fn().mockResolvedValue(undefined),
+  GetDiscordTargets: vi.fn().mockResolvedValue([]),
+  GetDiscordBroadcast: vi.fn().mockResolvedValue(false),
+  SetDiscordBroadcast: vi.fn().mockResolvedValue(undefined),
   GetErrorInfo: vi.fn().mockResolvedValue({
     code: 'DISCORD_NOT_RUNNING',
     title: 'Discord Not Running',
@@ -33,7 +36,10 @@ import {
   GetDiscordRetryState,
   RetryDiscordConnection,
   ConnectDiscord,
-  GetErrorInfo
+  GetErrorInfo,
+  GetDiscordTargets,
+  GetDiscordBroadcast,
+  SetDiscordBroadcast
 } from '../../../wailsjs/go/main/App'
 
 describe('discordConnection store', () => {
@@ -50,6 +56,8 @@ describe('discordConnection store', () => {
       expect(store.connected).toBe(false)
       expect(store.lastConnected).toBeNull()
       expect(store.retryState).toBeNull()
+      expect(store.targets).toEqual([])
+      expect(store.broadcast).toBe(false)
       expect(store.error).toBeNull()
       expect(store.loading).toBe(false)
       expect(store.initialized).toBe(false)
@@ -135,10 +143,11 @@ describe('discordConnection store', () => {
       it('registers event listeners for Discord events', () => {
         store.setupEventListeners()
 
-        expect(EventsOn).toHaveBeenCalledTimes(3)
+        expect(EventsOn).toHaveBeenCalledTimes(4)
         expect(EventsOn).toHaveBeenCalledWith('DiscordConnected', expect.any(Function))
         expect(EventsOn).toHaveBeenCalledWith('DiscordDisconnected', expect.any(Function))
         expect(EventsOn).toHaveBeenCalledWith('DiscordRetryState', expect.any(Function))
+        expect(EventsOn).toHaveBeenCalledWith('DiscordTargetsUpdated', expect.any(Function))
       })
     })
 
@@ -150,6 +159,7 @@ describe('discordConnection store', () => {
         expect(EventsOff).toHaveBeenCalledWith('DiscordConnected')
         expect(EventsOff).toHaveBeenCalledWith('DiscordDisconnected')
         expect(EventsOff).toHaveBeenCalledWith('DiscordRetryState')
+        expect(EventsOff).toHaveBeenCalledWith('DiscordTargetsUpdated')
         expect(store.initialized).toBe(false)
       })
     })
@@ -161,12 +171,16 @@ describe('discordConnection store', () => {
           discordLastConnected: '2026-01-01T00:00:00Z'
         })
         GetDiscordRetryState.mockResolvedValue({ isRetrying: false, attemptNumber: 0 })
+        GetDiscordBroadcast.mockResolvedValue(true)
+        GetDiscordTargets.mockResolvedValue([{ path: '/run/discord-ipc-0', release: 'stable', connected: true }])
 
         await store.refreshStatus()
 
         expect(store.connected).toBe(true)
         expect(store.lastConnected).toBe('2026-01-01T00:00:00Z')
         expect(store.retryState).toEqual({ isRetrying: false, attemptNumber: 0 })
+        expect(store.broadcast).toBe(true)
+        expect(store.targets).toHaveLength(1)
       })
 
       it('handles errors gracefully', async () => {
@@ -180,6 +194,25 @@ describe('discordConnection store', () => {
       })
     })
 
+    describe('setBroadcast', () => {
+      it('saves the mode and refreshes the targets', async () => {
+        GetDiscordTargets.mockResolvedValue([{ path: 'a', connected: true }, { path: 'b', connected: true }])
+
+        await store.setBroadcast(true)
+
+        expect(SetDiscordBroadcast).toHaveBeenCalledWith(true)
+        expect(store.broadcast).toBe(true)
+        expect(store.targets).toHaveLength(2)
+      })
+
+      it('reverts and re-throws on failure', async () => {
+        SetDiscordBroadcast.mockRejectedValueOnce(new Error('save failed'))
+
+        await expect(store.setBroadcast(true)).rejects.toThrow('save failed')
+        expect(store.broadcast).toBe(false)
+      })
+    })
+
     describe('retry', () => {
       it('calls RetryDiscordConnection and manages loading state', async () => {
         RetryDiscordConnection.mockResolvedValue(undefined)
@@ -272,11 +305,11 @@ describe('discordConnection store', () => {
 
         await store.initialize()
         expect(store.initialized).toBe(true)
-        expect(EventsOn).toHaveBeenCalledTimes(3)
+        expect(EventsOn).toHaveBeenCalledTimes(4)
 
         // Second call should be a no-op
         await store.initialize()
-        expect(EventsOn).toHaveBeenCalledTimes(3)
+        expect(EventsOn).toHaveBeenCalledTimes(4)
 
         consoleSpy.mockRestore()
       })
@@ -332,6 +365,16 @@ describe('discordConnection store', () => {
 
         expect(store.retryState).toEqual(state)
       })
+
+      it('DiscordTargetsUpdated replaces the targets', () => {
+        const targets = [
+          { path: 'a', connected: true },
+          { path: 'b', connected: false, error: { code: 'DISCORD_NOT_RUNNING', message: 'lost' } }
+        ]
+        eventHandlers['DiscordTargetsUpdated'](targets)
+
+        expect(store.targets).toEqual(targets)
+      })
     })
   })
 })
@@ -55,6 +55,8 @@ describe('plexConnection store', () => {
       expect(store.connected).toBe(false)
       expect(store.polling).toBe(false)
       expect(store.inErrorState).toBe(false)
+      expect(store.servers).toEqual([])
+      expect(store.serverErrors).toEqual({})
       expect(store.serverUrl).toBe('')
       expect(store.userId).toBe('')
       expect(store.userName).toBe('')
@@ -203,6 +205,26 @@ describe('plexConnection store', () => {
         expect(store.retryState).toEqual({ isRetrying: false, attemptNumber: 0 })
       })
 
+      it('tracks which servers are failing', async () => {
+        GetPlexConnectionStatus.mockResolvedValue({
+          connected: true,
+          polling: true,
+          inErrorState: false,
+          servers: [
+            { url: 'http://a:32400', inErrorState: true },
+            { url: 'http://b:32400', inErrorState: false }
+          ]
+        })
+        GetConnectionHistory.mockResolvedValue({})
+        GetPlexRetryState.mockResolvedValue(null)
+
+        await store.refreshStatus()
+
+        expect(store.servers).toHaveLength(2)
+        expect(store.serverErrors).toEqual({ 'http://a:32400': 'PLEX_UNREACHABLE' })
+        expect(store.inErrorState).toBe(false)
+      })
+
       it('handles errors gracefully', async () => {
         GetPlexConnectionStatus.mockRejectedValue(new Error('fetch failed'))
         const consoleSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
@@ -367,6 +389,36 @@ describe('plexConnection store', () => {
         expect(GetErrorInfo).toHaveBeenCalledWith('PLEX_UNREACHABLE')
       })
 
+      it('PlexConnectionError on one of several servers keeps the connection up', async () => {
+        store.servers = [{ url: 'http://a:32400' }, { url: 'http://b:32400' }]
+
+        await eventHandlers['PlexConnectionError']({ serverUrl: 'http://a:32400', errorCode: 'PLEX_UNREACHABLE' })
+
+        expect(store.serverErrors).toEqual({ 'http://a:32400': 'PLEX_UNREACHABLE' })
+        expect(store.connected).toBe(true)
+        expect(store.inErrorState).toBe(false)
+        expect(GetErrorInfo).not.toHaveBeenCalled()
+
+        await eventHandlers['PlexConnectionError']({ serverUrl: 'http://b:32400', errorCode: 'PLEX_UNREACHABLE' })
+
+        expect(store.connected).toBe(false)
+        expect(store.inErrorState).toBe(true)
+        expect(GetErrorInfo).toHaveBeenCalledWith('PLEX_UNREACHABLE')
+      })
+
+      it('PlexConnectionRestored only clears the restored server', async () => {
+        store.servers = [{ url: 'http://a:32400' }, { url: 'http://b:32400' }]
+        await eventHandlers['PlexConnectionError']({ serverUrl: 'http://a:32400', errorCode: 'PLEX_UNREACHABLE' })
+        await eventHandlers['PlexConnectionError']({ serverUrl: 'http://b:32400', errorCode: 'PLEX_TIMEOUT' })
+
+        eventHandlers['PlexConnectionRestored']({ serverUrl: 'http://a:32400' })
+
+        expect(store.serverErrors).toEqual({ 'http://b:32400': 'PLEX_TIMEOUT' })
+        expect(store.connected).toBe(true)
+        expect(store.inErrorState).toBe(false)
+        expect(store.error).toBeNull()
+      })
+
       it('PlexRetryState updates retry state', () => {
         const state = { isRetrying: true, attemptNumber: 3 }
         eventHandlers['PlexRetryState'](state)
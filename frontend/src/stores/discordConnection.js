@@ -1,6 +1,6 @@
 import { defineStore } from 'pinia';
 import { EventsOn, EventsOff } from '../../wailsjs/runtime/runtime';
-import { IsDiscordConnected, GetConnectionHistory, GetDiscordRetryState, RetryDiscordConnection, ConnectDiscord, GetErrorInfo } from '../../wailsjs/go/main/App';
+import { IsDiscordConnected, GetConnectionHistory, GetDiscordRetryState, RetryDiscordConnection, ConnectDiscord, GetErrorInfo, GetDiscordTargets, GetDiscordBroadcast, SetDiscordBroadcast } from '../../wailsjs/go/main/App';
 import { formatRelativeTime } from '../utils/timeUtils';
 import { t } from '@/i18n';
 
@@ -14,6 +14,8 @@ export const useDiscordConnectionStore = defineStore('discordConnection', {
         connected: false,
         lastConnected: null,
         retryState: null,
+        targets: [],
+        broadcast: false,
         error: null,
         loading: false,
         initialized: false
@@ -88,6 +90,7 @@ export const useDiscordConnectionStore = defineStore('discordConnection', {
                 this.connected = true;
                 this.error = null;
                 this.lastConnected = new Date().toISOString();
+                this.refreshTargets();
             });
 
             EventsOn('DiscordDisconnected', async (data) => {
@@ -98,11 +101,18 @@ export const useDiscordConnectionStore = defineStore('discordConnection', {
                 // nested under `error`, not on the top-level payload.
                 const errorCode = data?.error?.code || data?.code || 'DISCORD_NOT_RUNNING';
                 await this.setError(errorCode);
+                await this.refreshTargets();
             });
 
             EventsOn('DiscordRetryState', (state) => {
                 this.retryState = state;
             });
+
+            // In broadcast mode, one of several Discord clients dropped,
+            // failed an update or was picked up
+            EventsOn('DiscordTargetsUpdated', (targets) => {
+                this.targets = targets || [];
+            });
         },
 
         /**
@@ -112,6 +122,7 @@ export const useDiscordConnectionStore = defineStore('discordConnection', {
             EventsOff('DiscordConnected');
             EventsOff('DiscordDisconnected');
             EventsOff('DiscordRetryState');
+            EventsOff('DiscordTargetsUpdated');
             this.initialized = false;
         },
 
@@ -126,11 +137,41 @@ export const useDiscordConnectionStore = defineStore('discordConnection', {
                 this.lastConnected = history.discordLastConnected;
 
                 this.retryState = await GetDiscordRetryState();
+                this.broadcast = await GetDiscordBroadcast();
+                this.targets = (await GetDiscordTargets()) || [];
             } catch (error) {
                 console.error('Failed to refresh Discord status:', error);
             }
         },
 
+        /**
+         * Refresh the Discord clients presence goes to
+         */
+        async refreshTargets() {
+            try {
+                this.targets = (await GetDiscordTargets()) || [];
+            } catch (error) {
+                console.error('Failed to refresh Discord targets:', error);
+            }
+        },
+
+        /**
+         * Turn broadcast mode on or off: presence goes to every Discord
+         * client found instead of one. Reverts on failure.
+         */
+        async setBroadcast(enabled) {
+            const previous = this.broadcast;
+            this.broadcast = enabled;
+            try {
+                await SetDiscordBroadcast(enabled);
+            } catch (error) {
+                this.broadcast = previous;
+                throw error;
+            } finally {
+                await this.refreshTargets();
+            }
+        },
+
         /**
          * Manually retry connection
          */
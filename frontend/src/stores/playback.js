@@ -132,8 +132,8 @@ export const usePlaybackStore = defineStore('playback', {
         },
 
         /**
-         * Set the current track from a MusicSession event
-         * @param {Object} session - MusicSession object from backend
+         * Set the current track from a MediaSession event
+         * @param {Object} session - MediaSession object from backend
          */
         setTrack(session) {
             if (!session) {
@@ -143,9 +143,14 @@ export const usePlaybackStore = defineStore('playback', {
 
             this.currentTrack = {
                 sessionKey: session.sessionKey,
-                track: session.track,
+                mediaType: session.mediaType ?? 'music',
+                // MediaSession carries the title of any media type in `title`
+                track: session.title ?? session.track,
                 artist: session.artist,
                 album: session.album,
+                showTitle: session.showTitle,
+                season: session.season,
+                episode: session.episode,
                 thumb: session.thumb,
                 thumbUrl: session.thumbUrl,
                 duration: session.duration,
@@ -14,6 +14,10 @@ export const usePlexConnectionStore = defineStore('plexConnection', {
         connected: false,
         polling: false,
         inErrorState: false,
+        // Polled servers (GetPlexConnectionStatus().servers) and the error
+        // code of each one currently failing, keyed by server URL
+        servers: [],
+        serverErrors: {},
         serverUrl: '',
         userId: '',
         userName: '',
@@ -96,24 +100,34 @@ export const usePlexConnectionStore = defineStore('plexConnection', {
          * Setup Wails event listeners for Plex
          */
         setupEventListeners() {
+            // Both events are emitted per server (serverUrl): one server
+            // failing while another still answers is not a lost connection.
             EventsOn('PlexConnectionError', async (data) => {
-                this.connected = false;
-                this.inErrorState = true;
                 console.log('Plex connection error:', data);
 
                 const errorCode = data?.errorCode || data?.code || 'PLEX_UNREACHABLE';
-                await this.setError(errorCode);
+                this.serverErrors = { ...this.serverErrors, [data?.serverUrl || '']: errorCode };
+                this.syncErrorState();
+                if (this.inErrorState) {
+                    await this.setError(errorCode);
+                }
             });
 
-            EventsOn('PlexConnectionRestored', () => {
-                this.connected = true;
+            EventsOn('PlexConnectionRestored', (data) => {
+                const serverErrors = { ...this.serverErrors };
+                if (data?.serverUrl) {
+                    delete serverErrors[data.serverUrl];
+                    delete serverErrors[''];
+                } else {
+                    Object.keys(serverErrors).forEach((url) => delete serverErrors[url]);
+                }
+                this.serverErrors = serverErrors;
+                this.syncErrorState();
                 // The poller has resumed, so polling is live again. Without
                 // this the tile stays "Idle" (healthy = connected && polling)
                 // even after a successful recovery.
                 this.polling = true;
-                this.inErrorState = false;
                 this.lastConnected = new Date().toISOString();
-                this.clearError();
             });
 
             EventsOn('PlexRetryState', (state) => {
@@ -121,6 +135,21 @@ export const usePlexConnectionStore = defineStore('plexConnection', {
             });
         },
 
+        /**
+         * Derive the overall state from the per-server errors the way the
+         * backend does: in error only once every polled server fails,
+         * connected while any of them answers.
+         */
+        syncErrorState() {
+            const urls = new Set([...this.servers.map((server) => server.url), ...Object.keys(this.serverErrors)]);
+            const failing = [...urls].filter((url) => this.serverErrors[url]).length;
+            this.inErrorState = urls.size > 0 && failing === urls.size;
+            this.connected = failing < urls.size || urls.size === 0;
+            if (!this.inErrorState) {
+                this.clearError();
+            }
+        },
+
         /**
          * Mark the connection as live from observed poll activity.
          *
@@ -162,6 +191,9 @@ export const usePlexConnectionStore = defineStore('plexConnection', {
                 this.connected = status.connected;
                 this.polling = status.polling;
                 this.inErrorState = status.inErrorState;
+                this.servers = status.servers || [];
+                // Keep the codes already reported for servers still failing
+                this.serverErrors = Object.fromEntries(this.servers.filter((server) => server.inErrorState).map((server) => [server.url, this.serverErrors[server.url] || 'PLEX_UNREACHABLE']));
                 this.serverUrl = status.serverUrl;
                 this.userId = status.userId;
                 this.userName = status.userName;
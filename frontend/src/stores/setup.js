@@ -1,5 +1,6 @@
 import { defineStore } from 'pinia';
 import { SavePlexToken, IsDiscordConnected, ConnectDiscord, StartSessionPolling, CompleteSetup } from '../../wailsjs/go/main/App';
+import { plexServerUrl } from '@/utils/plexUrl';
 
 /**
  * Setup Wizard Store
@@ -188,7 +189,7 @@ export const useSetupStore = defineStore('setup', {
             this.selectedServer = server;
             // Also set the server URL for config
             if (server) {
-                this.plexServerUrl = `http://${server.address}:${server.port}`;
+                this.plexServerUrl = plexServerUrl(server);
                 this.isManualEntry = false;
             }
             this.saveState();
@@ -1,5 +1,5 @@
 import { describe, it, expect } from 'vitest';
-import { validatePlexServerUrl, PLEX_URL_PLACEHOLDER } from '../plexUrl';
+import { validatePlexServerUrl, plexServerUrl, PLEX_URL_PLACEHOLDER } from '../plexUrl';
 
 describe('validatePlexServerUrl', () => {
     it('rejects empty or whitespace-only input', () => {
@@ -43,3 +43,19 @@ describe('validatePlexServerUrl', () => {
         expect(validatePlexServerUrl('  http://192.168.1.100:32400  ').valid).toBe(true);
     });
 });
+
+describe('plexServerUrl', () => {
+    it('builds the URL from address and port for GDM results', () => {
+        expect(plexServerUrl({ address: '192.168.1.10', port: '32400' })).toBe('http://192.168.1.10:32400');
+    });
+
+    it('prefers a local connection, then remote, then relay', () => {
+        const relay = { uri: 'https://relay.plex.direct:8443', local: false, relay: true };
+        const remote = { uri: 'https://1-2-3-4.abc.plex.direct:32400', local: false, relay: false };
+        const local = { uri: 'https://10-0-0-5.abc.plex.direct:32400', local: true, relay: false };
+
+        expect(plexServerUrl({ connections: [relay, remote, local] })).toBe(local.uri);
+        expect(plexServerUrl({ connections: [relay, remote] })).toBe(remote.uri);
+        expect(plexServerUrl({ connections: [relay] })).toBe(relay.uri);
+    });
+});
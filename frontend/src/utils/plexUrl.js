@@ -52,3 +52,21 @@ export function validatePlexServerUrl(url) {
         return { valid: false, error: t('validation.urlFormat') };
     }
 }
+
+// Lower is better: a direct LAN connection beats a direct remote one, and
+// the plex.tv relay is only used as a last resort (mirrors plex.Server.URL).
+const connectionRank = (conn) => (conn.relay ? 2 : conn.local ? 0 : 1);
+
+/**
+ * Base URL to reach a discovered server.
+ *
+ * Servers found through the Plex account carry their advertised
+ * connections; GDM results only have an address and port.
+ *
+ * @param {{ address?: string, port?: string, connections?: Array<{ uri: string, local: boolean, relay: boolean }> }} server
+ * @returns {string}
+ */
+export function plexServerUrl(server) {
+    const best = (server.connections || []).filter((c) => c.uri).reduce((a, c) => (!a || connectionRank(c) < connectionRank(a) ? c : a), null);
+    return best ? best.uri : `http://${server.address}:${server.port}`;
+}
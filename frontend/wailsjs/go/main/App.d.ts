@@ -8,6 +8,10 @@ import {errors} from '../models';
 import {history} from '../models';
 import {config} from '../models';
 import {updater} from '../models';
+import {ipc} from '../models';
+import {discord} from '../models';
+
+export function AddResourceServer(arg1:plex.Server,arg2:string,arg3:string):Promise<void>;
 
 export function AddServer(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
 
@@ -31,30 +35,50 @@ export function DisconnectDiscord():Promise<void>;
 
 export function DiscoverPlexServers():Promise<Array<plex.Server>>;
 
+export function DiscoverRemotePlexServers():Promise<Array<plex.Server>>;
+
 export function DownloadAndInstallUpdate():Promise<version.UpdateInfo>;
 
+export function GetAdaptivePolling():Promise<main.AdaptivePolling>;
+
+export function GetAllSessions():Promise<Array<plex.MediaSession>>;
+
 export function GetAutoStart():Promise<boolean>;
 
 export function GetAutoUpdateCheck():Promise<boolean>;
 
 export function GetConnectionHistory():Promise<main.ConnectionHistory>;
 
-export function GetCurrentSession():Promise<plex.MusicSession>;
+export function GetCurrentSession():Promise<plex.MediaSession>;
 
 export function GetDefaultDiscordClientID():Promise<string>;
 
+export function GetDiscordBroadcast():Promise<boolean>;
+
 export function GetDiscordClientID():Promise<string>;
 
+export function GetDiscordInstance():Promise<config.DiscordInstance>;
+
 export function GetDiscordRetryState():Promise<retry.RetryState>;
 
+export function GetDiscordTargets():Promise<Array<discord.TargetStatus>>;
+
+export function GetEnabledMediaTypes():Promise<Array<string>>;
+
 export function GetErrorInfo(arg1:string):Promise<errors.ErrorInfo>;
 
 export function GetHideWhenPaused():Promise<Record<string, any>>;
 
+export function GetLibraryClassification(arg1:string):Promise<config.LibraryClassification>;
+
+export function GetLibrarySections(arg1:string):Promise<Array<plex.LibrarySection>>;
+
 export function GetListeningHistory(arg1:number):Promise<Array<history.Entry>>;
 
 export function GetListeningStats():Promise<history.Stats>;
 
+export function GetLyricsSettings():Promise<main.LyricsSettings>;
+
 export function GetMinimizeToTray():Promise<boolean>;
 
 export function GetPlexConnectionStatus():Promise<main.PlexConnectionStatus>;
@@ -67,14 +91,24 @@ export function GetPlexUsers(arg1:string):Promise<Array<plex.PlexUser>>;
 
 export function GetPollingInterval():Promise<number>;
 
+export function GetPresenceButtons(arg1:string):Promise<Array<config.PresenceButton>>;
+
 export function GetPresenceFormat():Promise<main.PresenceFormatSettings>;
 
+export function GetPresenceFormatFor(arg1:string):Promise<main.PresenceFormatSettings>;
+
 export function GetPresenceOptions():Promise<main.PresenceOptions>;
 
 export function GetResourceStats():Promise<main.ResourceStats>;
 
 export function GetServers():Promise<Array<config.ServerConfig>>;
 
+export function GetSessionFilters():Promise<config.SessionFilters>;
+
+export function GetSessionSelection():Promise<config.SessionSelection>;
+
+export function GetUpNext():Promise<Array<plex.PlayQueueItem>>;
+
 export function GetUpdateStatus():Promise<updater.Status>;
 
 export function GetVersion():Promise<version.Info>;
@@ -93,20 +127,32 @@ export function IsPresencePaused():Promise<boolean>;
 
 export function IsRetryableError(arg1:string):Promise<boolean>;
 
+export function ListDiscordInstances():Promise<Array<ipc.Instance>>;
+
+export function MarkCurrentPlayed():Promise<void>;
+
+export function MarkCurrentUnplayed():Promise<void>;
+
 export function MinimizeWindow():Promise<void>;
 
 export function OpenReleaseURL(arg1:string):Promise<void>;
 
 export function OpenReleasesPage():Promise<void>;
 
+export function PausePlayback():Promise<void>;
+
 export function QuitApp():Promise<void>;
 
+export function RateCurrentTrack(arg1:number):Promise<void>;
+
 export function RemoveServer(arg1:string):Promise<void>;
 
 export function ResetApplication():Promise<void>;
 
 export function RestartApplication():Promise<void>;
 
+export function ResumePlayback():Promise<void>;
+
 export function RetryDiscordConnection():Promise<void>;
 
 export function RetryPlexConnection():Promise<void>;
@@ -119,26 +165,52 @@ export function SavePlexUserSelection(arg1:string,arg2:string):Promise<void>;
 
 export function SaveServerURL(arg1:string):Promise<void>;
 
+export function SetAdaptivePolling(arg1:boolean,arg2:number):Promise<void>;
+
 export function SetAutoStart(arg1:boolean):Promise<void>;
 
 export function SetAutoUpdateCheck(arg1:boolean):Promise<void>;
 
+export function SetDiscordBroadcast(arg1:boolean):Promise<void>;
+
+export function SetDiscordInstance(arg1:config.DiscordInstance):Promise<void>;
+
+export function SetEnabledMediaTypes(arg1:Array<string>):Promise<void>;
+
 export function SetHideWhenPaused(arg1:boolean,arg2:number):Promise<void>;
 
+export function SetLibraryClassification(arg1:string,arg2:config.LibraryClassification):Promise<void>;
+
+export function SetLyricsSettings(arg1:main.LyricsSettings):Promise<void>;
+
 export function SetMinimizeToTray(arg1:boolean):Promise<void>;
 
 export function SetPollingInterval(arg1:number):Promise<void>;
 
+export function SetPresenceButtons(arg1:string,arg2:Array<config.PresenceButton>):Promise<void>;
+
 export function SetPresenceFormat(arg1:string,arg2:string):Promise<void>;
 
+export function SetPresenceFormatFor(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function SetPresenceOptions(arg1:main.PresenceOptions):Promise<void>;
 
 export function SetServerActive(arg1:string,arg2:boolean):Promise<void>;
 
+export function SetServerConnections(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetSessionFilters(arg1:config.SessionFilters):Promise<void>;
+
+export function SetSessionSelection(arg1:config.SessionSelection):Promise<void>;
+
 export function ShowWindow():Promise<void>;
 
 export function SkipSetup():Promise<void>;
 
+export function SkipToNext():Promise<void>;
+
+export function SkipToPrevious():Promise<void>;
+
 export function StartPlexPINAuth():Promise<Record<string, any>>;
 
 export function StartSessionPolling():Promise<void>;
@@ -147,6 +219,8 @@ export function StopSessionPolling():Promise<void>;
 
 export function TestDiscordPresence():Promise<void>;
 
+export function TogglePlayback():Promise<void>;
+
 export function TogglePresencePause():Promise<boolean>;
 
 export function UpdateDiscordPresence(arg1:string,arg2:string,arg3:string,arg4:string,arg5:number,arg6:number):Promise<void>;
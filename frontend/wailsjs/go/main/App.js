@@ -2,6 +2,10 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddResourceServer(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddResourceServer'](arg1, arg2, arg3);
+}
+
 export function AddServer(arg1, arg2, arg3, arg4) {
   return window['go']['main']['App']['AddServer'](arg1, arg2, arg3, arg4);
 }
@@ -46,10 +50,22 @@ export function DiscoverPlexServers() {
   return window['go']['main']['App']['DiscoverPlexServers']();
 }
 
+export function DiscoverRemotePlexServers() {
+  return window['go']['main']['App']['DiscoverRemotePlexServers']();
+}
+
 export function DownloadAndInstallUpdate() {
   return window['go']['main']['App']['DownloadAndInstallUpdate']();
 }
 
+export function GetAdaptivePolling() {
+  return window['go']['main']['App']['GetAdaptivePolling']();
+}
+
+export function GetAllSessions() {
+  return window['go']['main']['App']['GetAllSessions']();
+}
+
 export function GetAutoStart() {
   return window['go']['main']['App']['GetAutoStart']();
 }
@@ -70,14 +86,30 @@ export function GetDefaultDiscordClientID() {
   return window['go']['main']['App']['GetDefaultDiscordClientID']();
 }
 
+export function GetDiscordBroadcast() {
+  return window['go']['main']['App']['GetDiscordBroadcast']();
+}
+
 export function GetDiscordClientID() {
   return window['go']['main']['App']['GetDiscordClientID']();
 }
 
+export function GetDiscordInstance() {
+  return window['go']['main']['App']['GetDiscordInstance']();
+}
+
 export function GetDiscordRetryState() {
   return window['go']['main']['App']['GetDiscordRetryState']();
 }
 
+export function GetDiscordTargets() {
+  return window['go']['main']['App']['GetDiscordTargets']();
+}
+
+export function GetEnabledMediaTypes() {
+  return window['go']['main']['App']['GetEnabledMediaTypes']();
+}
+
 export function GetErrorInfo(arg1) {
   return window['go']['main']['App']['GetErrorInfo'](arg1);
 }
@@ -86,6 +118,14 @@ export function GetHideWhenPaused() {
   return window['go']['main']['App']['GetHideWhenPaused']();
 }
 
+export function GetLibraryClassification(arg1) {
+  return window['go']['main']['App']['GetLibraryClassification'](arg1);
+}
+
+export function GetLibrarySections(arg1) {
+  return window['go']['main']['App']['GetLibrarySections'](arg1);
+}
+
 export function GetListeningHistory(arg1) {
   return window['go']['main']['App']['GetListeningHistory'](arg1);
 }
@@ -94,6 +134,10 @@ export function GetListeningStats() {
   return window['go']['main']['App']['GetListeningStats']();
 }
 
+export function GetLyricsSettings() {
+  return window['go']['main']['App']['GetLyricsSettings']();
+}
+
 export function GetMinimizeToTray() {
   return window['go']['main']['App']['GetMinimizeToTray']();
 }
@@ -118,10 +162,18 @@ export function GetPollingInterval() {
   return window['go']['main']['App']['GetPollingInterval']();
 }
 
+export function GetPresenceButtons(arg1) {
+  return window['go']['main']['App']['GetPresenceButtons'](arg1);
+}
+
 export function GetPresenceFormat() {
   return window['go']['main']['App']['GetPresenceFormat']();
 }
 
+export function GetPresenceFormatFor(arg1) {
+  return window['go']['main']['App']['GetPresenceFormatFor'](arg1);
+}
+
 export function GetPresenceOptions() {
   return window['go']['main']['App']['GetPresenceOptions']();
 }
@@ -134,6 +186,18 @@ export function GetServers() {
   return window['go']['main']['App']['GetServers']();
 }
 
+export function GetSessionFilters() {
+  return window['go']['main']['App']['GetSessionFilters']();
+}
+
+export function GetSessionSelection() {
+  return window['go']['main']['App']['GetSessionSelection']();
+}
+
+export function GetUpNext() {
+  return window['go']['main']['App']['GetUpNext']();
+}
+
 export function GetUpdateStatus() {
   return window['go']['main']['App']['GetUpdateStatus']();
 }
@@ -170,6 +234,18 @@ export function IsRetryableError(arg1) {
   return window['go']['main']['App']['IsRetryableError'](arg1);
 }
 
+export function ListDiscordInstances() {
+  return window['go']['main']['App']['ListDiscordInstances']();
+}
+
+export function MarkCurrentPlayed() {
+  return window['go']['main']['App']['MarkCurrentPlayed']();
+}
+
+export function MarkCurrentUnplayed() {
+  return window['go']['main']['App']['MarkCurrentUnplayed']();
+}
+
 export function MinimizeWindow() {
   return window['go']['main']['App']['MinimizeWindow']();
 }
@@ -182,10 +258,18 @@ export function OpenReleasesPage() {
   return window['go']['main']['App']['OpenReleasesPage']();
 }
 
+export function PausePlayback() {
+  return window['go']['main']['App']['PausePlayback']();
+}
+
 export function QuitApp() {
   return window['go']['main']['App']['QuitApp']();
 }
 
+export function RateCurrentTrack(arg1) {
+  return window['go']['main']['App']['RateCurrentTrack'](arg1);
+}
+
 export function RemoveServer(arg1) {
   return window['go']['main']['App']['RemoveServer'](arg1);
 }
@@ -198,6 +282,10 @@ export function RestartApplication() {
   return window['go']['main']['App']['RestartApplication']();
 }
 
+export function ResumePlayback() {
+  return window['go']['main']['App']['ResumePlayback']();
+}
+
 export function RetryDiscordConnection() {
   return window['go']['main']['App']['RetryDiscordConnection']();
 }
@@ -222,6 +310,10 @@ export function SaveServerURL(arg1) {
   return window['go']['main']['App']['SaveServerURL'](arg1);
 }
 
+export function SetAdaptivePolling(arg1, arg2) {
+  return window['go']['main']['App']['SetAdaptivePolling'](arg1, arg2);
+}
+
 export function SetAutoStart(arg1) {
   return window['go']['main']['App']['SetAutoStart'](arg1);
 }
@@ -230,10 +322,30 @@ export function SetAutoUpdateCheck(arg1) {
   return window['go']['main']['App']['SetAutoUpdateCheck'](arg1);
 }
 
+export function SetDiscordBroadcast(arg1) {
+  return window['go']['main']['App']['SetDiscordBroadcast'](arg1);
+}
+
+export function SetDiscordInstance(arg1) {
+  return window['go']['main']['App']['SetDiscordInstance'](arg1);
+}
+
+export function SetEnabledMediaTypes(arg1) {
+  return window['go']['main']['App']['SetEnabledMediaTypes'](arg1);
+}
+
 export function SetHideWhenPaused(arg1, arg2) {
   return window['go']['main']['App']['SetHideWhenPaused'](arg1, arg2);
 }
 
+export function SetLibraryClassification(arg1, arg2) {
+  return window['go']['main']['App']['SetLibraryClassification'](arg1, arg2);
+}
+
+export function SetLyricsSettings(arg1) {
+  return window['go']['main']['App']['SetLyricsSettings'](arg1);
+}
+
 export function SetMinimizeToTray(arg1) {
   return window['go']['main']['App']['SetMinimizeToTray'](arg1);
 }
@@ -242,10 +354,18 @@ export function SetPollingInterval(arg1) {
   return window['go']['main']['App']['SetPollingInterval'](arg1);
 }
 
+export function SetPresenceButtons(arg1, arg2) {
+  return window['go']['main']['App']['SetPresenceButtons'](arg1, arg2);
+}
+
 export function SetPresenceFormat(arg1, arg2) {
   return window['go']['main']['App']['SetPresenceFormat'](arg1, arg2);
 }
 
+export function SetPresenceFormatFor(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetPresenceFormatFor'](arg1, arg2, arg3);
+}
+
 export function SetPresenceOptions(arg1) {
   return window['go']['main']['App']['SetPresenceOptions'](arg1);
 }
@@ -254,6 +374,18 @@ export function SetServerActive(arg1, arg2) {
   return window['go']['main']['App']['SetServerActive'](arg1, arg2);
 }
 
+export function SetServerConnections(arg1, arg2) {
+  return window['go']['main']['App']['SetServerConnections'](arg1, arg2);
+}
+
+export function SetSessionFilters(arg1) {
+  return window['go']['main']['App']['SetSessionFilters'](arg1);
+}
+
+export function SetSessionSelection(arg1) {
+  return window['go']['main']['App']['SetSessionSelection'](arg1);
+}
+
 export function ShowWindow() {
   return window['go']['main']['App']['ShowWindow']();
 }
@@ -262,6 +394,14 @@ export function SkipSetup() {
   return window['go']['main']['App']['SkipSetup']();
 }
 
+export function SkipToNext() {
+  return window['go']['main']['App']['SkipToNext']();
+}
+
+export function SkipToPrevious() {
+  return window['go']['main']['App']['SkipToPrevious']();
+}
+
 export function StartPlexPINAuth() {
   return window['go']['main']['App']['StartPlexPINAuth']();
 }
@@ -278,6 +418,10 @@ export function TestDiscordPresence() {
   return window['go']['main']['App']['TestDiscordPresence']();
 }
 
+export function TogglePlayback() {
+  return window['go']['main']['App']['TogglePlayback']();
+}
+
 export function TogglePresencePause() {
   return window['go']['main']['App']['TogglePresencePause']();
 }
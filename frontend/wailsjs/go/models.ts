@@ -1,10 +1,69 @@
 export namespace config {
 	
+	export class DiscordInstance {
+	    release: string;
+	    userId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscordInstance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.release = source["release"];
+	        this.userId = source["userId"];
+	    }
+	}
+	export class LibraryClassification {
+	    audiobooks: string[];
+	    podcasts: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LibraryClassification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.audiobooks = source["audiobooks"];
+	        this.podcasts = source["podcasts"];
+	    }
+	}
+	export class LibraryRules {
+	    include?: string[];
+	    exclude?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LibraryRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.include = source["include"];
+	        this.exclude = source["exclude"];
+	    }
+	}
+	export class PresenceButton {
+	    label: string;
+	    url: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PresenceButton(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.url = source["url"];
+	    }
+	}
 	export class ServerConfig {
 	    name: string;
 	    url: string;
 	    userId: string;
 	    userName: string;
+	    machineIdentifier?: string;
+	    connections?: string[];
+	    shared?: boolean;
 	    active: boolean;
 	
 	    static createFrom(source: any = {}) {
@@ -17,9 +76,129 @@ export namespace config {
 	        this.url = source["url"];
 	        this.userId = source["userId"];
 	        this.userName = source["userName"];
+	        this.machineIdentifier = source["machineIdentifier"];
+	        this.connections = source["connections"];
+	        this.shared = source["shared"];
 	        this.active = source["active"];
 	    }
 	}
+	export class SessionFilters {
+	    includePlayers?: string[];
+	    excludePlayers?: string[];
+	    includeProducts?: string[];
+	    excludeProducts?: string[];
+	    includePlayerIds?: string[];
+	    excludePlayerIds?: string[];
+	    libraries?: Record<string, LibraryRules>;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionFilters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includePlayers = source["includePlayers"];
+	        this.excludePlayers = source["excludePlayers"];
+	        this.includeProducts = source["includeProducts"];
+	        this.excludeProducts = source["excludeProducts"];
+	        this.includePlayerIds = source["includePlayerIds"];
+	        this.excludePlayerIds = source["excludePlayerIds"];
+	        this.libraries = this.convertValues(source["libraries"], LibraryRules, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SessionSelection {
+	    preferredPlayer: string;
+	    preferPlaying: boolean;
+	    preferMedia: string;
+	    preferRecent: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionSelection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.preferredPlayer = source["preferredPlayer"];
+	        this.preferPlaying = source["preferPlaying"];
+	        this.preferMedia = source["preferMedia"];
+	        this.preferRecent = source["preferRecent"];
+	    }
+	}
+
+}
+
+export namespace discord {
+	
+	export class Error {
+	    code: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Error(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class TargetStatus {
+	    error?: Error;
+	    path: string;
+	    release: string;
+	    username?: string;
+	    connected: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TargetStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.error = this.convertValues(source["error"], Error);
+	        this.path = source["path"];
+	        this.release = source["release"];
+	        this.username = source["username"];
+	        this.connected = source["connected"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 
 }
 
@@ -51,9 +230,11 @@ export namespace errors {
 export namespace history {
 	
 	export class Entry {
+	    mediaType?: string;
 	    track: string;
 	    artist: string;
 	    album: string;
+	    showTitle?: string;
 	    duration: number;
 	    // Go type: time
 	    startedAt: any;
@@ -65,9 +246,11 @@ export namespace history {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mediaType = source["mediaType"];
 	        this.track = source["track"];
 	        this.artist = source["artist"];
 	        this.album = source["album"];
+	        this.showTitle = source["showTitle"];
 	        this.duration = source["duration"];
 	        this.startedAt = this.convertValues(source["startedAt"], null);
 	        this.thumbUrl = source["thumbUrl"];
@@ -110,8 +293,81 @@ export namespace history {
 
 }
 
+export namespace ipc {
+	
+	export class User {
+	    id: string;
+	    username: string;
+	    global_name: string;
+	    discriminator: string;
+	    avatar: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new User(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.username = source["username"];
+	        this.global_name = source["global_name"];
+	        this.discriminator = source["discriminator"];
+	        this.avatar = source["avatar"];
+	    }
+	}
+	export class Instance {
+	    path: string;
+	    release: string;
+	    user: User;
+	
+	    static createFrom(source: any = {}) {
+	        return new Instance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.release = source["release"];
+	        this.user = this.convertValues(source["user"], User);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace main {
 	
+	export class AdaptivePolling {
+	    enabled: boolean;
+	    maxSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AdaptivePolling(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.maxSeconds = source["maxSeconds"];
+	    }
+	}
 	export class ConnectionHistory {
 	    // Go type: time
 	    plexLastConnected?: any;
@@ -146,10 +402,53 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class LyricsSettings {
+	    enabled: boolean;
+	    folder: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LyricsSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.folder = source["folder"];
+	    }
+	}
+	export class PlexServerStatus {
+	    name: string;
+	    url: string;
+	    activeUrl: string;
+	    userId: string;
+	    userName: string;
+	    connected: boolean;
+	    polling: boolean;
+	    pollingInterval: number;
+	    inErrorState: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlexServerStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.activeUrl = source["activeUrl"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.connected = source["connected"];
+	        this.polling = source["polling"];
+	        this.pollingInterval = source["pollingInterval"];
+	        this.inErrorState = source["inErrorState"];
+	    }
+	}
 	export class PlexConnectionStatus {
 	    serverUrl: string;
 	    userId: string;
 	    userName: string;
+	    servers: PlexServerStatus[];
 	    connected: boolean;
 	    polling: boolean;
 	    inErrorState: boolean;
@@ -163,11 +462,31 @@ export namespace main {
 	        this.serverUrl = source["serverUrl"];
 	        this.userId = source["userId"];
 	        this.userName = source["userName"];
+	        this.servers = this.convertValues(source["servers"], PlexServerStatus);
 	        this.connected = source["connected"];
 	        this.polling = source["polling"];
 	        this.inErrorState = source["inErrorState"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class PresenceFormatSettings {
 	    detailsFormat: string;
 	    stateFormat: string;
@@ -182,6 +501,22 @@ export namespace main {
 	        this.stateFormat = source["stateFormat"];
 	    }
 	}
+	export class PresenceOptions {
+	    activityStyle: string;
+	    statusDisplay: string;
+	    artworkLookup: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PresenceOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.activityStyle = source["activityStyle"];
+	        this.statusDisplay = source["statusDisplay"];
+	        this.artworkLookup = source["artworkLookup"];
+	    }
+	}
 	export class ResourceStats {
 	    timestamp: string;
 	    memoryAllocMB: number;
@@ -205,42 +540,207 @@ export namespace main {
 
 export namespace plex {
 	
-	export class MusicSession {
+	export class ItemMetadata {
+	    ratingKey: string;
+	    genres?: string[];
+	    moods?: string[];
+	    labels?: string[];
+	    studio?: string;
+	    trackCount?: number;
+	    guids?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ratingKey = source["ratingKey"];
+	        this.genres = source["genres"];
+	        this.moods = source["moods"];
+	        this.labels = source["labels"];
+	        this.studio = source["studio"];
+	        this.trackCount = source["trackCount"];
+	        this.guids = source["guids"];
+	    }
+	}
+	export class LibrarySection {
+	    id: string;
+	    title: string;
+	    type: string;
+	    agent: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LibrarySection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.title = source["title"];
+	        this.type = source["type"];
+	        this.agent = source["agent"];
+	    }
+	}
+	export class StreamQuality {
+	    codec?: string;
+	    bitrate?: number;
+	    bitDepth?: number;
+	    sampleRate?: number;
+	    resolution?: string;
+	    lossless?: boolean;
+	    decision?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StreamQuality(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.codec = source["codec"];
+	        this.bitrate = source["bitrate"];
+	        this.bitDepth = source["bitDepth"];
+	        this.sampleRate = source["sampleRate"];
+	        this.resolution = source["resolution"];
+	        this.lossless = source["lossless"];
+	        this.decision = source["decision"];
+	    }
+	}
+	export class PlayQueueItem {
+	    itemId: string;
+	    ratingKey: string;
+	    type: string;
+	    title: string;
+	    artist?: string;
+	    album?: string;
+	    duration?: number;
+	    thumbPath?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlayQueueItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.ratingKey = source["ratingKey"];
+	        this.type = source["type"];
+	        this.title = source["title"];
+	        this.artist = source["artist"];
+	        this.album = source["album"];
+	        this.duration = source["duration"];
+	        this.thumbPath = source["thumbPath"];
+	    }
+	}
+	export class MediaSession {
 	    sessionKey: string;
-	    userId: string;
-	    userName: string;
+	    ratingKey: string;
+	    key: string;
 	    type: string;
+	    mediaType: string;
 	    state: string;
-	    playerName: string;
-	    track: string;
-	    artist: string;
-	    album: string;
+	    title: string;
 	    thumb: string;
 	    thumbUrl: string;
 	    duration: number;
 	    viewOffset: number;
+	    year: number;
+	    userRating?: number;
+	    artist: string;
+	    album: string;
+	    trackNumber?: number;
+	    trackCount?: number;
+	    showTitle: string;
+	    season: number;
+	    episode: number;
+	    photoCount?: number;
+	    channelName?: string;
+	    channelNumber?: string;
+	    programTitle?: string;
+	    librarySectionId?: string;
+	    serverId?: string;
+	    guid?: string;
+	    slug?: string;
+	    showGuid?: string;
+	    showSlug?: string;
+	    userId: string;
+	    userName: string;
+	    playerName: string;
+	    playerId?: string;
+	    playQueueId?: string;
+	    playQueueItemId?: string;
+	    upNext?: PlayQueueItem[];
+	    quality: StreamQuality;
+	    seeked?: boolean;
+	    metadata?: ItemMetadata;
 	
 	    static createFrom(source: any = {}) {
-	        return new MusicSession(source);
+	        return new MediaSession(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.sessionKey = source["sessionKey"];
-	        this.userId = source["userId"];
-	        this.userName = source["userName"];
+	        this.ratingKey = source["ratingKey"];
+	        this.key = source["key"];
 	        this.type = source["type"];
+	        this.mediaType = source["mediaType"];
 	        this.state = source["state"];
-	        this.playerName = source["playerName"];
-	        this.track = source["track"];
-	        this.artist = source["artist"];
-	        this.album = source["album"];
+	        this.title = source["title"];
 	        this.thumb = source["thumb"];
 	        this.thumbUrl = source["thumbUrl"];
 	        this.duration = source["duration"];
 	        this.viewOffset = source["viewOffset"];
+	        this.year = source["year"];
+	        this.userRating = source["userRating"];
+	        this.artist = source["artist"];
+	        this.album = source["album"];
+	        this.trackNumber = source["trackNumber"];
+	        this.trackCount = source["trackCount"];
+	        this.showTitle = source["showTitle"];
+	        this.season = source["season"];
+	        this.episode = source["episode"];
+	        this.photoCount = source["photoCount"];
+	        this.channelName = source["channelName"];
+	        this.channelNumber = source["channelNumber"];
+	        this.programTitle = source["programTitle"];
+	        this.librarySectionId = source["librarySectionId"];
+	        this.serverId = source["serverId"];
+	        this.guid = source["guid"];
+	        this.slug = source["slug"];
+	        this.showGuid = source["showGuid"];
+	        this.showSlug = source["showSlug"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.playerName = source["playerName"];
+	        this.playerId = source["playerId"];
+	        this.playQueueId = source["playQueueId"];
+	        this.playQueueItemId = source["playQueueItemId"];
+	        this.upNext = this.convertValues(source["upNext"], PlayQueueItem);
+	        this.quality = this.convertValues(source["quality"], StreamQuality);
+	        this.seeked = source["seeked"];
+	        this.metadata = this.convertValues(source["metadata"], ItemMetadata);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 	export class PlexUser {
 	    id: string;
 	    name: string;
@@ -257,13 +757,41 @@ export namespace plex {
 	        this.thumb = source["thumb"];
 	    }
 	}
+	export class ServerConnection {
+	    uri: string;
+	    protocol: string;
+	    address: string;
+	    port: number;
+	    local: boolean;
+	    relay: boolean;
+	    IPv6: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ServerConnection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.uri = source["uri"];
+	        this.protocol = source["protocol"];
+	        this.address = source["address"];
+	        this.port = source["port"];
+	        this.local = source["local"];
+	        this.relay = source["relay"];
+	        this.IPv6 = source["IPv6"];
+	    }
+	}
 	export class Server {
 	    id: string;
 	    name: string;
 	    address: string;
 	    port: string;
 	    version: string;
+	    accessToken?: string;
+	    owner?: string;
+	    connections?: ServerConnection[];
 	    isLocal: boolean;
+	    owned: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Server(source);
@@ -276,9 +804,33 @@ export namespace plex {
 	        this.address = source["address"];
 	        this.port = source["port"];
 	        this.version = source["version"];
+	        this.accessToken = source["accessToken"];
+	        this.owner = source["owner"];
+	        this.connections = this.convertValues(source["connections"], ServerConnection);
 	        this.isLocal = source["isLocal"];
+	        this.owned = source["owned"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
+	
 	export class ValidationResult {
 	    serverName: string;
 	    serverVersion: string;
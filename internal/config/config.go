@@ -14,7 +14,18 @@ type ServerConfig struct {
 	URL      string `json:"url"`
 	UserID   string `json:"userId"`
 	UserName string `json:"userName"`
-	Active   bool   `json:"active"`
+	// MachineIdentifier is the server's unique ID. When set, a per-server
+	// access token may be stored in the keychain under it (shared servers).
+	MachineIdentifier string `json:"machineIdentifier,omitempty"`
+	// Connections are alternative base URLs for the same server (local,
+	// remote, relay, plex.direct). The client races them and fails over
+	// between them; URL remains the server's identity in the list.
+	Connections []string `json:"connections,omitempty"`
+	// Shared marks a server owned by someone else. Its sessions endpoint
+	// needs the owner's token, so playback is followed through the user's
+	// players (Plex Companion timelines) instead.
+	Shared bool `json:"shared,omitempty"`
+	Active bool `json:"active"`
 }
 
 // Config holds application configuration
@@ -28,19 +39,64 @@ type Config struct {
 	SelectedPlexUserID   string `json:"selectedPlexUserId"`   // ID of the Plex user to monitor
 	SelectedPlexUserName string `json:"selectedPlexUserName"` // Display name for UI purposes
 	PollingInterval      int    `json:"pollingInterval"`      // seconds
+	AdaptivePolling      bool   `json:"adaptivePolling"`      // Back off while nothing plays
+	AdaptivePollingMax   int    `json:"adaptivePollingMax"`   // Idle ceiling in seconds (0 = default)
 	MinimizeToTray       bool   `json:"minimizeToTray"`
 	AutoStart            bool   `json:"autoStart"`
 	SetupCompleted       bool   `json:"setupCompleted"` // True when setup wizard is done
 	SetupSkipped         bool   `json:"setupSkipped"`   // True when user skipped setup
 
+	// DiscordInstance chooses the Discord client presence goes to when
+	// several run (e.g. Stable and Canary, or a Flatpak next to a Snap).
+	// The zero value uses the first one found.
+	DiscordInstance DiscordInstance `json:"discordInstance"`
+
+	// DiscordBroadcast sends presence to every Discord client found (e.g.
+	// the desktop app and an arRPC bridge) instead of DiscordInstance.
+	DiscordBroadcast bool `json:"discordBroadcast"`
+
 	// Presence behavior
 	HideWhenPaused      bool `json:"hideWhenPaused"`      // Clear presence when playback is paused
 	HideWhenPausedDelay int  `json:"hideWhenPausedDelay"` // Seconds to wait before clearing (0 = immediate)
 
+	// Synced lyrics: show the current lyric line as the music state line.
+	// Lyrics come from the Plex server, else from "Artist - Title.lrc" or
+	// "Title.lrc" files in LyricsFolder.
+	LyricsEnabled bool   `json:"lyricsEnabled"`
+	LyricsFolder  string `json:"lyricsFolder,omitempty"`
+
 	// Custom presence format strings
 	PresenceDetailsFormat string `json:"presenceDetailsFormat"` // Format for Details line, e.g. "{track}"
 	PresenceStateFormat   string `json:"presenceStateFormat"`   // Format for State line, e.g. "by {artist} • {album}"
 
+	// EnabledMediaTypes lists the media types shown on Discord ("music",
+	// "movie", "tv", "photo", "clip", "live", "audiobook", "podcast"). Empty means music only, as before other
+	// media types were supported; use MediaTypesEnabled() to read it.
+	EnabledMediaTypes []string `json:"enabledMediaTypes,omitempty"`
+
+	// LibraryClassifications chooses the libraries whose items are shown as
+	// audiobooks or podcasts, on top of the libraries detected from their
+	// metadata agent. Section IDs are per server, so the choice is keyed by
+	// server machine identifier; use LibraryClassificationFor() to read it.
+	LibraryClassifications map[string]LibraryClassification `json:"libraryClassifications,omitempty"`
+
+	// SessionSelection chooses which of the user's concurrent sessions is
+	// shown. The zero value shows the one the server lists first.
+	SessionSelection SessionSelection `json:"sessionSelection"`
+
+	// SessionFilters keeps sessions on some players or from some libraries
+	// off Discord. Empty lists filter nothing.
+	SessionFilters SessionFilters `json:"sessionFilters"`
+
+	// PresenceFormats holds per-media-type format strings, keyed by media
+	// type. Music without an entry uses PresenceDetailsFormat and
+	// PresenceStateFormat; use PresenceFormatFor() to read them.
+	PresenceFormats map[string]PresenceFormat `json:"presenceFormats,omitempty"`
+
+	// PresenceButtons holds up to two buttons per media type, keyed by media
+	// type. Labels and URLs are templates using the format tokens.
+	PresenceButtons map[string][]PresenceButton `json:"presenceButtons,omitempty"`
+
 	// Presence display options
 	// ActivityStyle: "media" (Listening/Watching) or "game" (classic Playing).
 	// StatusDisplay: "app", "state", or "details" — which line shows in the member list.
@@ -63,6 +119,102 @@ type Config struct {
 	AutoUpdateCheck *bool `json:"autoUpdateCheck,omitempty"`
 }
 
+// PresenceFormat is a pair of Details/State format strings for one media
+// type. Empty strings use the presence builder's default layout.
+type PresenceFormat struct {
+	Details string `json:"details"`
+	State   string `json:"state"`
+}
+
+// PresenceButton is a button shown on the presence card. Label and URL may
+// use format tokens, including the link tokens (e.g. "{plexurl}").
+type PresenceButton struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// DiscordInstance identifies a running Discord client. Empty fields match
+// any client.
+type DiscordInstance struct {
+	Release string `json:"release"` // "stable", "ptb", "canary" or "development"
+	UserID  string `json:"userId"`  // Discord account logged into it
+}
+
+// SessionSelection is the policy for picking one of several concurrent
+// sessions; see plex.SelectionPolicy for how the criteria combine.
+type SessionSelection struct {
+	PreferredPlayer string `json:"preferredPlayer"` // Player name to prefer ("" = any)
+	PreferPlaying   bool   `json:"preferPlaying"`   // Playing over paused
+	PreferMedia     string `json:"preferMedia"`     // "video", "audio" or "" for no preference
+	PreferRecent    bool   `json:"preferRecent"`    // Most recently started first
+}
+
+// SessionFilters are include/exclude rules per session attribute; see
+// plex.SessionFilter for how they combine.
+type SessionFilters struct {
+	// Player titles (e.g. "Living Room TV")
+	IncludePlayers []string `json:"includePlayers,omitempty"`
+	ExcludePlayers []string `json:"excludePlayers,omitempty"`
+
+	// Player products (e.g. "Plex for Android (TV)")
+	IncludeProducts []string `json:"includeProducts,omitempty"`
+	ExcludeProducts []string `json:"excludeProducts,omitempty"`
+
+	// Player machine identifiers
+	IncludePlayerIDs []string `json:"includePlayerIds,omitempty"`
+	ExcludePlayerIDs []string `json:"excludePlayerIds,omitempty"`
+
+	// Library section IDs are per server, so their rules are keyed by server
+	// machine identifier; use LibraryRulesFor() to read them.
+	Libraries map[string]LibraryRules `json:"libraries,omitempty"`
+}
+
+// LibraryRules includes or excludes the library section IDs of one server.
+type LibraryRules struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// LibraryRulesFor returns the library rules of the server with the given
+// machine identifier.
+func (f SessionFilters) LibraryRulesFor(machineID string) LibraryRules {
+	return f.Libraries[machineID]
+}
+
+// LibraryClassification lists the library section IDs of one server shown
+// as audiobooks and podcasts.
+type LibraryClassification struct {
+	Audiobooks []string `json:"audiobooks"`
+	Podcasts   []string `json:"podcasts"`
+}
+
+// MediaTypesEnabled returns the media types shown on Discord, defaulting to
+// music only when none are configured.
+func (c *Config) MediaTypesEnabled() []string {
+	if len(c.EnabledMediaTypes) == 0 {
+		return []string{"music"}
+	}
+	return c.EnabledMediaTypes
+}
+
+// LibraryClassificationFor returns the audiobook and podcast libraries chosen
+// on the server with the given machine identifier.
+func (c *Config) LibraryClassificationFor(machineID string) LibraryClassification {
+	return c.LibraryClassifications[machineID]
+}
+
+// PresenceFormatFor returns the format strings for a media type. Music falls
+// back to the legacy PresenceDetailsFormat/PresenceStateFormat fields.
+func (c *Config) PresenceFormatFor(mediaType string) PresenceFormat {
+	if f, ok := c.PresenceFormats[mediaType]; ok {
+		return f
+	}
+	if mediaType == "music" {
+		return PresenceFormat{Details: c.PresenceDetailsFormat, State: c.PresenceStateFormat}
+	}
+	return PresenceFormat{}
+}
+
 // IsAutoUpdateCheckEnabled reports whether automatic update checks are
 // enabled. Defaults to true when the setting has never been persisted.
 func (c *Config) IsAutoUpdateCheckEnabled() bool {
@@ -128,3 +128,35 @@ func TestIsAutoUpdateCheckEnabled(t *testing.T) {
 		t.Error("explicit false should be disabled")
 	}
 }
+
+// TestMediaTypesEnabled verifies the music-only default and configured types
+func TestMediaTypesEnabled(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.MediaTypesEnabled(); len(got) != 1 || got[0] != "music" {
+		t.Errorf("MediaTypesEnabled() = %v, want [music]", got)
+	}
+	cfg.EnabledMediaTypes = []string{"music", "tv"}
+	if got := cfg.MediaTypesEnabled(); len(got) != 2 {
+		t.Errorf("MediaTypesEnabled() = %v, want configured types", got)
+	}
+}
+
+// TestPresenceFormatFor verifies per-type formats and the legacy music fields
+func TestPresenceFormatFor(t *testing.T) {
+	cfg := &Config{
+		PresenceDetailsFormat: "{track}",
+		PresenceStateFormat:   "{artist}",
+		PresenceFormats: map[string]PresenceFormat{
+			"movie": {Details: "{track} ({year})"},
+		},
+	}
+	if got := cfg.PresenceFormatFor("music"); got.Details != "{track}" || got.State != "{artist}" {
+		t.Errorf("music format = %+v, want legacy fields", got)
+	}
+	if got := cfg.PresenceFormatFor("movie"); got.Details != "{track} ({year})" {
+		t.Errorf("movie format = %+v", got)
+	}
+	if got := cfg.PresenceFormatFor("tv"); got != (PresenceFormat{}) {
+		t.Errorf("tv format = %+v, want empty", got)
+	}
+}
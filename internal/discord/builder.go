@@ -2,6 +2,8 @@ package discord
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"plexcord/internal/discord/ipc"
@@ -22,6 +24,12 @@ var builderRegistry = map[string]PresenceBuilder{
 	MediaTypeMusic: &musicBuilder{},
 	MediaTypeMovie: &movieBuilder{},
 	MediaTypeTV:    &tvBuilder{},
+	MediaTypePhoto: &photoBuilder{},
+	MediaTypeClip:  &clipBuilder{},
+	MediaTypeLive:  &liveBuilder{},
+
+	MediaTypeAudiobook: &audiobookBuilder{},
+	MediaTypePodcast:   &podcastBuilder{},
 }
 
 // RegisterPresenceBuilder registers a builder for a given media type.
@@ -32,7 +40,8 @@ func RegisterPresenceBuilder(mediaType string, builder PresenceBuilder) {
 
 // buildActivityForMediaType dispatches to the appropriate PresenceBuilder
 // based on data.MediaType. Falls back to the music builder for empty or
-// unknown media types to preserve backward compatibility.
+// unknown media types to preserve backward compatibility. Buttons render
+// the same way for every media type, so they are added here.
 func buildActivityForMediaType(data *PresenceData) ipc.Activity {
 	mt := data.MediaType
 	if mt == "" {
@@ -42,7 +51,9 @@ func buildActivityForMediaType(data *PresenceData) ipc.Activity {
 	if !ok {
 		builder = builderRegistry[MediaTypeMusic]
 	}
-	return builder.Build(data)
+	activity := builder.Build(data)
+	applyButtons(&activity, data)
+	return activity
 }
 
 // ----------------------------------------------------------------------------
@@ -117,12 +128,23 @@ func applyArtwork(activity *ipc.Activity, data *PresenceData, fallbackText strin
 
 // applyFormatTokens applies custom format strings with token replacement.
 // Supported tokens: {track}, {artist}, {album}, {year}, {player},
-// {show}, {season}, {episode}.
+// {show}, {season}, {episode}, {count}, {channel}, {chapter}, the album and
+// queue tokens {tracknum}, {trackcount}, {next}, the {rating} stars, the
+// stream quality tokens {codec}, {bitrate}, {bitdepth}, {samplerate}, {decision},
+// {resolution}, e.g. "{codec} {bitdepth}/{samplerate} · {decision}" renders
+// "FLAC 24/96 · Direct Play", and the link tokens of linkTokens.
 func applyFormatTokens(format string, data *PresenceData) string {
 	if format == "" {
 		return ""
 	}
-	replacer := strings.NewReplacer(
+	replacer := strings.NewReplacer(append(formatTokens(data), linkTokens(data)...)...)
+	return replacer.Replace(format)
+}
+
+// formatTokens returns the token/value pairs of applyFormatTokens, except
+// the link tokens.
+func formatTokens(data *PresenceData) []string {
+	return []string{
 		"{track}", data.Track,
 		"{artist}", data.Artist,
 		"{album}", data.Album,
@@ -131,8 +153,58 @@ func applyFormatTokens(format string, data *PresenceData) string {
 		"{show}", data.ShowTitle,
 		"{season}", fmt.Sprintf("%d", data.Season),
 		"{episode}", fmt.Sprintf("%d", data.Episode),
-	)
-	return replacer.Replace(format)
+		"{count}", positiveInt(data.ItemCount),
+		"{channel}", data.Channel,
+		"{chapter}", positiveInt(data.Chapter),
+		"{tracknum}", positiveInt(data.TrackNumber),
+		"{trackcount}", positiveInt(data.TrackCount),
+		"{next}", data.Next,
+		"{rating}", formatRating(data.Rating),
+		"{codec}", strings.ToUpper(data.Codec),
+		"{bitrate}", positiveInt(data.Bitrate),
+		"{bitdepth}", positiveInt(data.BitDepth),
+		"{samplerate}", formatSampleRate(data.SampleRate),
+		"{decision}", data.Decision,
+		"{resolution}", formatResolution(data.Resolution),
+	}
+}
+
+// positiveInt formats n, or "" when it is unknown (zero).
+func positiveInt(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// formatSampleRate formats a rate in Hz as kHz: 96000 → "96", 44100 → "44.1".
+func formatSampleRate(hz int) string {
+	if hz <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(hz)/1000, 'f', -1, 64)
+}
+
+// formatRating renders a rating out of 10 as five stars, rounded to whole
+// stars: 7 → "★★★★☆". Unrated items render as "".
+func formatRating(rating float64) string {
+	if rating <= 0 {
+		return ""
+	}
+	stars := int(math.Round(min(rating, 10) / 2))
+	return strings.Repeat("★", stars) + strings.Repeat("☆", 5-stars)
+}
+
+// formatResolution formats Plex's video resolution: "1080" → "1080p",
+// "4k" → "4K", "sd" → "SD".
+func formatResolution(resolution string) string {
+	if resolution == "" {
+		return ""
+	}
+	if _, err := strconv.Atoi(resolution); err == nil {
+		return resolution + "p"
+	}
+	return strings.ToUpper(resolution)
 }
 
 // ----------------------------------------------------------------------------
@@ -164,6 +236,9 @@ func (musicBuilder) Build(data *PresenceData) ipc.Activity {
 			}
 		}
 	}
+	if data.Lyric != "" {
+		activity.State = "♪ " + data.Lyric
+	}
 
 	applyActivityType(&activity, data, ipc.ActivityListening)
 	applyTimestamps(&activity, data)
@@ -231,3 +306,175 @@ func (tvBuilder) Build(data *PresenceData) ipc.Activity {
 	applyPlaybackIcon(&activity, data)
 	return activity
 }
+
+// ----------------------------------------------------------------------------
+// photoBuilder — formats a photo slideshow session
+// ----------------------------------------------------------------------------
+
+type photoBuilder struct{}
+
+func (photoBuilder) Build(data *PresenceData) ipc.Activity {
+	activity := ipc.Activity{}
+
+	if data.DetailsFormat != "" || data.StateFormat != "" {
+		activity.Details = applyFormatTokens(data.DetailsFormat, data)
+		activity.State = applyFormatTokens(data.StateFormat, data)
+	} else {
+		// Album name as details (Album holds the photo album), count as state
+		switch {
+		case data.Album != "":
+			activity.Details = data.Album
+		case data.Track != "":
+			activity.Details = data.Track
+		default:
+			activity.Details = "Photos"
+		}
+		switch {
+		case data.ItemCount == 1:
+			activity.State = "Slideshow • 1 photo"
+		case data.ItemCount > 1:
+			activity.State = fmt.Sprintf("Slideshow • %d photos", data.ItemCount)
+		default:
+			activity.State = "Slideshow"
+		}
+	}
+
+	// Slides have no meaningful duration: only an elapsed timer is shown
+	applyActivityType(&activity, data, ipc.ActivityWatching)
+	applyTimestamps(&activity, data)
+	applyArtwork(&activity, data, "Plex Photos")
+	applyPlaybackIcon(&activity, data)
+	return activity
+}
+
+// ----------------------------------------------------------------------------
+// clipBuilder — formats a music video (clip) session
+// ----------------------------------------------------------------------------
+
+type clipBuilder struct{}
+
+func (clipBuilder) Build(data *PresenceData) ipc.Activity {
+	activity := ipc.Activity{}
+
+	if data.DetailsFormat != "" || data.StateFormat != "" {
+		activity.Details = applyFormatTokens(data.DetailsFormat, data)
+		activity.State = applyFormatTokens(data.StateFormat, data)
+	} else {
+		activity.Details = data.Track
+		if data.Artist != "" {
+			activity.State = "by " + data.Artist
+		} else {
+			activity.State = "Music Video"
+		}
+	}
+
+	applyActivityType(&activity, data, ipc.ActivityWatching)
+	applyTimestamps(&activity, data)
+	applyArtwork(&activity, data, "Plex Music Video")
+	applyPlaybackIcon(&activity, data)
+	return activity
+}
+
+// ----------------------------------------------------------------------------
+// liveBuilder — formats a Live TV session
+// ----------------------------------------------------------------------------
+
+type liveBuilder struct{}
+
+func (liveBuilder) Build(data *PresenceData) ipc.Activity {
+	activity := ipc.Activity{}
+
+	if data.DetailsFormat != "" || data.StateFormat != "" {
+		activity.Details = applyFormatTokens(data.DetailsFormat, data)
+		activity.State = applyFormatTokens(data.StateFormat, data)
+	} else {
+		// Program title as details, channel as state
+		activity.Details = data.Track
+		switch {
+		case data.Channel != "" && data.ChannelNumber != "":
+			activity.State = fmt.Sprintf("Live on %s (%s)", data.Channel, data.ChannelNumber)
+		case data.Channel != "":
+			activity.State = "Live on " + data.Channel
+		default:
+			activity.State = "Live TV"
+		}
+	}
+
+	// A live airing has no duration to track progress against: the caller
+	// leaves Duration at zero, so only an elapsed timer is shown
+	applyActivityType(&activity, data, ipc.ActivityWatching)
+	applyTimestamps(&activity, data)
+	applyArtwork(&activity, data, "Plex Live TV")
+	applyPlaybackIcon(&activity, data)
+	return activity
+}
+
+// ----------------------------------------------------------------------------
+// audiobookBuilder — formats an audiobook session
+// ----------------------------------------------------------------------------
+
+type audiobookBuilder struct{}
+
+func (audiobookBuilder) Build(data *PresenceData) ipc.Activity {
+	activity := ipc.Activity{}
+
+	if data.DetailsFormat != "" || data.StateFormat != "" {
+		activity.Details = applyFormatTokens(data.DetailsFormat, data)
+		activity.State = applyFormatTokens(data.StateFormat, data)
+	} else {
+		// Book as details; chapter progress (or the chapter's title) as state
+		activity.Details = data.Album
+		if activity.Details == "" {
+			activity.Details = data.Track
+		}
+		var chapter string
+		switch {
+		case data.Chapter > 0 && data.ItemCount > 0:
+			chapter = fmt.Sprintf("Chapter %d of %d", data.Chapter, data.ItemCount)
+		case data.Chapter > 0:
+			chapter = fmt.Sprintf("Chapter %d", data.Chapter)
+		default:
+			chapter = data.Track
+		}
+		if data.Artist != "" {
+			activity.State = chapter + " • by " + data.Artist
+		} else {
+			activity.State = chapter
+		}
+	}
+
+	applyActivityType(&activity, data, ipc.ActivityListening)
+	applyTimestamps(&activity, data)
+	applyArtwork(&activity, data, "Plex Audiobooks")
+	applyPlaybackIcon(&activity, data)
+	return activity
+}
+
+// ----------------------------------------------------------------------------
+// podcastBuilder — formats a podcast episode session
+// ----------------------------------------------------------------------------
+
+type podcastBuilder struct{}
+
+func (podcastBuilder) Build(data *PresenceData) ipc.Activity {
+	activity := ipc.Activity{}
+
+	if data.DetailsFormat != "" || data.StateFormat != "" {
+		activity.Details = applyFormatTokens(data.DetailsFormat, data)
+		activity.State = applyFormatTokens(data.StateFormat, data)
+	} else {
+		// Episode as details, show as state
+		activity.Details = data.Track
+		if data.ShowTitle != "" {
+			activity.State = data.ShowTitle
+		} else {
+			activity.State = "Podcast"
+		}
+	}
+
+	applyActivityType(&activity, data, ipc.ActivityListening)
+	applyTimestamps(&activity, data)
+	applyArtwork(&activity, data, "Plex Podcasts")
+	applyPlaybackIcon(&activity, data)
+	return activity
+}
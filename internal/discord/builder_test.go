@@ -126,7 +126,7 @@ func TestBuildActivityForMediaType_DefaultsToMusic(t *testing.T) {
 
 func TestBuildActivityForMediaType_UnknownFallsBackToMusic(t *testing.T) {
 	data := &PresenceData{
-		MediaType: "audiobook", // unregistered
+		MediaType: "trailer", // unregistered
 		Track:     "Chapter 1",
 		Artist:    "Author",
 		State:     "playing",
@@ -313,3 +313,204 @@ func TestApplyTimestamps_PausedSendsNone(t *testing.T) {
 		t.Error("paused sessions should send no timestamps")
 	}
 }
+
+func TestPhotoBuilder_AlbumAndCount(t *testing.T) {
+	data := &PresenceData{
+		MediaType: MediaTypePhoto,
+		Track:     "IMG_0001",
+		Album:     "Vacation",
+		ItemCount: 42,
+		State:     "playing",
+	}
+
+	activity := (photoBuilder{}).Build(data)
+
+	if activity.Details != "Vacation" {
+		t.Errorf("expected album as Details, got %q", activity.Details)
+	}
+	if activity.State != "Slideshow • 42 photos" {
+		t.Errorf("unexpected State: %q", activity.State)
+	}
+	if activity.Type != ipc.ActivityWatching {
+		t.Errorf("expected Watching activity, got %v", activity.Type)
+	}
+	if activity.LargeImage != "plex" {
+		t.Errorf("expected Plex logo without artwork, got %q", activity.LargeImage)
+	}
+}
+
+func TestPhotoBuilder_Fallbacks(t *testing.T) {
+	activity := (photoBuilder{}).Build(&PresenceData{MediaType: MediaTypePhoto, State: "paused"})
+
+	if activity.Details != "Photos" || activity.State != "Slideshow" {
+		t.Errorf("unexpected fallback lines: %q / %q", activity.Details, activity.State)
+	}
+	if activity.SmallImage != "pause" {
+		t.Errorf("expected pause icon, got %q", activity.SmallImage)
+	}
+}
+
+func TestClipBuilder_DefaultFormat(t *testing.T) {
+	data := &PresenceData{
+		MediaType: MediaTypeClip,
+		Track:     "Take On Me",
+		Artist:    "a-ha",
+		State:     "playing",
+	}
+
+	activity := buildActivityForMediaType(data)
+
+	if activity.Details != "Take On Me" || activity.State != "by a-ha" {
+		t.Errorf("unexpected lines: %q / %q", activity.Details, activity.State)
+	}
+	if activity.Type != ipc.ActivityWatching {
+		t.Errorf("expected Watching activity, got %v", activity.Type)
+	}
+
+	data.Artist = ""
+	if activity := buildActivityForMediaType(data); activity.State != "Music Video" {
+		t.Errorf("expected Music Video fallback, got %q", activity.State)
+	}
+}
+
+func TestLiveBuilder_ElapsedTimerAndChannel(t *testing.T) {
+	start := time.Unix(1000, 0)
+	data := &PresenceData{
+		MediaType:     MediaTypeLive,
+		Track:         "The News",
+		Channel:       "KABC",
+		ChannelNumber: "7.1",
+		State:         "playing",
+		StartTime:     &start,
+	}
+
+	activity := buildActivityForMediaType(data)
+
+	if activity.Details != "The News" || activity.State != "Live on KABC (7.1)" {
+		t.Errorf("unexpected lines: %q / %q", activity.Details, activity.State)
+	}
+	if activity.Type != ipc.ActivityWatching {
+		t.Errorf("expected Watching activity, got %v", activity.Type)
+	}
+	if activity.Timestamps == nil || activity.Timestamps.Start == nil || activity.Timestamps.End != nil {
+		t.Errorf("expected elapsed timer (start only), got %+v", activity.Timestamps)
+	}
+
+	data.Channel, data.ChannelNumber = "", ""
+	if activity := buildActivityForMediaType(data); activity.State != "Live TV" {
+		t.Errorf("expected Live TV fallback, got %q", activity.State)
+	}
+}
+
+func TestAudiobookBuilder_ChapterProgress(t *testing.T) {
+	data := &PresenceData{
+		MediaType: MediaTypeAudiobook,
+		Track:     "Chapter Three",
+		Artist:    "J.R.R. Tolkien",
+		Album:     "The Hobbit",
+		Chapter:   3,
+		ItemCount: 19,
+		State:     "playing",
+	}
+
+	activity := buildActivityForMediaType(data)
+
+	if activity.Details != "The Hobbit" {
+		t.Errorf("expected book as Details, got %q", activity.Details)
+	}
+	if activity.State != "Chapter 3 of 19 • by J.R.R. Tolkien" {
+		t.Errorf("unexpected State: %q", activity.State)
+	}
+	if activity.Type != ipc.ActivityListening {
+		t.Errorf("expected Listening activity, got %v", activity.Type)
+	}
+
+	data.Chapter, data.ItemCount, data.Artist = 0, 0, ""
+	if activity := buildActivityForMediaType(data); activity.State != "Chapter Three" {
+		t.Errorf("expected chapter title without a number, got %q", activity.State)
+	}
+}
+
+func TestPodcastBuilder_EpisodeAndShow(t *testing.T) {
+	data := &PresenceData{
+		MediaType: MediaTypePodcast,
+		Track:     "Episode 12: Tides",
+		ShowTitle: "Ocean Stories",
+		State:     "playing",
+	}
+
+	activity := buildActivityForMediaType(data)
+
+	if activity.Details != "Episode 12: Tides" || activity.State != "Ocean Stories" {
+		t.Errorf("unexpected lines: %q / %q", activity.Details, activity.State)
+	}
+	if activity.LargeText != "Plex Podcasts" {
+		t.Errorf("expected podcast fallback text, got %q", activity.LargeText)
+	}
+}
+
+func TestApplyFormatTokens_StreamQuality(t *testing.T) {
+	data := &PresenceData{
+		Codec:      "flac",
+		BitDepth:   24,
+		SampleRate: 96000,
+		Decision:   "Direct Play",
+	}
+	if got := applyFormatTokens("{codec} {bitdepth}/{samplerate} · {decision}", data); got != "FLAC 24/96 · Direct Play" {
+		t.Errorf("unexpected audio quality: %q", got)
+	}
+
+	data = &PresenceData{SampleRate: 44100, Bitrate: 320, Resolution: "1080"}
+	if got := applyFormatTokens("{samplerate} kHz {bitrate} kbps {resolution}{bitdepth}", data); got != "44.1 kHz 320 kbps 1080p" {
+		t.Errorf("unexpected formatting: %q", got)
+	}
+	if got := formatResolution("4k"); got != "4K" {
+		t.Errorf("formatResolution(4k) = %q, want 4K", got)
+	}
+}
+
+func TestApplyFormatTokens_AlbumAndQueue(t *testing.T) {
+	data := &PresenceData{TrackNumber: 4, TrackCount: 12, Next: "Animal by Def Leppard"}
+	if got := applyFormatTokens("Track {tracknum} of {trackcount} · Next: {next}", data); got != "Track 4 of 12 · Next: Animal by Def Leppard" {
+		t.Errorf("unexpected formatting: %q", got)
+	}
+	if got := applyFormatTokens("{tracknum}/{trackcount}{next}", &PresenceData{}); got != "/" {
+		t.Errorf("expected unknown values to render empty, got %q", got)
+	}
+}
+
+func TestApplyFormatTokens_CountAndChapter(t *testing.T) {
+	data := &PresenceData{Chapter: 3, ItemCount: 19}
+	if got := applyFormatTokens("Chapter {chapter} of {count}", data); got != "Chapter 3 of 19" {
+		t.Errorf("unexpected rendering: %q", got)
+	}
+	// Unknown values render empty rather than as 0
+	if got := applyFormatTokens("{chapter}/{count}", &PresenceData{}); got != "/" {
+		t.Errorf("expected empty tokens when unknown, got %q", got)
+	}
+}
+
+func TestApplyFormatTokens_Rating(t *testing.T) {
+	tests := []struct {
+		rating float64
+		want   string
+	}{
+		{0, ""},
+		{10, "★★★★★"},
+		{7, "★★★★☆"},
+		{2, "★☆☆☆☆"},
+	}
+	for _, tt := range tests {
+		if got := applyFormatTokens("{rating}", &PresenceData{Rating: tt.rating}); got != tt.want {
+			t.Errorf("{rating} for %v = %q, want %q", tt.rating, got, tt.want)
+		}
+	}
+}
+
+func TestMusicBuilder_LyricReplacesState(t *testing.T) {
+	data := &PresenceData{MediaType: MediaTypeMusic, Track: "Hysteria", Artist: "Def Leppard", Lyric: "Out of touch"}
+	activity := buildActivityForMediaType(data)
+	if activity.Details != "Hysteria" || activity.State != "♪ Out of touch" {
+		t.Errorf("unexpected activity: details=%q state=%q", activity.Details, activity.State)
+	}
+}
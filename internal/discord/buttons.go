@@ -0,0 +1,129 @@
+package discord
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"plexcord/internal/discord/ipc"
+	"plexcord/internal/errors"
+)
+
+// Discord's limits on activity buttons.
+const (
+	MaxButtons           = 2
+	MaxButtonLabelLength = 32 // Characters
+	MaxButtonURLLength   = 512
+)
+
+// ButtonTemplate is a presence button whose label and URL may use the format
+// tokens of applyFormatTokens, e.g. {Label: "Listen on Plex", URL:
+// "{plexurl}"} or {Label: "Search lyrics", URL:
+// "https://genius.com/search?q={artist} {track}"}.
+type ButtonTemplate struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// linkTokens returns the token/value pairs of the pages about the item:
+// {plexurl}, {watchurl}, {musicbrainzurl} and {imdburl}.
+func linkTokens(data *PresenceData) []string {
+	return []string{
+		"{plexurl}", data.PlexURL,
+		"{watchurl}", data.WatchURL,
+		"{musicbrainzurl}", data.MusicBrainzURL,
+		"{imdburl}", data.IMDbURL,
+	}
+}
+
+// ValidateButtons checks button templates against Discord's limits: at most
+// MaxButtons buttons, each with a label and an http(s) URL, or a URL that
+// starts with a link token. Labels whose tokens render too long are cut at
+// MaxButtonLabelLength.
+func ValidateButtons(buttons []ButtonTemplate) error {
+	if len(buttons) > MaxButtons {
+		return errors.New(errors.INVALID_INPUT, fmt.Sprintf("discord shows at most %d buttons", MaxButtons))
+	}
+	for _, b := range buttons {
+		label := strings.TrimSpace(b.Label)
+		if label == "" {
+			return errors.New(errors.INVALID_INPUT, "button label cannot be empty")
+		}
+		if !strings.Contains(label, "{") && utf8.RuneCountInString(label) > MaxButtonLabelLength {
+			return errors.New(errors.INVALID_INPUT,
+				fmt.Sprintf("button label %q is longer than %d characters", label, MaxButtonLabelLength))
+		}
+		if len(b.URL) > MaxButtonURLLength {
+			return errors.New(errors.INVALID_INPUT,
+				fmt.Sprintf("button URL is longer than %d characters", MaxButtonURLLength))
+		}
+		if !validButtonURLTemplate(b.URL) {
+			return errors.New(errors.INVALID_INPUT, "button URL must be an http(s) URL: "+b.URL)
+		}
+	}
+	return nil
+}
+
+// validButtonURLTemplate reports whether a URL template renders an http(s)
+// URL: it starts with a link token or with an http(s) scheme and a host.
+func validButtonURLTemplate(template string) bool {
+	pairs := linkTokens(&PresenceData{})
+	for i := 0; i < len(pairs); i += 2 {
+		if strings.HasPrefix(template, pairs[i]) {
+			return true
+		}
+	}
+	return validButtonURL(template)
+}
+
+// validButtonURL reports whether Discord accepts raw as a button URL.
+func validButtonURL(raw string) bool {
+	if raw == "" || len(raw) > MaxButtonURLLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// applyButtons renders data.Buttons onto activity. A button whose label
+// renders empty, or whose URL isn't valid once rendered (typically a link
+// the item doesn't have, such as {imdburl} for a track), is left out.
+func applyButtons(activity *ipc.Activity, data *PresenceData) {
+	for _, b := range data.Buttons {
+		if len(activity.Buttons) == MaxButtons {
+			return
+		}
+		label := truncateLabel(strings.TrimSpace(applyFormatTokens(b.Label, data)))
+		link := renderButtonURL(b.URL, data)
+		if label == "" || !validButtonURL(link) {
+			continue
+		}
+		activity.Buttons = append(activity.Buttons, ipc.Button{Label: label, URL: link})
+	}
+}
+
+// renderButtonURL applies tokens to a URL template. Text tokens are
+// query-escaped so titles can go in search URLs; link tokens are inserted
+// as they are. Spaces typed in the template itself are escaped too.
+func renderButtonURL(template string, data *PresenceData) string {
+	template = strings.ReplaceAll(template, " ", "%20")
+	pairs := formatTokens(data)
+	for i := 1; i < len(pairs); i += 2 {
+		pairs[i] = url.QueryEscape(pairs[i])
+	}
+	return strings.NewReplacer(append(pairs, linkTokens(data)...)...).Replace(template)
+}
+
+// truncateLabel cuts label to MaxButtonLabelLength characters, ending it
+// with an ellipsis when cut.
+func truncateLabel(label string) string {
+	if utf8.RuneCountInString(label) <= MaxButtonLabelLength {
+		return label
+	}
+	runes := []rune(label)
+	return strings.TrimSpace(string(runes[:MaxButtonLabelLength-1])) + "…"
+}
@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"testing"
+
+	plexerrors "plexcord/internal/errors"
+)
+
+func TestApplyButtons(t *testing.T) {
+	data := &PresenceData{
+		MediaType: MediaTypeMusic,
+		Track:     "Hysteria",
+		Artist:    "Def Leppard & Co",
+		PlexURL:   "https://app.plex.tv/desktop/#!/server/abc/details?key=%2Flibrary%2Fmetadata%2F1",
+		Buttons: []ButtonTemplate{
+			{Label: "IMDb", URL: "{imdburl}"}, // Tracks have no IMDb page: skipped
+			{Label: "Play {track} on Plex", URL: "{plexurl}"},
+			{Label: "Search", URL: "https://example.com/search?q={artist} {track}"},
+			{Label: "Third", URL: "https://example.com"}, // Over the limit
+		},
+	}
+	activity := buildActivityForMediaType(data)
+
+	if len(activity.Buttons) != 2 {
+		t.Fatalf("expected 2 buttons, got %+v", activity.Buttons)
+	}
+	if b := activity.Buttons[0]; b.Label != "Play Hysteria on Plex" || b.URL != data.PlexURL {
+		t.Errorf("unexpected Plex button: %+v", b)
+	}
+	if b := activity.Buttons[1]; b.URL != "https://example.com/search?q=Def+Leppard+%26+Co%20Hysteria" {
+		t.Errorf("expected escaped text tokens, got %q", b.URL)
+	}
+}
+
+func TestApplyButtons_TruncatesLongLabels(t *testing.T) {
+	data := &PresenceData{
+		Track:   "A Very Long Track Title That Keeps Going",
+		Buttons: []ButtonTemplate{{Label: "{track}", URL: "https://example.com"}},
+	}
+	activity := buildActivityForMediaType(data)
+	if len(activity.Buttons) != 1 || len([]rune(activity.Buttons[0].Label)) > MaxButtonLabelLength {
+		t.Errorf("expected one label of at most %d characters, got %+v", MaxButtonLabelLength, activity.Buttons)
+	}
+}
+
+func TestValidateButtons(t *testing.T) {
+	valid := [][]ButtonTemplate{
+		nil,
+		{{Label: "On Plex", URL: "{plexurl}"}, {Label: "{track}", URL: "https://example.com/?q={track}"}},
+	}
+	for _, buttons := range valid {
+		if err := ValidateButtons(buttons); err != nil {
+			t.Errorf("ValidateButtons(%+v) error = %v", buttons, err)
+		}
+	}
+
+	invalid := [][]ButtonTemplate{
+		{{Label: "A", URL: "https://a.com"}, {Label: "B", URL: "https://b.com"}, {Label: "C", URL: "https://c.com"}},
+		{{Label: " ", URL: "https://example.com"}},
+		{{Label: "A label far longer than Discord allows", URL: "https://example.com"}},
+		{{Label: "Script", URL: "javascript:alert(1)"}},
+		{{Label: "Relative", URL: "/library/metadata/1"}},
+		{{Label: "Token later", URL: "x{plexurl}"}},
+	}
+	for _, buttons := range invalid {
+		if err := ValidateButtons(buttons); plexerrors.GetCode(err) != plexerrors.INVALID_INPUT {
+			t.Errorf("ValidateButtons(%+v) = %v, want %s", buttons, err, plexerrors.INVALID_INPUT)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package discord
+
+import "plexcord/internal/discord/ipc"
+
+// Discord builds an Instance can be.
+const (
+	ReleaseStable      = ipc.ReleaseStable
+	ReleasePTB         = ipc.ReleasePTB
+	ReleaseCanary      = ipc.ReleaseCanary
+	ReleaseDevelopment = ipc.ReleaseDevelopment
+)
+
+// Instance is a running Discord client PlexCord can show presence on.
+type Instance = ipc.Instance
+
+// InstanceSelector chooses among running Discord clients by release
+// (ReleaseStable, ReleaseCanary, ...) and logged-in user.
+type InstanceSelector = ipc.InstanceSelector
+
+// ListInstances returns every running Discord client reachable over IPC,
+// identified by logging into it with clientID (DefaultClientID if empty).
+func ListInstances(clientID string) []Instance {
+	if clientID == "" {
+		clientID = DefaultClientID
+	}
+	return ipc.ListInstances(clientID)
+}
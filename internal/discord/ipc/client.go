@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -14,9 +15,19 @@ import (
 // socket so a presence update can never block a caller indefinitely.
 const responseTimeout = 10 * time.Second
 
+// Default keepalive settings, see SetHeartbeat. Discord answers a PING
+// within milliseconds, so a missed PONG means the client is gone or hung.
+const (
+	DefaultHeartbeatInterval = 15 * time.Second
+	DefaultHeartbeatTimeout  = 10 * time.Second
+)
+
 // errNotConnected is returned when an operation is attempted before Login.
 var errNotConnected = errors.New("ipc: not connected")
 
+// errTimeout is returned when Discord doesn't answer within responseTimeout.
+var errTimeout = errors.New("ipc: timed out waiting for Discord")
+
 // ClosedError indicates Discord sent a CLOSE (op 2) frame or the socket was
 // torn down. Callers use it to detect a lost connection without string matching.
 type ClosedError struct {
@@ -42,41 +53,164 @@ func (e *ActivityError) Error() string {
 	return fmt.Sprintf("ipc: activity rejected (%d): %s", e.Code, e.Message)
 }
 
-// Client is a single Discord IPC connection. It is not safe for concurrent use;
-// callers (PresenceManager) serialize access with their own mutex.
+// call is a command waiting for its response.
+type call struct {
+	nonce string
+	cmd   string
+	reply chan error
+}
+
+// Client is a single Discord IPC connection. Frames are read by a background
+// loop that hands each response to the command with the same nonce, keeps the
+// READY payload and dispatches subscribed events, so a frame Discord sends
+// on its own never passes for a reply. Safe for concurrent use.
 type Client struct {
-	conn net.Conn
-	// dial opens the platform socket. Overridable in tests with a fake conn.
+	// dial, when set, opens the socket instead of searching the platform's
+	// sockets for the selected client. Tests use it for a fake conn.
 	dial func() (net.Conn, error)
+
+	selector InstanceSelector // Client to connect to, see SetInstance
+	instance Instance         // Client of the current connection
+
+	writeMu sync.Mutex // Serializes frames on the socket
+
+	mu      sync.Mutex
+	conn    net.Conn
+	pending []*call
+	ready   *Ready
+	readyCh chan struct{} // Closed once READY arrives
+	done    chan struct{} // Closed when the read loop ends
+	err     error         // Why the read loop ended
+	pongs   chan struct{} // Receives a value for each PONG
+	onEvent func(Event)
+
+	// Keepalive settings; a zero interval disables the heartbeat
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
 }
 
 // New returns a Client that connects to the local Discord IPC socket.
 func New() *Client {
-	return &Client{dial: dialDiscord}
+	return &Client{
+		heartbeatInterval: DefaultHeartbeatInterval,
+		heartbeatTimeout:  DefaultHeartbeatTimeout,
+	}
 }
 
-// Login opens the IPC socket and performs the handshake for clientID. It
-// returns an error if the socket cannot be opened or Discord rejects the
-// handshake (e.g. an unknown client id).
+// SetHeartbeat sets how often the connection is checked with a PING, and
+// how long a PONG may take before the connection is considered dead and
+// closed. A zero interval disables the heartbeat. Applies from the next
+// Login.
+func (c *Client) SetHeartbeat(interval, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heartbeatInterval = interval
+	c.heartbeatTimeout = timeout
+}
+
+// Login opens the IPC socket of the selected Discord client (see
+// SetInstance) and performs the handshake for clientID. It returns an error
+// if no socket can be opened or Discord rejects the handshake (e.g. an
+// unknown client id).
 func (c *Client) Login(clientID string) error {
-	conn, err := c.dial()
+	c.mu.Lock()
+	dial, sel := c.dial, c.selector
+	c.mu.Unlock()
+	if dial == nil {
+		return c.loginSelected(clientID, sel)
+	}
+	if err := c.login(clientID, dial); err != nil {
+		return err
+	}
+	inst := instanceOf(c, "")
+	c.mu.Lock()
+	c.instance = inst
+	c.mu.Unlock()
+	return nil
+}
+
+// login connects with dial and performs the handshake.
+func (c *Client) login(clientID string, dial func() (net.Conn, error)) error {
+	conn, err := dial()
 	if err != nil {
 		return err
 	}
-	c.conn = conn
+	readyCh, done := make(chan struct{}), make(chan struct{})
+	c.mu.Lock()
+	c.conn, c.ready, c.err = conn, nil, nil
+	c.readyCh, c.done = readyCh, done
+	c.pongs = make(chan struct{}, 1)
+	interval, timeout := c.heartbeatInterval, c.heartbeatTimeout
+	c.mu.Unlock()
+	go c.readLoop(conn, readyCh, done)
 
-	if err := c.handshake(clientID); err != nil {
+	if err := c.handshake(clientID, readyCh, done); err != nil {
 		// Tear the connection down on any handshake failure; the close error is
 		// not actionable, so join it with the primary error for visibility.
 		return errors.Join(err, c.Close())
 	}
+	if interval > 0 {
+		go c.heartbeat(conn, done, interval, timeout)
+	}
 	return nil
 }
 
-// handshake sends the op-0 handshake and validates Discord's reply. Discord
-// replies with a DISPATCH/READY frame; a CLOSE (e.g. an invalid client id) is
-// surfaced as an error so callers do not believe they connected.
-func (c *Client) handshake(clientID string) error {
+// heartbeat sends a PING every interval until the connection ends, and
+// closes it when a PONG doesn't come back within timeout. The read loop then
+// reports the closure like any other.
+func (c *Client) heartbeat(conn net.Conn, done <-chan struct{}, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		pongs := c.pongs
+		c.mu.Unlock()
+		select {
+		case <-pongs: // Drop a late PONG from the previous beat
+		default:
+		}
+
+		payload, _ := json.Marshal(map[string]string{"nonce": nonce()})
+		if err := c.send(opPing, payload); err != nil {
+			c.fail(conn, done, &ClosedError{Message: "heartbeat failed: " + err.Error()})
+			return
+		}
+
+		timer := time.NewTimer(timeout)
+		select {
+		case <-pongs:
+			timer.Stop()
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			c.fail(conn, done, &ClosedError{Message: fmt.Sprintf("no heartbeat response within %v", timeout)})
+			return
+		}
+	}
+}
+
+// fail closes a connection considered dead, recording err as the reason
+// unless it already ended.
+func (c *Client) fail(conn net.Conn, done <-chan struct{}, err error) {
+	c.mu.Lock()
+	if c.done == done && c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+	_ = conn.Close()
+}
+
+// handshake sends the op-0 handshake and waits for Discord's DISPATCH/READY
+// reply. A CLOSE (e.g. an invalid client id) is surfaced as an error so
+// callers do not believe they connected.
+func (c *Client) handshake(clientID string, readyCh, done <-chan struct{}) error {
 	payload, err := json.Marshal(handshake{V: "1", ClientID: clientID})
 	if err != nil {
 		return err
@@ -84,88 +218,284 @@ func (c *Client) handshake(clientID string) error {
 	if err := c.send(opHandshake, payload); err != nil {
 		return err
 	}
-	return c.readResponse()
+
+	timer := time.NewTimer(responseTimeout)
+	defer timer.Stop()
+	select {
+	case <-readyCh:
+		return nil
+	case <-done:
+		return c.Err()
+	case <-timer.C:
+		return errTimeout
+	}
 }
 
-// SetActivity sends a SET_ACTIVITY command and parses the response, returning
-// an *ActivityError if Discord rejected the payload or a *ClosedError if the
-// connection dropped.
+// SetActivity sends a SET_ACTIVITY command and waits for its response,
+// returning an *ActivityError if Discord rejected the payload or a
+// *ClosedError if the connection dropped.
 func (c *Client) SetActivity(a Activity) error {
-	if c.conn == nil {
-		return errNotConnected
-	}
-	payload, err := json.Marshal(frame{
+	f := frame{
 		Cmd:   "SET_ACTIVITY",
 		Args:  args{Pid: os.Getpid(), Activity: a.toPayload()},
 		Nonce: nonce(),
-	})
+	}
+	return c.command(f.Cmd, f.Nonce, f)
+}
+
+// Subscribe asks Discord to dispatch evt (e.g. "ACTIVITY_JOIN") to the event
+// handler. eventArgs are the event's arguments, nil for none.
+func (c *Client) Subscribe(evt string, eventArgs map[string]any) error {
+	return c.subscription("SUBSCRIBE", evt, eventArgs)
+}
+
+// Unsubscribe stops the dispatch of evt.
+func (c *Client) Unsubscribe(evt string, eventArgs map[string]any) error {
+	return c.subscription("UNSUBSCRIBE", evt, eventArgs)
+}
+
+func (c *Client) subscription(cmd, evt string, eventArgs map[string]any) error {
+	if eventArgs == nil {
+		eventArgs = map[string]any{}
+	}
+	f := eventFrame{Cmd: cmd, Evt: evt, Args: eventArgs, Nonce: nonce()}
+	return c.command(cmd, f.Nonce, f)
+}
+
+// SetEventHandler sets the function receiving subscribed events. It is
+// called from the read loop, so it must not block.
+func (c *Client) SetEventHandler(fn func(Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvent = fn
+}
+
+// Ready returns the READY payload of the connection: the Discord user and
+// API configuration. Nil before Login.
+func (c *Client) Ready() *Ready {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ready
+}
+
+// Done returns a channel that is closed as soon as the connection ends,
+// whether Discord closed it, the socket broke or Close was called. Nil
+// before Login.
+func (c *Client) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+// Err returns why the connection ended (a *ClosedError), or nil while it
+// is open.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close tears down the connection and waits for the read loop to stop. It is
+// safe to call when not connected.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	conn, done := c.conn, c.done
+	c.conn = nil
+	c.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	err := conn.Close()
+	<-done
+	return err
+}
+
+// command sends a command frame and waits for the response with its nonce.
+func (c *Client) command(cmd, nonce string, f any) error {
+	payload, err := json.Marshal(f)
 	if err != nil {
 		return err
 	}
+
+	cl := &call{nonce: nonce, cmd: cmd, reply: make(chan error, 1)}
+	c.mu.Lock()
+	if c.conn == nil {
+		c.mu.Unlock()
+		return errNotConnected
+	}
+	if c.err != nil {
+		// The read loop has ended (or is ending): the socket is closed and
+		// nothing would answer, so report why instead of writing to it.
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.pending = append(c.pending, cl)
+	done := c.done
+	c.mu.Unlock()
+
 	if err := c.send(opFrame, payload); err != nil {
+		c.forget(cl)
 		return err
 	}
-	return c.readResponse()
+
+	timer := time.NewTimer(responseTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-cl.reply:
+		return err
+	case <-done:
+		// The read loop may have answered just before it stopped
+		select {
+		case err := <-cl.reply:
+			return err
+		default:
+			return c.Err()
+		}
+	case <-timer.C:
+		c.forget(cl)
+		return errTimeout
+	}
 }
 
-// Close tears down the connection. It is safe to call when not connected.
-func (c *Client) Close() error {
-	if c.conn == nil {
-		return nil
+// forget drops a call that will no longer be waited for.
+func (c *Client) forget(cl *call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, p := range c.pending {
+		if p == cl {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return
+		}
 	}
-	err := c.conn.Close()
-	c.conn = nil
-	return err
 }
 
 func (c *Client) send(op opcode, payload []byte) error {
-	if c.conn == nil {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn == nil {
 		return errNotConnected
 	}
 	if len(payload) > maxFrameSize {
 		return fmt.Errorf("ipc: payload too large (%d bytes)", len(payload))
 	}
-	_, err := c.conn.Write(encodeFrame(op, payload))
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := conn.Write(encodeFrame(op, payload))
 	return err
 }
 
-// readResponse reads and interprets a single response frame. A CLOSE frame
-// yields a *ClosedError; an `evt: ERROR` frame yields an *ActivityError; any
-// other frame (READY, a normal SET_ACTIVITY ack) is treated as success.
-func (c *Client) readResponse() error {
-	if c.conn == nil {
-		return errNotConnected
+// readLoop reads frames until the connection ends, then fails the pending
+// commands with the reason and closes done.
+func (c *Client) readLoop(conn net.Conn, readyCh, done chan struct{}) {
+	var closeErr *ClosedError
+	for {
+		op, payload, err := readFrame(conn)
+		if err != nil {
+			closeErr = &ClosedError{Message: err.Error()}
+			break
+		}
+		if op == opClose {
+			closeErr = &ClosedError{}
+			// A malformed CLOSE body still means the connection is going away.
+			var cl closePayload
+			if json.Unmarshal(payload, &cl) == nil {
+				closeErr = &ClosedError{Code: cl.Code, Message: cl.Message}
+			}
+			break
+		}
+		switch op {
+		case opFrame:
+			c.handleFrame(payload, readyCh)
+		case opPing:
+			// Discord checking on us: echo the payload back
+			if err := c.send(opPong, payload); err != nil {
+				closeErr = &ClosedError{Message: err.Error()}
+			}
+		case opPong:
+			c.mu.Lock()
+			pongs := c.pongs
+			c.mu.Unlock()
+			select {
+			case pongs <- struct{}{}:
+			default:
+			}
+		}
+		if closeErr != nil {
+			break
+		}
 	}
-	// Bound the wait so a silent socket cannot block a presence update forever.
-	// The deadline is refreshed on each call, so it need not be reset here.
-	if err := c.conn.SetReadDeadline(time.Now().Add(responseTimeout)); err != nil {
-		return err
+
+	_ = conn.Close()
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = closeErr
+	}
+	err := c.err
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	for _, cl := range pending {
+		cl.reply <- err
 	}
+	close(done)
+}
 
-	op, payload, err := readFrame(c.conn)
-	if err != nil {
-		return err
+// handleFrame routes an op-1 frame: READY is kept, other DISPATCH frames go
+// to the event handler, and responses to the command that sent their nonce.
+func (c *Client) handleFrame(payload []byte, readyCh chan struct{}) {
+	var resp responseFrame
+	if err := json.Unmarshal(payload, &resp); err != nil {
+		// A frame we cannot parse is not fatal; drop it
+		return
 	}
 
-	switch op {
-	case opClose:
-		var cl closePayload
-		if err := json.Unmarshal(payload, &cl); err != nil {
-			// A malformed CLOSE body still means the connection is going away.
-			return &ClosedError{}
+	if resp.Cmd == "DISPATCH" {
+		if resp.Evt == "READY" {
+			var ready Ready
+			_ = json.Unmarshal(resp.Data, &ready)
+			c.mu.Lock()
+			first := c.ready == nil
+			c.ready = &ready
+			c.mu.Unlock()
+			if first {
+				close(readyCh)
+			}
+			return
 		}
-		return &ClosedError{Code: cl.Code, Message: cl.Message}
-	case opFrame:
-		var resp responseFrame
-		if err := json.Unmarshal(payload, &resp); err != nil {
-			// A frame we cannot parse is not fatal; Discord accepted the command.
-			return nil
+		c.mu.Lock()
+		onEvent := c.onEvent
+		c.mu.Unlock()
+		if onEvent != nil && resp.Evt != "" {
+			onEvent(Event{Name: resp.Evt, Data: resp.Data})
 		}
-		if resp.Evt == "ERROR" {
-			return &ActivityError{Code: resp.Data.Code, Message: resp.Data.Message}
+		return
+	}
+
+	var result error
+	if resp.Evt == "ERROR" {
+		var data errorData
+		_ = json.Unmarshal(resp.Data, &data)
+		result = &ActivityError{Code: data.Code, Message: data.Message}
+	}
+	if cl := c.takeCall(resp.Nonce, resp.Cmd); cl != nil {
+		cl.reply <- result
+	}
+}
+
+// takeCall removes and returns the pending call a response answers: the one
+// with its nonce or, for a response without one, the oldest call of the same
+// command.
+func (c *Client) takeCall(nonce, cmd string) *call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, cl := range c.pending {
+		if (nonce != "" && cl.nonce == nonce) || (nonce == "" && cl.cmd == cmd) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return cl
 		}
-		return nil
-	default:
-		return nil
 	}
+	return nil
 }
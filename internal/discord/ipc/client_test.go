@@ -174,3 +174,207 @@ func TestClient_ReadResponse_HonorsDeadline(t *testing.T) {
 		t.Error("expected a read timeout error from a silent socket")
 	}
 }
+
+func TestClient_MatchesResponsesByNonceAndKeepsReady(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = server.Close(); _ = client.Close() })
+
+	events := make(chan Event, 1)
+	go func() {
+		if _, _, err := readFrame(server); err != nil {
+			return
+		}
+		_, _ = server.Write(encodeFrame(opFrame, []byte(`{"cmd":"DISPATCH","evt":"READY","data":{"v":1,"user":{"id":"42","username":"wumpus"},"config":{"cdn_host":"cdn.discordapp.com"}}}`)))
+
+		for {
+			_, payload, err := readFrame(server)
+			if err != nil {
+				return
+			}
+			var f struct{ Cmd, Evt, Nonce string }
+			_ = json.Unmarshal(payload, &f)
+
+			// An unsolicited event and a stale response come first: neither
+			// may pass for the reply
+			_, _ = server.Write(encodeFrame(opFrame, []byte(`{"cmd":"DISPATCH","evt":"ACTIVITY_JOIN","data":{"secret":"s"}}`)))
+			_, _ = server.Write(encodeFrame(opFrame, []byte(`{"cmd":"SET_ACTIVITY","evt":"ERROR","nonce":"stale","data":{"code":4000}}`)))
+			_, _ = server.Write(encodeFrame(opFrame, []byte(`{"cmd":"`+f.Cmd+`","evt":null,"nonce":"`+f.Nonce+`","data":{}}`)))
+		}
+	}()
+
+	c := &Client{dial: func() (net.Conn, error) { return client, nil }}
+	c.SetEventHandler(func(e Event) { events <- e })
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if ready := c.Ready(); ready == nil || ready.User.Username != "wumpus" || ready.Config.CDNHost != "cdn.discordapp.com" {
+		t.Errorf("unexpected READY payload: %+v", ready)
+	}
+
+	if err := c.Subscribe("ACTIVITY_JOIN", nil); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	select {
+	case e := <-events:
+		if e.Name != "ACTIVITY_JOIN" || string(e.Data) != `{"secret":"s"}` {
+			t.Errorf("unexpected event: %s %s", e.Name, e.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the dispatched event")
+	}
+	if err := c.SetActivity(Activity{Details: "x"}); err != nil {
+		t.Errorf("SetActivity took a stale or unsolicited frame for its reply: %v", err)
+	}
+}
+
+func TestClient_ReportsClosureImmediately(t *testing.T) {
+	f := newFakeDiscord(t, func(op opcode, payload []byte) (opcode, []byte) {
+		return opFrame, readyFrame()
+	})
+
+	c := f.newClient()
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if c.Err() != nil {
+		t.Errorf("Err() = %v on an open connection", c.Err())
+	}
+
+	// Discord quits: the socket closes without any command in flight
+	_ = f.server.Close()
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected Done to close when the socket closes")
+	}
+	var closeErr *ClosedError
+	if !errors.As(c.Err(), &closeErr) {
+		t.Errorf("expected *ClosedError, got %v", c.Err())
+	}
+	if err := c.SetActivity(Activity{}); err == nil {
+		t.Error("expected SetActivity to fail on a closed connection")
+	}
+}
+
+func TestClient_CommandAfterClosureReturnsCloseReason(t *testing.T) {
+	f := newFakeDiscord(t, func(op opcode, payload []byte) (opcode, []byte) {
+		if op == opHandshake {
+			return opFrame, readyFrame()
+		}
+		// Any command after the handshake gets Discord hanging up
+		return opClose, []byte(`{"code":4000,"message":"bye"}`)
+	})
+
+	c := f.newClient()
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := c.SetActivity(Activity{Details: "x"}); err == nil {
+		t.Fatal("expected the command answered with CLOSE to fail")
+	}
+	<-c.Done()
+
+	// The socket is gone but Close was never called: later commands must
+	// report the closure, not a write error on the dead socket
+	for i := 0; i < 2; i++ {
+		err := c.SetActivity(Activity{Details: "y"})
+		var closeErr *ClosedError
+		if !errors.As(err, &closeErr) || closeErr.Code != 4000 {
+			t.Fatalf("expected the *ClosedError with code 4000, got %v", err)
+		}
+	}
+	c.mu.Lock()
+	pending := len(c.pending)
+	c.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("expected no pending commands after the closure, got %d", pending)
+	}
+}
+
+func TestClient_HeartbeatKeepsAnsweringConnectionOpen(t *testing.T) {
+	pings := make(chan struct{}, 16)
+	f := newFakeDiscord(t, func(op opcode, payload []byte) (opcode, []byte) {
+		if op == opPing {
+			pings <- struct{}{}
+			return opPong, payload
+		}
+		return opFrame, readyFrame()
+	})
+
+	c := f.newClient()
+	c.SetHeartbeat(10*time.Millisecond, 50*time.Millisecond)
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-pings:
+		case <-time.After(time.Second):
+			t.Fatal("expected periodic pings")
+		}
+	}
+	if c.Err() != nil {
+		t.Errorf("connection closed despite pongs: %v", c.Err())
+	}
+}
+
+func TestClient_MissedPongClosesConnection(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = server.Close(); _ = client.Close() })
+	go func() {
+		if _, _, err := readFrame(server); err != nil {
+			return
+		}
+		_, _ = server.Write(encodeFrame(opFrame, readyFrame()))
+		// Hung Discord: keep reading, never answer
+		for {
+			if _, _, err := readFrame(server); err != nil {
+				return
+			}
+		}
+	}()
+
+	c := &Client{dial: func() (net.Conn, error) { return client, nil }}
+	c.SetHeartbeat(10*time.Millisecond, 20*time.Millisecond)
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the connection to close after a missed pong")
+	}
+	var closeErr *ClosedError
+	if !errors.As(c.Err(), &closeErr) || closeErr.Message == "" {
+		t.Errorf("expected a heartbeat *ClosedError, got %v", c.Err())
+	}
+}
+
+func TestClient_AnswersDiscordPing(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = server.Close(); _ = client.Close() })
+	pong := make(chan []byte, 1)
+	go func() {
+		if _, _, err := readFrame(server); err != nil {
+			return
+		}
+		_, _ = server.Write(encodeFrame(opFrame, readyFrame()))
+		_, _ = server.Write(encodeFrame(opPing, []byte(`{"nonce":"n"}`)))
+		if op, payload, err := readFrame(server); err == nil && op == opPong {
+			pong <- payload
+		}
+	}()
+
+	c := &Client{dial: func() (net.Conn, error) { return client, nil }}
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	select {
+	case payload := <-pong:
+		if string(payload) != `{"nonce":"n"}` {
+			t.Errorf("pong payload = %s, want the ping's", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a PONG in answer to Discord's PING")
+	}
+}
@@ -4,7 +4,6 @@ package ipc
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -16,8 +15,9 @@ import (
 // immediately, so this only matters for a socket that exists but never accepts.
 const dialTimeout = 2 * time.Second
 
-// candidateDirs returns the directories that may hold a discord-ipc-N socket,
-// covering the plain runtime dir plus common Flatpak/Snap sandbox locations.
+// candidateDirs returns the directories that may hold a discord-ipc-N socket:
+// the runtime and temp dirs, plus the Snap and Flatpak sandboxes of Discord
+// Stable, PTB and Canary within each.
 func candidateDirs() []string {
 	var bases []string
 	for _, env := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
@@ -27,35 +27,51 @@ func candidateDirs() []string {
 	}
 	bases = append(bases, "/tmp")
 
-	dirs := make([]string, 0, 4*len(bases))
+	dirs := make([]string, 0, 8*len(bases))
 	for _, b := range bases {
-		dirs = append(dirs,
-			b,
-			filepath.Join(b, "snap.discord"),
-			filepath.Join(b, "app", "com.discordapp.Discord"),
-			filepath.Join(b, ".flatpak", "com.discordapp.Discord", "xdg-run"),
-		)
+		dirs = append(dirs, b)
+		for _, snap := range []string{"snap.discord", "snap.discord-ptb", "snap.discord-canary"} {
+			dirs = append(dirs, filepath.Join(b, snap))
+		}
+		for _, app := range []string{"com.discordapp.Discord", "com.discordapp.DiscordPTB", "com.discordapp.DiscordCanary"} {
+			dirs = append(dirs,
+				filepath.Join(b, "app", app),
+				filepath.Join(b, ".flatpak", app, "xdg-run"),
+			)
+		}
 	}
 	return dirs
 }
 
-// dialDiscord connects to the first available discord-ipc-{0..9} unix socket.
-func dialDiscord() (net.Conn, error) {
-	dialer := net.Dialer{Timeout: dialTimeout}
-	ctx := context.Background()
-	var lastErr error
+// socketPaths returns the discord-ipc-{0..9} sockets that exist, in search
+// order. A socket reachable from several directories (one being a link to
+// the other) is listed once.
+func socketPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
 	for _, dir := range candidateDirs() {
 		for i := 0; i < 10; i++ {
 			path := filepath.Join(dir, fmt.Sprintf("discord-ipc-%d", i))
-			conn, err := dialer.DialContext(ctx, "unix", path)
-			if err == nil {
-				return conn, nil
+			info, err := os.Stat(path)
+			if err != nil || info.Mode()&os.ModeSocket == 0 {
+				continue
+			}
+			real, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				real = path
+			}
+			if seen[real] {
+				continue
 			}
-			lastErr = err
+			seen[real] = true
+			paths = append(paths, path)
 		}
 	}
-	if lastErr == nil {
-		lastErr = errors.New("no discord-ipc socket found (is Discord running?)")
-	}
-	return nil, lastErr
+	return paths
+}
+
+// dialPath connects to the unix socket at path.
+func dialPath(path string) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	return dialer.DialContext(context.Background(), "unix", path)
 }
@@ -3,7 +3,6 @@
 package ipc
 
 import (
-	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -15,19 +14,17 @@ import (
 // because a plain Dial blocks for a very long time when Discord is not running.
 const dialTimeout = 2 * time.Second
 
-// dialDiscord connects to the first available discord-ipc-{0..9} named pipe.
-func dialDiscord() (net.Conn, error) {
-	var lastErr error
-	for i := 0; i < 10; i++ {
-		path := fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i)
-		conn, err := npipe.DialTimeout(path, dialTimeout)
-		if err == nil {
-			return conn, nil
-		}
-		lastErr = err
+// socketPaths returns the discord-ipc-{0..9} named pipes, in search order.
+// Every Discord build (Stable, PTB, Canary) takes the next free number.
+func socketPaths() []string {
+	paths := make([]string, 10)
+	for i := range paths {
+		paths[i] = fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i)
 	}
-	if lastErr == nil {
-		lastErr = errors.New("no discord-ipc pipe found (is Discord running?)")
-	}
-	return nil, lastErr
+	return paths
+}
+
+// dialPath connects to the named pipe at path.
+func dialPath(path string) (net.Conn, error) {
+	return npipe.DialTimeout(path, dialTimeout)
 }
@@ -0,0 +1,156 @@
+package ipc
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// Discord builds, as told apart by Release.
+const (
+	ReleaseStable      = "stable"
+	ReleasePTB         = "ptb"
+	ReleaseCanary      = "canary"
+	ReleaseDevelopment = "development"
+)
+
+// Release returns which Discord build sent the READY payload, from the API
+// endpoint it is configured with (e.g. "//canary.discord.com/api").
+func (r *Ready) Release() string {
+	endpoint := r.Config.APIEndpoint
+	switch {
+	case strings.Contains(endpoint, "canary."):
+		return ReleaseCanary
+	case strings.Contains(endpoint, "ptb."):
+		return ReleasePTB
+	case r.Config.Environment != "" && r.Config.Environment != "production":
+		return ReleaseDevelopment
+	default:
+		return ReleaseStable
+	}
+}
+
+// Instance is a running Discord client reachable over IPC.
+type Instance struct {
+	Path    string `json:"path"`    // Socket or named pipe
+	Release string `json:"release"` // ReleaseStable, ReleasePTB, ...
+	User    User   `json:"user"`    // Account logged into the client
+}
+
+// InstanceSelector chooses the Discord client to connect to. Empty fields
+// match any client; the zero value takes the first one found.
+type InstanceSelector struct {
+	Release string `json:"release"` // e.g. ReleaseCanary
+	UserID  string `json:"userId"`  // Discord user ID
+}
+
+// IsZero reports whether s matches any client.
+func (s InstanceSelector) IsZero() bool {
+	return s.Release == "" && s.UserID == ""
+}
+
+// Matches reports whether inst is a client s selects.
+func (s InstanceSelector) Matches(inst Instance) bool {
+	return (s.Release == "" || s.Release == inst.Release) &&
+		(s.UserID == "" || s.UserID == inst.User.ID)
+}
+
+// ListInstances identifies the Discord client behind every reachable IPC
+// socket by logging into it with clientID and reading its READY payload.
+// Sockets that don't complete the handshake are left out.
+func ListInstances(clientID string) []Instance {
+	var instances []Instance
+	for _, path := range socketPaths() {
+		c := &Client{dial: dialAt(path)}
+		if err := c.Login(clientID); err != nil {
+			continue
+		}
+		instances = append(instances, instanceOf(c, path))
+		_ = c.Close()
+	}
+	return instances
+}
+
+// SetInstance makes Login connect to the client sel selects, or to the
+// first client found when none does (so presence still shows somewhere).
+// The zero selector connects to the first client found.
+func (c *Client) SetInstance(sel InstanceSelector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selector = sel
+}
+
+// Instance returns the client the connection is logged into. Zero before
+// Login.
+func (c *Client) Instance() Instance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.instance
+}
+
+// loginSelected logs into each socket in turn until one is the client
+// sel selects, falling back to the first that answered.
+func (c *Client) loginSelected(clientID string, sel InstanceSelector) error {
+	fallback := ""
+	var lastErr error
+	for _, path := range socketPaths() {
+		if err := c.loginAt(clientID, path); err != nil {
+			lastErr = err
+			continue
+		}
+		if sel.Matches(c.Instance()) {
+			return nil
+		}
+		if fallback == "" {
+			fallback = path
+		}
+		_ = c.Close()
+	}
+
+	if fallback != "" {
+		return c.loginAt(clientID, fallback)
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no discord-ipc socket found (is Discord running?)")
+	}
+	return lastErr
+}
+
+// loginAt logs into the client listening at path.
+func (c *Client) loginAt(clientID, path string) error {
+	if err := c.login(clientID, dialAt(path)); err != nil {
+		return err
+	}
+	inst := instanceOf(c, path)
+	c.mu.Lock()
+	c.instance = inst
+	c.mu.Unlock()
+	return nil
+}
+
+// instanceOf describes the client c is logged into at path.
+func instanceOf(c *Client, path string) Instance {
+	inst := Instance{Path: path}
+	if ready := c.Ready(); ready != nil {
+		inst.Release = ready.Release()
+		inst.User = ready.User
+	}
+	return inst
+}
+
+// dialAt returns a dial function for the socket at path.
+func dialAt(path string) func() (net.Conn, error) {
+	return func() (net.Conn, error) { return dialPath(path) }
+}
+
+// SocketPaths returns the sockets (named pipes on Windows) a Discord client
+// may listen on, in search order.
+func SocketPaths() []string {
+	return socketPaths()
+}
+
+// LoginAt logs into the client listening at path, such as an Instance's
+// Path, regardless of the instance selector.
+func (c *Client) LoginAt(clientID, path string) error {
+	return c.loginAt(clientID, path)
+}
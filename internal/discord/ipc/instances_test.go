@@ -0,0 +1,106 @@
+//go:build !windows
+
+package ipc
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// serveFakeInstance answers handshakes on a unix socket at path with a READY
+// from userID on the Discord build behind apiEndpoint.
+func serveFakeInstance(t *testing.T, path, userID, apiEndpoint string) {
+	t.Helper()
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen %s: %v", path, err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	ready := []byte(`{"cmd":"DISPATCH","evt":"READY","data":{"v":1,"user":{"id":"` + userID + `"},"config":{"api_endpoint":"` + apiEndpoint + `","environment":"production"}}}`)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, _, err := readFrame(conn); err != nil {
+					return
+				}
+				_, _ = conn.Write(encodeFrame(opFrame, ready))
+				for {
+					if _, _, err := readFrame(conn); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+}
+
+func TestListInstancesAndSelection(t *testing.T) {
+	dir := t.TempDir()
+	for _, env := range []string{"TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	serveFakeInstance(t, filepath.Join(dir, "discord-ipc-0"), "1", "//discord.com/api")
+	canaryDir := filepath.Join(dir, "app", "com.discordapp.DiscordCanary")
+	if err := os.MkdirAll(canaryDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	serveFakeInstance(t, filepath.Join(canaryDir, "discord-ipc-0"), "2", "//canary.discord.com/api")
+
+	instances := ListInstances("123456789012345678")
+	var found []Instance
+	for _, inst := range instances {
+		if filepath.Dir(inst.Path) == dir || filepath.Dir(inst.Path) == canaryDir {
+			found = append(found, inst)
+		}
+	}
+	if len(found) != 2 || found[0].Release != ReleaseStable || found[1].Release != ReleaseCanary || found[1].User.ID != "2" {
+		t.Fatalf("unexpected instances: %+v", instances)
+	}
+
+	c := New()
+	c.SetHeartbeat(0, 0)
+	c.SetInstance(InstanceSelector{Release: ReleaseCanary})
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got := c.Instance(); got.Path != found[1].Path || got.User.ID != "2" {
+		t.Errorf("connected to %+v, want the Canary instance", got)
+	}
+	_ = c.Close()
+
+	// No match: the first instance found still gets the presence
+	c.SetInstance(InstanceSelector{UserID: "404"})
+	if err := c.Login("123456789012345678"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got := c.Instance(); got.Path != found[0].Path {
+		t.Errorf("connected to %+v, want the first instance", got)
+	}
+	_ = c.Close()
+}
+
+func TestReadyRelease(t *testing.T) {
+	tests := []struct {
+		endpoint, environment, want string
+	}{
+		{"//discord.com/api", "production", ReleaseStable},
+		{"//ptb.discord.com/api", "production", ReleasePTB},
+		{"//canary.discord.com/api", "production", ReleaseCanary},
+		{"//localhost:3000/api", "development", ReleaseDevelopment},
+	}
+	for _, tt := range tests {
+		r := &Ready{Config: ReadyConfig{APIEndpoint: tt.endpoint, Environment: tt.environment}}
+		if got := r.Release(); got != tt.want {
+			t.Errorf("Release() for %s = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
@@ -6,11 +6,16 @@
 // SET_ACTIVITY responses are parsed instead of ignored.
 //
 // The wire protocol is intentionally small: an opcode-framed stream carrying a
-// JSON handshake (op 0) followed by SET_ACTIVITY command frames (op 1). See
+// JSON handshake (op 0) followed by command frames (op 1), SET_ACTIVITY and
+// SUBSCRIBE, the responses and events Discord sends back, and PING/PONG
+// keepalives (op 3/4). See
 // https://discord.com/developers/docs/topics/rpc for the framing details.
 package ipc
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ActivityType is the Discord activity type sent in the presence payload.
 // Discord IPC has supported Listening/Watching over RPC since mid-2024; older
@@ -68,6 +73,35 @@ type Activity struct {
 	Buttons           []Button
 }
 
+// Ready is the payload of the READY event Discord sends after the handshake.
+type Ready struct {
+	V      int         `json:"v"`
+	User   User        `json:"user"`
+	Config ReadyConfig `json:"config"`
+}
+
+// User is the Discord user logged into the client.
+type User struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	GlobalName    string `json:"global_name"` // Display name, may be empty
+	Discriminator string `json:"discriminator"`
+	Avatar        string `json:"avatar"` // Avatar hash
+}
+
+// ReadyConfig is the API configuration of the Discord client.
+type ReadyConfig struct {
+	CDNHost     string `json:"cdn_host"`
+	APIEndpoint string `json:"api_endpoint"`
+	Environment string `json:"environment"`
+}
+
+// Event is a DISPATCH frame for a subscribed event.
+type Event struct {
+	Name string          // e.g. "ACTIVITY_JOIN"
+	Data json.RawMessage // The event's payload, as sent
+}
+
 // ----------------------------------------------------------------------------
 // Wire types (JSON encoded into command frames)
 // ----------------------------------------------------------------------------
@@ -116,15 +150,27 @@ type payloadButton struct {
 	URL   string `json:"url,omitempty"`
 }
 
-// responseFrame is the subset of a Discord op-1 response we care about.
+// eventFrame is a SUBSCRIBE or UNSUBSCRIBE command.
+type eventFrame struct {
+	Cmd   string         `json:"cmd"`
+	Evt   string         `json:"evt"`
+	Args  map[string]any `json:"args"`
+	Nonce string         `json:"nonce"`
+}
+
+// responseFrame is an op-1 frame from Discord: a response to a command
+// (with its nonce) or a DISPATCH of an event.
 type responseFrame struct {
-	Cmd   string `json:"cmd"`
-	Evt   string `json:"evt"`
-	Nonce string `json:"nonce"`
-	Data  struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"data"`
+	Cmd   string          `json:"cmd"`
+	Evt   string          `json:"evt"`
+	Nonce string          `json:"nonce"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// errorData is the data of an `evt: ERROR` response.
+type errorData struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
 }
 
 // closePayload is the body of an op-2 CLOSE frame.
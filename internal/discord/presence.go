@@ -12,26 +12,84 @@ import (
 )
 
 // PresenceManager handles Discord Rich Presence updates.
-// It manages the connection lifecycle and presence state.
+// It manages the connection lifecycle and presence state. Presence goes to
+// one Discord client, or to every client found in broadcast mode (see
+// SetBroadcast), each tracked as a target.
 type PresenceManager struct {
 	presence  *PresenceData
-	conn      *ipc.Client
+	targets   []*target
 	clientID  string
 	mu        sync.RWMutex
-	connected bool
+	connected bool // At least one target is connected
+
+	// onLost is called when Discord drops the connection (see
+	// SetConnectionLostHandler)
+	onLost func(err error)
+
+	// onTargetsChanged is called when one of several targets drops or fails
+	// (see SetTargetsChangedHandler)
+	onTargetsChanged func(targets []TargetStatus)
+
+	// Keepalive of new connections, see SetHeartbeat
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+
+	// Discord client new connections go to, see SetInstance
+	selector InstanceSelector
+
+	// Connect to every Discord client found, see SetBroadcast
+	broadcast bool
+
+	// How often broadcast mode looks for newly started Discord clients,
+	// and the channel that stops the scan
+	rescanInterval time.Duration
+	stopRescan     chan struct{}
 }
 
 // NewPresenceManager creates a new presence manager.
 func NewPresenceManager() *PresenceManager {
 	return &PresenceManager{
-		clientID:  DefaultClientID,
-		connected: false,
+		clientID:          DefaultClientID,
+		connected:         false,
+		heartbeatInterval: ipc.DefaultHeartbeatInterval,
+		heartbeatTimeout:  ipc.DefaultHeartbeatTimeout,
+		rescanInterval:    DefaultRescanInterval,
 	}
 }
 
+// SetHeartbeat sets how often the connection to Discord is checked, and how
+// long Discord may take to answer before the connection is considered lost.
+// A zero interval disables the check. Applies from the next Connect.
+func (pm *PresenceManager) SetHeartbeat(interval, timeout time.Duration) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.heartbeatInterval = interval
+	pm.heartbeatTimeout = timeout
+}
+
+// SetInstance chooses which running Discord client to show presence on.
+// Applies from the next Connect, outside broadcast mode.
+func (pm *PresenceManager) SetInstance(sel InstanceSelector) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.selector = sel
+}
+
+// SetBroadcast turns broadcast mode on or off: presence goes to every
+// Discord client found (e.g. the desktop app and an arRPC bridge such as
+// Vesktop's) instead of the one SetInstance selects. Applies from the next
+// Connect.
+func (pm *PresenceManager) SetBroadcast(enabled bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.broadcast = enabled
+}
+
 // Connect establishes a connection to Discord using the provided Client ID.
 // If clientID is empty, the default PlexCord Client ID is used.
-// Returns an error if connection fails.
+// Returns an error if connection fails. In broadcast mode it succeeds when at
+// least one client answers, and while connected it picks up clients started
+// since, both on each call and periodically (see DefaultRescanInterval).
 func (pm *PresenceManager) Connect(clientID string) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
@@ -48,6 +106,10 @@ func (pm *PresenceManager) Connect(clientID string) error {
 
 	// Already connected with same client ID
 	if pm.connected && pm.clientID == clientID {
+		if pm.broadcast {
+			pm.connectNewTargets(clientID)
+			pm.startRescan()
+		}
 		log.Printf("Discord: Already connected with Client ID %s", clientID)
 		return nil
 	}
@@ -55,28 +117,198 @@ func (pm *PresenceManager) Connect(clientID string) error {
 	// Disconnect existing connection if client ID changed
 	if pm.connected && pm.clientID != clientID {
 		log.Printf("Discord: Client ID changed, reconnecting...")
-		if pm.conn != nil {
-			if err := pm.conn.Close(); err != nil {
-				log.Printf("Discord: error closing previous IPC connection: %v", err)
-			}
-			pm.conn = nil
-		}
+		pm.stopRescanLocked()
+		pm.closeTargets()
 		pm.connected = false
 	}
 
 	log.Printf("Discord: Attempting to connect with Client ID %s", clientID)
+	pm.targets = nil
 
-	// Attempt to login to Discord over the internal IPC client.
-	c := ipc.New()
-	if err := c.Login(clientID); err != nil {
-		log.Printf("Discord: Connection failed: %v", err)
-		return mapDiscordError(err)
+	if pm.broadcast {
+		if err := pm.connectNewTargets(clientID); err != nil && len(pm.targets) == 0 {
+			log.Printf("Discord: Connection failed: %v", err)
+			return mapDiscordError(err)
+		}
+	} else {
+		// Attempt to login to Discord over the internal IPC client.
+		c := ipc.New()
+		c.SetHeartbeat(pm.heartbeatInterval, pm.heartbeatTimeout)
+		c.SetInstance(pm.selector)
+		if err := c.Login(clientID); err != nil {
+			log.Printf("Discord: Connection failed: %v", err)
+			return mapDiscordError(err)
+		}
+		pm.addTarget(c)
 	}
 
-	pm.conn = c
 	pm.clientID = clientID
 	pm.connected = true
-	log.Printf("Discord: Successfully connected")
+	pm.stopRescanLocked()
+	if pm.broadcast {
+		pm.startRescan()
+	}
+	return nil
+}
+
+// connectNewTargets connects to every Discord client not already connected,
+// returning the last error if none answered. Callers hold pm.mu.
+func (pm *PresenceManager) connectNewTargets(clientID string) error {
+	clients, err := loginNew(clientID, pm.connectedPaths(), pm.heartbeatInterval, pm.heartbeatTimeout)
+	for _, c := range clients {
+		pm.addTarget(c)
+	}
+	return err
+}
+
+// connectedPaths returns the sockets of the connected targets. Callers hold
+// pm.mu.
+func (pm *PresenceManager) connectedPaths() map[string]bool {
+	connected := make(map[string]bool)
+	for _, t := range pm.targets {
+		if t.connected {
+			connected[t.instance.Path] = true
+		}
+	}
+	return connected
+}
+
+// loginNew logs into every Discord client whose socket isn't in skip,
+// returning the last error if none answered. It doesn't touch the manager,
+// so it may run without pm.mu held.
+func loginNew(clientID string, skip map[string]bool, interval, timeout time.Duration) ([]*ipc.Client, error) {
+	var clients []*ipc.Client
+	var lastErr error
+	for _, path := range ipc.SocketPaths() {
+		if skip[path] {
+			continue
+		}
+		c := ipc.New()
+		c.SetHeartbeat(interval, timeout)
+		if err := c.LoginAt(clientID, path); err != nil {
+			lastErr = err
+			continue
+		}
+		clients = append(clients, c)
+	}
+	if len(clients) == 0 && lastErr == nil {
+		lastErr = stderrors.New("no new discord-ipc socket found")
+	}
+	if len(clients) == 0 {
+		return nil, lastErr
+	}
+	return clients, nil
+}
+
+// addTarget starts sending presence to the client c is logged into, in
+// place of a disconnected target for the same socket, and returns its
+// target. Callers hold pm.mu.
+func (pm *PresenceManager) addTarget(c *ipc.Client) *target {
+	t := &target{conn: c, instance: c.Instance(), connected: true}
+	replaced := false
+	for i, old := range pm.targets {
+		if old.instance.Path == t.instance.Path {
+			pm.targets[i], replaced = t, true
+			break
+		}
+	}
+	if !replaced {
+		pm.targets = append(pm.targets, t)
+	}
+
+	if t.instance.User.Username != "" {
+		log.Printf("Discord: Successfully connected as %s (%s, %s)", t.instance.User.Username, t.instance.Release, t.instance.Path)
+	} else {
+		log.Printf("Discord: Successfully connected (%s)", t.instance.Path)
+	}
+	go pm.watch(t)
+	return t
+}
+
+// closeTargets closes every target's connection. Callers hold pm.mu.
+func (pm *PresenceManager) closeTargets() {
+	for _, t := range pm.targets {
+		if !t.connected {
+			continue
+		}
+		t.connected = false
+		if err := t.conn.Close(); err != nil {
+			log.Printf("Discord: error closing IPC connection to %s: %v", t.instance.Path, err)
+		}
+	}
+}
+
+// watch marks t disconnected as soon as its connection ends without
+// Disconnect being called, e.g. when Discord quits or stops answering
+// heartbeats. Losing the last target loses the connection.
+func (pm *PresenceManager) watch(t *target) {
+	<-t.conn.Done()
+
+	pm.mu.Lock()
+	if !t.connected {
+		// Disconnected or replaced on purpose
+		pm.mu.Unlock()
+		return
+	}
+	err := errors.Wrap(t.conn.Err(), errors.DISCORD_NOT_RUNNING, "Discord connection lost")
+	t.connected = false
+	t.err = err
+	pm.connected = pm.anyConnected()
+	lost := !pm.connected
+	if lost {
+		pm.presence = nil
+	}
+	onLost, onTargetsChanged := pm.onLost, pm.onTargetsChanged
+	statuses := pm.statuses()
+	pm.mu.Unlock()
+
+	log.Printf("Discord: Connection to %s lost: %v", t.instance.Path, t.conn.Err())
+	switch {
+	case lost && onLost != nil:
+		onLost(err)
+	case !lost && onTargetsChanged != nil:
+		onTargetsChanged(statuses)
+	}
+}
+
+// SetConnectionLostHandler sets the function called when Discord drops the
+// connection (in broadcast mode, the last one). It runs on its own
+// goroutine, without the manager's lock held.
+func (pm *PresenceManager) SetConnectionLostHandler(fn func(err error)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.onLost = fn
+}
+
+// SetTargetsChangedHandler sets the function called with the status of
+// every target when one of them drops or fails while others stay connected.
+// It runs without the manager's lock held.
+func (pm *PresenceManager) SetTargetsChangedHandler(fn func(targets []TargetStatus)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.onTargetsChanged = fn
+}
+
+// Targets returns the status of each Discord client presence goes to.
+func (pm *PresenceManager) Targets() []TargetStatus {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.statuses()
+}
+
+// User returns the Discord user of the connection (in broadcast mode, of
+// the first connected target), or nil when not connected.
+func (pm *PresenceManager) User() *ipc.User {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	for _, t := range pm.targets {
+		if !t.connected {
+			continue
+		}
+		if ready := t.conn.Ready(); ready != nil {
+			return &ready.User
+		}
+	}
 	return nil
 }
 
@@ -95,13 +327,10 @@ func (pm *PresenceManager) Disconnect() error {
 	// Clear presence before logout
 	pm.presence = nil
 
-	// Close the IPC connection
-	if pm.conn != nil {
-		if err := pm.conn.Close(); err != nil {
-			log.Printf("Discord: error closing IPC connection: %v", err)
-		}
-		pm.conn = nil
-	}
+	// Close the IPC connections
+	pm.stopRescanLocked()
+	pm.closeTargets()
+	pm.targets = nil
 	pm.connected = false
 
 	log.Printf("Discord: Disconnected")
@@ -123,11 +352,26 @@ func (pm *PresenceManager) GetClientID() string {
 }
 
 // SetPresence updates the Discord Rich Presence with track information.
-// Returns an error if not connected or if the update fails.
+// Returns an error if not connected or if the update fails; in broadcast
+// mode, if it fails on every target.
 func (pm *PresenceManager) SetPresence(data *PresenceData) error {
 	pm.mu.Lock()
-	defer pm.mu.Unlock()
+	err := pm.setPresenceLocked(data)
+	var failed []TargetStatus
+	if err == nil && pm.anyFailed() {
+		failed = pm.statuses()
+	}
+	onTargetsChanged := pm.onTargetsChanged
+	pm.mu.Unlock()
+
+	if failed != nil && onTargetsChanged != nil {
+		onTargetsChanged(failed)
+	}
+	return err
+}
 
+// setPresenceLocked is SetPresence with pm.mu held.
+func (pm *PresenceManager) setPresenceLocked(data *PresenceData) error {
 	if !pm.connected {
 		return errors.New(errors.DISCORD_CONN_FAILED, "not connected to Discord")
 	}
@@ -135,7 +379,7 @@ func (pm *PresenceManager) SetPresence(data *PresenceData) error {
 	// Build activity from presence data
 	activity := buildActivity(data)
 
-	err := pm.conn.SetActivity(activity)
+	err := pm.fanOut(activity)
 	if err != nil {
 		log.Printf("Discord: Failed to set presence: %v", err)
 		// Check if connection was lost
@@ -166,7 +410,7 @@ func (pm *PresenceManager) ClearPresence() error {
 	// Send an empty activity to clear the presence display.
 	// This avoids the logout/login cycle that would briefly disconnect us
 	// and risk leaving the manager in an inconsistent state.
-	if err := pm.conn.SetActivity(ipc.Activity{}); err != nil {
+	if err := pm.fanOut(ipc.Activity{}); err != nil {
 		// If the upstream rejects the empty activity, log but don't disconnect.
 		// The previous presence data will still be showing until the next update.
 		log.Printf("Discord: Failed to clear presence (non-fatal): %v", err)
@@ -285,30 +529,59 @@ func isConnectionLostError(err error) bool {
 // activityStyle ("media"/"game") and statusDisplay ("app"/"state"/"details") control the
 // Discord activity type and member-list line; empty strings fall back to defaults.
 func (pm *PresenceManager) UpdatePresenceFromPlayback(track, artist, album, state string, duration, position int64, artworkURL, player, detailsFormat, stateFormat, activityStyle, statusDisplay string) error {
-	startTime := time.Now().Add(-time.Duration(position) * time.Millisecond)
-
-	data := &PresenceData{
+	return pm.UpdatePresenceFromMedia(&PresenceData{
+		MediaType:     MediaTypeMusic,
 		Track:         track,
 		Artist:        artist,
 		Album:         album,
 		State:         state,
 		Duration:      duration,
 		Position:      position,
-		StartTime:     &startTime,
 		ArtworkURL:    artworkURL,
 		Player:        player,
 		DetailsFormat: detailsFormat,
 		StateFormat:   stateFormat,
 		ActivityStyle: activityStyle,
 		StatusDisplay: statusDisplay,
+	})
+}
+
+// UpdatePresenceFromMedia updates presence from playback data of any media
+// type. data.MediaType selects the builder; the timestamps are derived from
+// data.Position and data.Duration (see PresenceData.SetTimestamps),
+// overwriting any already set.
+func (pm *PresenceManager) UpdatePresenceFromMedia(data *PresenceData) error {
+	pm.mu.RLock()
+	prev := pm.presence
+	pm.mu.RUnlock()
+
+	data.SetTimestamps(prev)
+	return pm.SetPresence(data)
+}
+
+// SetTimestamps sets the timestamps from the position as of ObservedAt.
+// Re-sending the item prev shows in the same state without a seek (artwork
+// resolved, lyric line, rating) keeps prev's timestamps instead, so the
+// progress bar doesn't jump back to a stale position.
+func (d *PresenceData) SetTimestamps(prev *PresenceData) {
+	if prev != nil && prev.StartTime != nil && !d.Seeked && d.ItemKey != "" &&
+		prev.ItemKey == d.ItemKey && prev.State == d.State && prev.Duration == d.Duration {
+		d.StartTime, d.EndTime = prev.StartTime, prev.EndTime
+		return
+	}
+
+	observedAt := d.ObservedAt
+	if observedAt.IsZero() {
+		observedAt = time.Now()
 	}
+	startTime := observedAt.Add(-time.Duration(d.Position) * time.Millisecond)
+	d.StartTime = &startTime
+	d.EndTime = nil
 
 	// Compute the end timestamp for the progress bar when the duration is known.
 	// Streams / unknown durations fall back to an elapsed-only timer.
-	if duration > 0 {
-		endTime := startTime.Add(time.Duration(duration) * time.Millisecond)
-		data.EndTime = &endTime
+	if d.Duration > 0 {
+		endTime := startTime.Add(time.Duration(d.Duration) * time.Millisecond)
+		d.EndTime = &endTime
 	}
-
-	return pm.SetPresence(data)
 }
@@ -300,6 +300,36 @@ func TestBuildActivity(t *testing.T) {
 	}
 }
 
+func TestPresenceDataSetTimestamps(t *testing.T) {
+	observed := time.Now().Add(-10 * time.Second)
+	first := &PresenceData{ItemKey: "1:123", State: "playing", Duration: 200000, Position: 30000, ObservedAt: observed}
+	first.SetTimestamps(nil)
+	if want := observed.Add(-30 * time.Second); !first.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want observation time minus position %v", first.StartTime, want)
+	}
+	if want := first.StartTime.Add(200 * time.Second); first.EndTime == nil || !first.EndTime.Equal(want) {
+		t.Errorf("EndTime = %v, want %v", first.EndTime, want)
+	}
+
+	// Re-sent with the position as of the last poll: the anchor stays
+	resend := &PresenceData{ItemKey: "1:123", State: "playing", Duration: 200000, Position: 30000}
+	resend.SetTimestamps(first)
+	if resend.StartTime != first.StartTime || resend.EndTime != first.EndTime {
+		t.Errorf("re-send moved the timestamps: %v-%v, want %v-%v", resend.StartTime, resend.EndTime, first.StartTime, first.EndTime)
+	}
+
+	for name, data := range map[string]*PresenceData{
+		"seek":         {ItemKey: "1:123", State: "playing", Duration: 200000, Position: 90000, Seeked: true},
+		"state change": {ItemKey: "1:123", State: "paused", Duration: 200000, Position: 40000},
+		"new item":     {ItemKey: "1:456", State: "playing", Duration: 180000, Position: 0},
+	} {
+		data.SetTimestamps(first)
+		if data.StartTime == first.StartTime {
+			t.Errorf("%s: expected new timestamps", name)
+		}
+	}
+}
+
 func TestGetCurrentPresence(t *testing.T) {
 	pm := NewPresenceManager()
 
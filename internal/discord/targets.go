@@ -0,0 +1,192 @@
+package discord
+
+import (
+	stderrors "errors"
+	"log"
+	"sync"
+	"time"
+
+	"plexcord/internal/discord/ipc"
+	"plexcord/internal/errors"
+)
+
+// DefaultRescanInterval is how often broadcast mode looks for Discord
+// clients started after Connect.
+const DefaultRescanInterval = 30 * time.Second
+
+// target is a Discord client presence goes to.
+type target struct {
+	conn      *ipc.Client
+	instance  ipc.Instance
+	connected bool
+	err       error // Why the last update failed, or why it disconnected
+}
+
+// status describes t for the frontend.
+func (t *target) status() TargetStatus {
+	s := TargetStatus{
+		Path:      t.instance.Path,
+		Release:   t.instance.Release,
+		Username:  t.instance.User.Username,
+		Connected: t.connected,
+	}
+	if t.err != nil {
+		s.Error = &Error{Code: errors.GetCode(t.err), Message: t.err.Error()}
+	}
+	return s
+}
+
+// fanOut sends activity to every connected target at once, recording each
+// one's outcome. It fails only when no target took the activity. Callers
+// hold pm.mu.
+func (pm *PresenceManager) fanOut(activity ipc.Activity) error {
+	var live []*target
+	for _, t := range pm.targets {
+		if t.connected {
+			live = append(live, t)
+		}
+	}
+
+	errs := make([]error, len(live))
+	var wg sync.WaitGroup
+	for i, t := range live {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = t.conn.SetActivity(activity)
+		}()
+	}
+	wg.Wait()
+
+	delivered := false
+	for i, t := range live {
+		t.err = errs[i]
+		delivered = delivered || errs[i] == nil
+	}
+	if delivered {
+		return nil
+	}
+	if err := stderrors.Join(errs...); err != nil {
+		return err
+	}
+	return stderrors.New("no Discord client connected")
+}
+
+// statuses returns the status of every target. Callers hold pm.mu.
+func (pm *PresenceManager) statuses() []TargetStatus {
+	statuses := make([]TargetStatus, len(pm.targets))
+	for i, t := range pm.targets {
+		statuses[i] = t.status()
+	}
+	return statuses
+}
+
+// anyConnected reports whether a target is still connected. Callers hold
+// pm.mu.
+func (pm *PresenceManager) anyConnected() bool {
+	for _, t := range pm.targets {
+		if t.connected {
+			return true
+		}
+	}
+	return false
+}
+
+// anyFailed reports whether a connected target failed its last update.
+// Callers hold pm.mu.
+func (pm *PresenceManager) anyFailed() bool {
+	for _, t := range pm.targets {
+		if t.connected && t.err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// startRescan starts looking for newly started Discord clients every
+// rescanInterval, unless a scan already runs. Callers hold pm.mu.
+func (pm *PresenceManager) startRescan() {
+	if pm.stopRescan != nil || pm.rescanInterval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	pm.stopRescan = stop
+	go pm.rescan(stop, pm.rescanInterval)
+}
+
+// stopRescanLocked stops the scan started by startRescan. Callers hold
+// pm.mu.
+func (pm *PresenceManager) stopRescanLocked() {
+	if pm.stopRescan != nil {
+		close(pm.stopRescan)
+		pm.stopRescan = nil
+	}
+}
+
+func (pm *PresenceManager) rescan(stop <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			pm.connectStartedClients(stop)
+		}
+	}
+}
+
+// connectStartedClients adds the Discord clients started since the last
+// scan as targets and shows them the current presence. Logging in may take
+// a while on a hung client, so it happens without pm.mu held.
+func (pm *PresenceManager) connectStartedClients(stop <-chan struct{}) {
+	pm.mu.RLock()
+	clientID, skip := pm.clientID, pm.connectedPaths()
+	interval, timeout := pm.heartbeatInterval, pm.heartbeatTimeout
+	live := pm.connected
+	pm.mu.RUnlock()
+	if !live {
+		return
+	}
+
+	clients, _ := loginNew(clientID, skip, interval, timeout)
+	if len(clients) == 0 {
+		return
+	}
+
+	pm.mu.Lock()
+	// Disconnected, reconnected or lost meanwhile
+	stopped := !pm.connected
+	select {
+	case <-stop:
+		stopped = true
+	default:
+	}
+	var added []*target
+	for _, c := range clients {
+		if stopped || pm.connectedPaths()[c.Instance().Path] {
+			_ = c.Close()
+			continue
+		}
+		added = append(added, pm.addTarget(c))
+	}
+	if len(added) == 0 {
+		pm.mu.Unlock()
+		return
+	}
+	if pm.presence != nil {
+		activity := buildActivity(pm.presence)
+		for _, t := range added {
+			if t.err = t.conn.SetActivity(activity); t.err != nil {
+				log.Printf("Discord: Failed to set presence on %s: %v", t.instance.Path, t.err)
+			}
+		}
+	}
+	onTargetsChanged := pm.onTargetsChanged
+	statuses := pm.statuses()
+	pm.mu.Unlock()
+
+	if onTargetsChanged != nil {
+		onTargetsChanged(statuses)
+	}
+}
@@ -0,0 +1,194 @@
+//go:build !windows
+
+package discord
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClient is a Discord client on a unix socket that accepts every
+// activity and counts them.
+type fakeClient struct {
+	activities atomic.Int32
+	conns      chan net.Conn
+}
+
+func serveFakeClient(t *testing.T, path string) *fakeClient {
+	t.Helper()
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen %s: %v", path, err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	f := &fakeClient{conns: make(chan net.Conn, 4)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			f.conns <- conn
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeClient) serve(conn net.Conn) {
+	defer conn.Close()
+	if _, err := readTestFrame(conn); err != nil {
+		return
+	}
+	writeTestFrame(conn, `{"cmd":"DISPATCH","evt":"READY","data":{"v":1,"user":{"id":"1","username":"plexfan"},"config":{"api_endpoint":"//discord.com/api"}}}`)
+	for {
+		payload, err := readTestFrame(conn)
+		if err != nil {
+			return
+		}
+		var req struct {
+			Cmd   string `json:"cmd"`
+			Nonce string `json:"nonce"`
+		}
+		_ = json.Unmarshal(payload, &req)
+		f.activities.Add(1)
+		writeTestFrame(conn, `{"cmd":"`+req.Cmd+`","nonce":"`+req.Nonce+`","data":{}}`)
+	}
+}
+
+func readTestFrame(r io.Reader) ([]byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, binary.LittleEndian.Uint32(header[4:8]))
+	_, err := io.ReadFull(r, payload)
+	return payload, err
+}
+
+func writeTestFrame(w io.Writer, payload string) {
+	buf := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], 1)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(payload)))
+	copy(buf[8:], payload)
+	_, _ = w.Write(buf)
+}
+
+func TestPresenceManager_BroadcastsToEveryClient(t *testing.T) {
+	dir := t.TempDir()
+	for _, env := range []string{"TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	desktop := serveFakeClient(t, filepath.Join(dir, "discord-ipc-0"))
+	bridge := serveFakeClient(t, filepath.Join(dir, "discord-ipc-1"))
+
+	pm := NewPresenceManager()
+	pm.SetHeartbeat(0, 0)
+	pm.SetBroadcast(true)
+	changed := make(chan []TargetStatus, 1)
+	pm.SetTargetsChangedHandler(func(targets []TargetStatus) { changed <- targets })
+	if err := pm.Connect(""); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = pm.Disconnect() })
+
+	var ours []TargetStatus
+	for _, s := range pm.Targets() {
+		if filepath.Dir(s.Path) == dir {
+			ours = append(ours, s)
+		}
+	}
+	if len(ours) != 2 || !ours[0].Connected || !ours[1].Connected || ours[0].Username != "plexfan" {
+		t.Fatalf("expected two connected targets, got %+v", pm.Targets())
+	}
+
+	if err := pm.SetPresence(&PresenceData{MediaType: MediaTypeMusic, Track: "Hysteria", Artist: "Def Leppard"}); err != nil {
+		t.Fatalf("SetPresence: %v", err)
+	}
+	if desktop.activities.Load() != 1 || bridge.activities.Load() != 1 {
+		t.Errorf("expected one activity on each client, got %d and %d", desktop.activities.Load(), bridge.activities.Load())
+	}
+
+	// The bridge quits: the desktop client keeps the presence
+	(<-bridge.conns).Close()
+	select {
+	case targets := <-changed:
+		for _, s := range targets {
+			if s.Path == ours[1].Path && (s.Connected || s.Error == nil) {
+				t.Errorf("expected the bridge reported disconnected, got %+v", s)
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a targets changed notification")
+	}
+	if !pm.IsConnected() {
+		t.Error("expected the manager to stay connected")
+	}
+	if err := pm.ClearPresence(); err != nil {
+		t.Errorf("ClearPresence: %v", err)
+	}
+	if desktop.activities.Load() != 2 {
+		t.Errorf("expected the clear on the desktop client, got %d activities", desktop.activities.Load())
+	}
+}
+
+func TestPresenceManager_BroadcastPicksUpStartedClients(t *testing.T) {
+	dir := t.TempDir()
+	for _, env := range []string{"TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	desktop := serveFakeClient(t, filepath.Join(dir, "discord-ipc-0"))
+
+	pm := NewPresenceManager()
+	pm.SetHeartbeat(0, 0)
+	pm.SetBroadcast(true)
+	pm.rescanInterval = 20 * time.Millisecond
+	changed := make(chan []TargetStatus, 1)
+	pm.SetTargetsChangedHandler(func(targets []TargetStatus) { changed <- targets })
+	if err := pm.Connect(""); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = pm.Disconnect() })
+	if err := pm.SetPresence(&PresenceData{MediaType: MediaTypeMusic, Track: "Hysteria", Artist: "Def Leppard"}); err != nil {
+		t.Fatalf("SetPresence: %v", err)
+	}
+
+	// The bridge starts after Connect: the next scan connects it and shows
+	// it the current presence
+	bridgePath := filepath.Join(dir, "discord-ipc-1")
+	bridge := serveFakeClient(t, bridgePath)
+	select {
+	case targets := <-changed:
+		found := false
+		for _, s := range targets {
+			found = found || (s.Path == bridgePath && s.Connected && s.Error == nil)
+		}
+		if !found {
+			t.Errorf("expected the bridge reported connected, got %+v", targets)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the started client to be picked up")
+	}
+	if desktop.activities.Load() != 1 || bridge.activities.Load() != 1 {
+		t.Errorf("expected the presence on each client once, got %d and %d", desktop.activities.Load(), bridge.activities.Load())
+	}
+
+	// Disconnect stops the scan
+	_ = pm.Disconnect()
+	late := serveFakeClient(t, filepath.Join(dir, "discord-ipc-2"))
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-late.conns:
+		t.Error("expected no scan after Disconnect")
+	default:
+	}
+}
@@ -25,6 +25,13 @@ const (
 	MediaTypeMovie = "movie"
 	MediaTypeTV    = "tv"
 	MediaTypePhoto = "photo"
+	MediaTypeClip  = "clip" // Music videos
+	MediaTypeLive  = "live" // Live TV; Track holds the program title
+
+	// Spoken audio. Audiobooks: Track is the chapter, Artist the author and
+	// Album the book. Podcasts: Track is the episode, ShowTitle the show.
+	MediaTypeAudiobook = "audiobook"
+	MediaTypePodcast   = "podcast"
 )
 
 // Activity style constants control the Discord activity type.
@@ -48,7 +55,7 @@ const (
 // fields for a given media type are simply ignored.
 type PresenceData struct {
 	// Timestamps for elapsed time / progress bar display.
-	// StartTime is when playback began (now − position); EndTime is
+	// StartTime is when playback began (ObservedAt − position); EndTime is
 	// StartTime + duration and is only set when the duration is known, so
 	// Discord can render a live progress bar.
 	StartTime *time.Time `json:"startTime,omitempty"`
@@ -70,6 +77,46 @@ type PresenceData struct {
 	Season    int    `json:"season,omitempty"`
 	Episode   int    `json:"episode,omitempty"`
 
+	// ItemCount is the size of the collection being shown (photos in the
+	// slideshow's album, chapters in the audiobook). Zero when unknown.
+	ItemCount int `json:"itemCount,omitempty"`
+
+	// Chapter is the audiobook chapter's number. Zero when unknown.
+	Chapter int `json:"chapter,omitempty"`
+
+	// Album position and play queue, only used through format tokens.
+	// Zero values are unknown.
+	TrackNumber int     `json:"trackNumber,omitempty"`
+	TrackCount  int     `json:"trackCount,omitempty"`
+	Next        string  `json:"next,omitempty"`   // What plays next, e.g. "Hysteria by Def Leppard"
+	Rating      float64 `json:"rating,omitempty"` // User rating out of 10, 0 when unrated
+
+	// Lyric is the lyric line being sung. When set, music presence shows it
+	// as the state line.
+	Lyric string `json:"lyric,omitempty"`
+
+	// Live TV fields (ignored for other media types)
+	Channel       string `json:"channel,omitempty"`
+	ChannelNumber string `json:"channelNumber,omitempty"`
+
+	// Stream quality, only used through format tokens. Zero values are
+	// unknown and render as empty strings.
+	Codec      string `json:"codec,omitempty"`      // e.g. "flac", "h264"
+	Bitrate    int    `json:"bitrate,omitempty"`    // kbps
+	BitDepth   int    `json:"bitDepth,omitempty"`   // Bits per sample
+	SampleRate int    `json:"sampleRate,omitempty"` // Hz
+	Decision   string `json:"decision,omitempty"`   // "Direct Play", "Direct Stream" or "Transcode"
+	Resolution string `json:"resolution,omitempty"` // As Plex reports it: "1080", "4k", "sd"
+
+	// Pages about the item, for link tokens. Empty when unavailable.
+	PlexURL        string `json:"plexUrl,omitempty"`        // Plex Web, for people with access to the server
+	WatchURL       string `json:"watchUrl,omitempty"`       // watch.plex.tv
+	MusicBrainzURL string `json:"musicBrainzUrl,omitempty"` // MusicBrainz recording
+	IMDbURL        string `json:"imdbUrl,omitempty"`
+
+	// Buttons are the button templates configured for the media type
+	Buttons []ButtonTemplate `json:"buttons,omitempty"`
+
 	// Artwork URL (for large image)
 	ArtworkURL string `json:"artworkUrl"`
 
@@ -78,6 +125,14 @@ type PresenceData struct {
 	Duration int64  `json:"duration"`
 	Position int64  `json:"position"`
 
+	// ItemKey identifies the session's current item, ObservedAt is when
+	// Position was reported (zero means now) and Seeked marks a position
+	// that jumped. UpdatePresenceFromMedia keeps the timestamps already
+	// shown unless the item, the state or the position changed.
+	ItemKey    string    `json:"-"`
+	ObservedAt time.Time `json:"-"`
+	Seeked     bool      `json:"-"`
+
 	// Custom format strings for presence display
 	DetailsFormat string `json:"detailsFormat,omitempty"`
 	StateFormat   string `json:"stateFormat,omitempty"`
@@ -91,8 +146,19 @@ type PresenceData struct {
 
 // ConnectionEvent represents a Discord connection state change event
 type ConnectionEvent struct {
-	Error     *Error `json:"error,omitempty"`
-	ClientID  string `json:"clientId,omitempty"`
+	Error     *Error         `json:"error,omitempty"`
+	ClientID  string         `json:"clientId,omitempty"`
+	Targets   []TargetStatus `json:"targets,omitempty"`
+	Connected bool           `json:"connected"`
+}
+
+// TargetStatus is the state of one Discord client presence goes to, for
+// frontend display
+type TargetStatus struct {
+	Error     *Error `json:"error,omitempty"` // Last failed update, or why it disconnected
+	Path      string `json:"path"`            // Socket or named pipe
+	Release   string `json:"release"`         // "stable", "ptb", "canary", ...
+	Username  string `json:"username,omitempty"`
 	Connected bool   `json:"connected"`
 }
 
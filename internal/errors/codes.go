@@ -68,6 +68,27 @@ const (
 	//
 	// Recommended action: Add a Plex server and select a user in Settings.
 	PLEX_NOT_CONFIGURED = "PLEX_NOT_CONFIGURED"
+
+	// PLEX_SERVER_MISMATCH indicates a different Plex server answered at the
+	// configured URL than the one that was set up.
+	// This typically occurs when:
+	// - The server's IP changed and another server took over the old address
+	// - The URL was edited to point at another server
+	//
+	// Recommended action: PlexCord re-scans the network for the original
+	// server; otherwise update the server URL in Settings.
+	PLEX_SERVER_MISMATCH = "PLEX_SERVER_MISMATCH"
+
+	// PLAYBACK_UNAVAILABLE indicates an action on the current playback has
+	// nothing it can apply to.
+	// This typically occurs when:
+	// - Nothing is playing
+	// - The current player can't be controlled remotely
+	// - The current item isn't in a library (e.g. Live TV)
+	// - The current session's server is no longer polled
+	//
+	// Recommended action: Start playback on a Plex player, then try again.
+	PLAYBACK_UNAVAILABLE = "PLAYBACK_UNAVAILABLE"
 )
 
 // Discord Error Codes
@@ -171,6 +192,14 @@ const (
 
 // General Error Codes
 const (
+	// INVALID_INPUT indicates a request carried a value it doesn't accept.
+	// This typically occurs when:
+	// - A value is out of range
+	// - An unknown command or option is requested
+	//
+	// Recommended action: Correct the value and try again.
+	INVALID_INPUT = "INVALID_INPUT"
+
 	// UNKNOWN_ERROR indicates an unexpected error occurred.
 	// This is a fallback error code when a more specific code isn't available.
 	//
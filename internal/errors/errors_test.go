@@ -196,6 +196,7 @@ func TestAllErrorCodes(t *testing.T) {
 	errorCodes := []string{
 		PLEX_UNREACHABLE,
 		PLEX_AUTH_FAILED,
+		PLEX_SERVER_MISMATCH,
 		DISCORD_NOT_RUNNING,
 		DISCORD_CONN_FAILED,
 		CONFIG_READ_FAILED,
@@ -205,6 +206,8 @@ func TestAllErrorCodes(t *testing.T) {
 		KEYCHAIN_READ_FAILED,
 		ENCRYPTION_FAILED,
 		DECRYPTION_FAILED,
+		PLAYBACK_UNAVAILABLE,
+		INVALID_INPUT,
 		UNKNOWN_ERROR,
 	}
 
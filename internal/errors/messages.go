@@ -48,6 +48,20 @@ var errorInfoMap = map[string]ErrorInfo{
 		Suggestion:  "Add a Plex server and select a user in Settings, then try again.",
 		Retryable:   false,
 	},
+	PLEX_SERVER_MISMATCH: {
+		Code:        PLEX_SERVER_MISMATCH,
+		Title:       "Different Plex Server",
+		Description: "Another Plex server is answering at the saved address.",
+		Suggestion:  "PlexCord is looking for your server on the network. If it has moved, update its URL in Settings.",
+		Retryable:   true,
+	},
+	PLAYBACK_UNAVAILABLE: {
+		Code:        PLAYBACK_UNAVAILABLE,
+		Title:       "Nothing to Control",
+		Description: "There is no playback this action can apply to.",
+		Suggestion:  "Start playing something on a Plex player, then try again.",
+		Retryable:   false,
+	},
 
 	// Discord Errors
 	DISCORD_NOT_RUNNING: {
@@ -126,6 +140,13 @@ var errorInfoMap = map[string]ErrorInfo{
 	},
 
 	// General Errors
+	INVALID_INPUT: {
+		Code:        INVALID_INPUT,
+		Title:       "Invalid Value",
+		Description: "The request contained a value that isn't accepted.",
+		Suggestion:  "Check the value and try again.",
+		Retryable:   false,
+	},
 	UNKNOWN_ERROR: {
 		Code:        UNKNOWN_ERROR,
 		Title:       "Unexpected Error",
@@ -171,7 +192,7 @@ func IsAuthError(code string) bool {
 // IsConnectionError returns whether the error is a connection-related issue.
 func IsConnectionError(code string) bool {
 	switch code {
-	case PLEX_UNREACHABLE, PLEX_CONN_FAILED, TIMEOUT,
+	case PLEX_UNREACHABLE, PLEX_CONN_FAILED, TIMEOUT, PLEX_SERVER_MISMATCH,
 		DISCORD_NOT_RUNNING, DISCORD_CONN_FAILED:
 		return true
 	}
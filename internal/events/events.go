@@ -19,9 +19,11 @@ const (
 	PlexConnectionError    = "PlexConnectionError"
 	PlexConnectionRestored = "PlexConnectionRestored"
 	PlexRetryState         = "PlexRetryState"
+	PlexServerRelocated    = "PlexServerRelocated"
 	DiscordConnected       = "DiscordConnected"
 	DiscordDisconnected    = "DiscordDisconnected"
 	DiscordRetryState      = "DiscordRetryState"
+	DiscordTargetsUpdated  = "DiscordTargetsUpdated"
 
 	// Update lifecycle events emitted while an in-app update is downloaded
 	// and applied. UpdateAvailable is emitted by the automatic update checker
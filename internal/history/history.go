@@ -9,11 +9,15 @@ import (
 	"time"
 )
 
-// Entry represents a single listening history entry.
+// Entry represents a single listening history entry. Track holds the title
+// of any media type; Artist and Album are only set for music and ShowTitle
+// only for TV episodes.
 type Entry struct {
+	MediaType string    `json:"mediaType,omitempty"` // "music", "movie", "tv", "photo"; empty in entries saved before other types were recorded
 	Track     string    `json:"track"`
 	Artist    string    `json:"artist"`
 	Album     string    `json:"album"`
+	ShowTitle string    `json:"showTitle,omitempty"`
 	Duration  int64     `json:"duration"`
 	StartedAt time.Time `json:"startedAt"`
 	ThumbURL  string    `json:"thumbUrl,omitempty"`
@@ -50,7 +54,7 @@ func NewStore(configDir string, maxEntries int) *Store {
 
 // Add inserts a new entry at the front of the history.
 // It deduplicates against the most recent entry to prevent poll-driven duplicates
-// (same track+artist+album as the last entry is skipped).
+// (same track+artist+album+show as the last entry is skipped).
 // The list is trimmed to maxEntries and auto-saved.
 func (s *Store) Add(entry Entry) {
 	s.mu.Lock()
@@ -59,7 +63,7 @@ func (s *Store) Add(entry Entry) {
 	// Deduplicate: skip if identical to the last entry
 	if len(s.entries) > 0 {
 		last := s.entries[0]
-		if last.Track == entry.Track && last.Artist == entry.Artist && last.Album == entry.Album {
+		if last.Track == entry.Track && last.Artist == entry.Artist && last.Album == entry.Album && last.ShowTitle == entry.ShowTitle {
 			return
 		}
 	}
@@ -93,23 +97,24 @@ func (s *Store) GetRecent(limit int) []Entry {
 	return result
 }
 
-// GetStats returns aggregate listening statistics.
+// GetStats returns aggregate listening statistics. Only music entries are
+// counted.
 func (s *Store) GetStats() Stats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	stats := Stats{
-		TotalTracks: len(s.entries),
-	}
-
-	if len(s.entries) == 0 {
-		return stats
-	}
-
+	var stats Stats
 	artistCounts := make(map[string]int)
 	for _, e := range s.entries {
+		if e.MediaType != "" && e.MediaType != "music" {
+			continue
+		}
+		stats.TotalTracks++
 		artistCounts[e.Artist]++
 	}
+	if stats.TotalTracks == 0 {
+		return stats
+	}
 
 	stats.UniqueArtists = len(artistCounts)
 
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"plexcord/internal/config"
 	"plexcord/internal/errors"
@@ -18,6 +19,33 @@ const fallbackFilename = ".credentials"
 
 // setTokenFallback encrypts and stores the token when OS keychain is unavailable
 func setTokenFallback(token string) error {
+	return setFallbackFile(fallbackFilename, token)
+}
+
+// getTokenFallback retrieves and decrypts the token from fallback storage
+func getTokenFallback() (string, error) {
+	return getFallbackFile(fallbackFilename)
+}
+
+// deleteTokenFallback removes the encrypted token file
+func deleteTokenFallback() error {
+	return deleteFallbackFile(fallbackFilename)
+}
+
+// serverFallbackFilename returns the fallback file for a per-server token.
+// Only characters safe in a filename are kept from the machine identifier.
+func serverFallbackFilename(machineID string) string {
+	safe := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, machineID)
+	return fallbackFilename + "-" + safe
+}
+
+// setFallbackFile encrypts and stores a secret in the named fallback file
+func setFallbackFile(filename, token string) error {
 	// Derive encryption key from machine-specific data
 	key := deriveMachineKey()
 
@@ -31,7 +59,7 @@ func setTokenFallback(token string) error {
 	encoded := base64.StdEncoding.EncodeToString(encrypted)
 
 	// Get fallback file path
-	credPath, err := getFallbackPath()
+	credPath, err := getFallbackPath(filename)
 	if err != nil {
 		return err
 	}
@@ -50,9 +78,9 @@ func setTokenFallback(token string) error {
 	return nil
 }
 
-// getTokenFallback retrieves and decrypts the token from fallback storage
-func getTokenFallback() (string, error) {
-	credPath, err := getFallbackPath()
+// getFallbackFile retrieves and decrypts a secret from the named fallback file
+func getFallbackFile(filename string) (string, error) {
+	credPath, err := getFallbackPath(filename)
 	if err != nil {
 		return "", err
 	}
@@ -87,9 +115,9 @@ func getTokenFallback() (string, error) {
 	return string(decrypted), nil
 }
 
-// deleteTokenFallback removes the encrypted token file
-func deleteTokenFallback() error {
-	credPath, err := getFallbackPath()
+// deleteFallbackFile removes the named encrypted fallback file
+func deleteFallbackFile(filename string) error {
+	credPath, err := getFallbackPath(filename)
 	if err != nil {
 		return err
 	}
@@ -103,8 +131,8 @@ func deleteTokenFallback() error {
 	return nil
 }
 
-// getFallbackPath returns the path to the fallback credentials file
-func getFallbackPath() (string, error) {
+// getFallbackPath returns the path to the named fallback credentials file
+func getFallbackPath(filename string) (string, error) {
 	// Get config directory
 	configPath, err := config.GetConfigPath()
 	if err != nil {
@@ -112,7 +140,7 @@ func getFallbackPath() (string, error) {
 	}
 
 	// Fallback file is in same directory as config
-	credPath := filepath.Join(filepath.Dir(configPath), fallbackFilename)
+	credPath := filepath.Join(filepath.Dir(configPath), filename)
 	return credPath, nil
 }
 
@@ -246,7 +246,7 @@ func TestFallbackFileEncryption(t *testing.T) {
 	}
 
 	// Read the file directly
-	credPath, err := getFallbackPath()
+	credPath, err := getFallbackPath(fallbackFilename)
 	if err != nil {
 		t.Fatalf("getFallbackPath failed: %v", err)
 	}
@@ -1,6 +1,8 @@
 package keychain
 
 import (
+	"log"
+
 	"plexcord/internal/errors"
 
 	"github.com/zalando/go-keyring"
@@ -11,6 +13,9 @@ const (
 	ServiceName = "PlexCord"
 	// TokenKey is the account name for the Plex authentication token
 	TokenKey = "plex-token"
+	// serverTokenPrefix prefixes the account name of per-server access tokens,
+	// which are keyed by the server's machine identifier.
+	serverTokenPrefix = "plex-token-"
 )
 
 // SetToken stores the Plex authentication token securely in the OS keychain.
@@ -111,3 +116,61 @@ func DeleteToken() error {
 	}
 	return nil
 }
+
+// SetServerToken stores the access token for a single Plex server, keyed by
+// the server's machine identifier. Shared (friends') servers reject the
+// account token and need the per-server token from the plex.tv resources API.
+// Falls back to encrypted file storage like SetToken.
+func SetServerToken(machineID, token string) error {
+	if machineID == "" {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "server identifier cannot be empty")
+	}
+	if token == "" {
+		return errors.New(errors.CONFIG_WRITE_FAILED, "token cannot be empty")
+	}
+
+	if err := keyring.Set(ServiceName, serverTokenPrefix+machineID, token); err != nil {
+		if fallbackErr := setFallbackFile(serverFallbackFilename(machineID), token); fallbackErr != nil {
+			return errors.Wrap(fallbackErr, errors.KEYCHAIN_STORE_FAILED, "failed to store server token in keychain or fallback")
+		}
+	}
+	return nil
+}
+
+// GetServerToken retrieves the access token stored for a Plex server.
+// Returns an empty string (not an error) if none has been stored, in which
+// case callers use the account token.
+func GetServerToken(machineID string) (string, error) {
+	if machineID == "" {
+		return "", nil
+	}
+
+	token, err := keyring.Get(ServiceName, serverTokenPrefix+machineID)
+	if err != nil {
+		if err == keyring.ErrNotFound {
+			return "", nil
+		}
+		fallbackToken, fallbackErr := getFallbackFile(serverFallbackFilename(machineID))
+		if fallbackErr != nil {
+			return "", errors.Wrap(fallbackErr, errors.KEYCHAIN_READ_FAILED, "failed to retrieve server token from keychain or fallback")
+		}
+		return fallbackToken, nil
+	}
+	return token, nil
+}
+
+// DeleteServerToken removes the access token stored for a Plex server.
+// Deleting a token that was never stored is not an error.
+func DeleteServerToken(machineID string) error {
+	if machineID == "" {
+		return nil
+	}
+
+	if err := keyring.Delete(ServiceName, serverTokenPrefix+machineID); err != nil && err != keyring.ErrNotFound {
+		log.Printf("Warning: Failed to delete server token from keychain: %v", err)
+	}
+	if err := deleteFallbackFile(serverFallbackFilename(machineID)); err != nil {
+		return errors.Wrap(err, errors.KEYCHAIN_READ_FAILED, "failed to delete server token fallback")
+	}
+	return nil
+}
@@ -220,3 +220,53 @@ func TestMain(m *testing.M) {
 
 	os.Exit(code)
 }
+
+// TestServerTokenRoundTrip tests per-server tokens are stored separately
+// from the account token and can be deleted independently
+func TestServerTokenRoundTrip(t *testing.T) {
+	const machineID = "test-machine-abc123"
+	_ = DeleteServerToken(machineID)
+
+	if err := SetServerToken(machineID, "server-token"); err != nil {
+		t.Fatalf("SetServerToken failed: %v", err)
+	}
+
+	token, err := GetServerToken(machineID)
+	if err != nil {
+		t.Fatalf("GetServerToken failed: %v", err)
+	}
+	if token != "server-token" {
+		t.Errorf("Expected server token %q, got %q", "server-token", token)
+	}
+
+	if err := DeleteServerToken(machineID); err != nil {
+		t.Fatalf("DeleteServerToken failed: %v", err)
+	}
+	token, err = GetServerToken(machineID)
+	if err != nil {
+		t.Errorf("GetServerToken should not error after delete, got: %v", err)
+	}
+	if token != "" {
+		t.Errorf("Expected empty token after delete, got %q", token)
+	}
+}
+
+// TestServerTokenValidation tests empty identifiers and tokens are rejected
+func TestServerTokenValidation(t *testing.T) {
+	if err := SetServerToken("", "token"); err == nil {
+		t.Error("Expected error for empty server identifier")
+	}
+	if err := SetServerToken("machine", ""); err == nil {
+		t.Error("Expected error for empty token")
+	}
+	if token, err := GetServerToken(""); err != nil || token != "" {
+		t.Errorf("Expected empty result for empty identifier, got %q, %v", token, err)
+	}
+}
+
+// TestServerFallbackFilename tests machine identifiers can't escape the config dir
+func TestServerFallbackFilename(t *testing.T) {
+	if got := serverFallbackFilename("../evil/id"); got != ".credentials-evilid" {
+		t.Errorf("Expected sanitized filename, got %q", got)
+	}
+}
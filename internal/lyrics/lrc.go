@@ -0,0 +1,113 @@
+// Package lyrics parses time-synced lyrics in the LRC format and finds
+// sidecar .lrc files, so the line being sung can be shown on Discord.
+//
+// Only synced lyrics are useful here: plain text without timestamps has no
+// current line, so Parse reports it as no lyrics at all.
+package lyrics
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line is a lyric line and when it starts.
+type Line struct {
+	Time time.Duration // From the start of the track
+	Text string        // Empty for instrumental breaks
+}
+
+// Lyrics are a track's synced lyric lines, sorted by time.
+type Lyrics struct {
+	Lines []Line
+}
+
+var (
+	// timeTag matches a line's [mm:ss], [mm:ss.xx] or [mm:ss:xx] tag.
+	timeTag = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+
+	// offsetTag matches the [offset:±ms] header tag.
+	offsetTag = regexp.MustCompile(`^\[offset:\s*([+-]?\d+)\s*\]`)
+
+	// wordTag matches the per-word <mm:ss.xx> tags of enhanced LRC.
+	wordTag = regexp.MustCompile(`<\d+:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+// Parse parses LRC text. A line may carry several time tags to repeat it
+// (e.g. a chorus), and the [offset] tag shifts every line: a positive offset
+// shows lines earlier. Other header tags ([ar:], [ti:], ...) are ignored.
+// Returns nil when the text has no timed lines.
+func Parse(text string) *Lyrics {
+	var lines []Line
+	var offset time.Duration
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSpace(raw)
+		if m := offsetTag.FindStringSubmatch(raw); m != nil {
+			ms, _ := strconv.Atoi(m[1])
+			offset = time.Duration(ms) * time.Millisecond
+			continue
+		}
+
+		var times []time.Duration
+		for {
+			m := timeTag.FindStringSubmatch(raw)
+			if m == nil {
+				break
+			}
+			times = append(times, tagTime(m[1], m[2], m[3]))
+			raw = raw[len(m[0]):]
+		}
+		text := strings.TrimSpace(wordTag.ReplaceAllString(raw, ""))
+		for _, t := range times {
+			lines = append(lines, Line{Time: t, Text: text})
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	for i := range lines {
+		lines[i].Time -= offset
+		if lines[i].Time < 0 {
+			lines[i].Time = 0
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time < lines[j].Time })
+	return &Lyrics{Lines: lines}
+}
+
+// tagTime converts a time tag's minutes, seconds and fraction. The fraction
+// is hundredths with two digits and milliseconds with three.
+func tagTime(min, sec, frac string) time.Duration {
+	m, _ := strconv.Atoi(min)
+	s, _ := strconv.Atoi(sec)
+	d := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if frac != "" {
+		f, _ := strconv.Atoi(frac)
+		switch len(frac) {
+		case 1:
+			d += time.Duration(f) * 100 * time.Millisecond
+		case 2:
+			d += time.Duration(f) * 10 * time.Millisecond
+		default:
+			d += time.Duration(f) * time.Millisecond
+		}
+	}
+	return d
+}
+
+// LineAt returns the line being sung at position, or "" before the first
+// line and during instrumental breaks.
+func (l *Lyrics) LineAt(position time.Duration) string {
+	if l == nil {
+		return ""
+	}
+	// Index of the first line starting after position
+	i := sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].Time > position })
+	if i == 0 {
+		return ""
+	}
+	return l.Lines[i-1].Text
+}
@@ -0,0 +1,57 @@
+package lyrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAndLineAt(t *testing.T) {
+	l := Parse(`[ar:Def Leppard]
+[ti:Hysteria]
+[00:12.50]Out of touch, out of reach yeah
+[00:16.00][01:02.00]Hysteria
+[00:20.1]<00:20.1>When <00:20.5>you're <00:21.0>near
+[00:25.00]
+`)
+	if l == nil || len(l.Lines) != 5 {
+		t.Fatalf("expected 5 timed lines, got %+v", l)
+	}
+
+	tests := []struct {
+		position time.Duration
+		want     string
+	}{
+		{5 * time.Second, ""},
+		{12500 * time.Millisecond, "Out of touch, out of reach yeah"},
+		{17 * time.Second, "Hysteria"},
+		{21 * time.Second, "When you're near"},
+		{30 * time.Second, ""},
+		{65 * time.Second, "Hysteria"},
+	}
+	for _, tt := range tests {
+		if got := l.LineAt(tt.position); got != tt.want {
+			t.Errorf("LineAt(%v) = %q, want %q", tt.position, got, tt.want)
+		}
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	l := Parse("[offset:+500]\n[00:10.00]Line")
+	if l == nil || l.Lines[0].Time != 9500*time.Millisecond {
+		t.Errorf("expected the line shown 500ms earlier, got %+v", l)
+	}
+	l = Parse("[offset:-250]\n[00:10.000]Line")
+	if l == nil || l.Lines[0].Time != 10250*time.Millisecond {
+		t.Errorf("expected the line shown 250ms later, got %+v", l)
+	}
+}
+
+func TestParseUntimed(t *testing.T) {
+	if l := Parse("Just some\nplain lyrics"); l != nil {
+		t.Errorf("expected nil for unsynced lyrics, got %+v", l)
+	}
+	var none *Lyrics
+	if got := none.LineAt(time.Minute); got != "" {
+		t.Errorf("LineAt on nil lyrics = %q, want empty", got)
+	}
+}
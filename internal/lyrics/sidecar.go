@@ -0,0 +1,69 @@
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// invalidNameChars are removed before comparing file names, since taggers
+// and rippers drop or replace them differently.
+const invalidNameChars = `/\:*?"<>|`
+
+// FindSidecar looks in folder for the .lrc file of a track, named
+// "Artist - Title.lrc" or "Title.lrc". Names are compared case-insensitively
+// and without characters that aren't allowed in file names. Returns "" when
+// there is none.
+func FindSidecar(folder, artist, title string) (string, error) {
+	if folder == "" || title == "" {
+		return "", nil
+	}
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{normalizeName(title)}
+	if artist != "" {
+		candidates = append([]string{normalizeName(artist + " - " + title)}, candidates...)
+	}
+	names := make(map[string]string, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(name), ".lrc") {
+			continue
+		}
+		names[normalizeName(strings.TrimSuffix(name, filepath.Ext(name)))] = name
+	}
+	for _, c := range candidates {
+		if name, ok := names[c]; ok {
+			return filepath.Join(folder, name), nil
+		}
+	}
+	return "", nil
+}
+
+// LoadSidecar finds and parses the .lrc file of a track in folder. Returns
+// nil when there is none or it has no timed lines.
+func LoadSidecar(folder, artist, title string) (*Lyrics, error) {
+	path, err := FindSidecar(folder, artist, title)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// normalizeName lowercases a file name and strips invalid characters.
+func normalizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidNameChars, r) {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,50 @@
+package lyrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindSidecar(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"AC_DC - Thunderstruck.lrc", "Hysteria.LRC", "Animal.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("[00:01.00]Line"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		artist, title string
+		want          string
+	}{
+		{"Def Leppard", "Hysteria", "Hysteria.LRC"}, // Title only, any case
+		{"AC/DC", "Thunderstruck", ""},              // "/" dropped, "_" kept: no match
+		{"Def Leppard", "Animal", ""},               // Not an .lrc file
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := FindSidecar(dir, tt.artist, tt.title)
+		if err != nil {
+			t.Fatalf("FindSidecar(%q, %q) error = %v", tt.artist, tt.title, err)
+		}
+		if tt.want != "" {
+			tt.want = filepath.Join(dir, tt.want)
+		}
+		if got != tt.want {
+			t.Errorf("FindSidecar(%q, %q) = %q, want %q", tt.artist, tt.title, got, tt.want)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ACDC - Thunderstruck.lrc"), []byte("[00:01.00]Thunder"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := LoadSidecar(dir, "AC/DC", "Thunderstruck")
+	if err != nil || l == nil || l.Lines[0].Text != "Thunder" {
+		t.Errorf("LoadSidecar() = %+v, %v", l, err)
+	}
+
+	if _, err := FindSidecar(filepath.Join(dir, "missing"), "A", "B"); err == nil {
+		t.Error("expected an error for a missing folder")
+	}
+}
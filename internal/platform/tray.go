@@ -11,6 +11,7 @@ package platform
 import (
 	"log"
 	"runtime"
+	"strings"
 	"sync"
 
 	"github.com/energye/systray"
@@ -20,6 +21,10 @@ import (
 type TrayCallbacks struct {
 	OnShow func() // Called when the user asks to show/restore the window
 	OnQuit func() // Called when the user asks to quit the application
+
+	// Optional current track actions; their menu items are only shown when set
+	OnRate       func(stars int)   // 1-5 stars, 0 to clear the rating
+	OnMarkPlayed func(played bool) // Mark the current track played or unplayed
 }
 
 // TrayManager manages the system tray icon and its menu.
@@ -71,6 +76,8 @@ func (tm *TrayManager) onReady() {
 	systray.SetTitle("PlexCord")
 	systray.SetTooltip(tm.tooltip)
 
+	tm.addTrackItems()
+
 	mShow := systray.AddMenuItem("Show PlexCord", "Bring the PlexCord window to the foreground")
 	mQuit := systray.AddMenuItem("Quit", "Quit PlexCord completely")
 
@@ -84,6 +91,30 @@ func (tm *TrayManager) onReady() {
 	log.Printf("System tray: ready")
 }
 
+// addTrackItems adds the menu items acting on the current track, followed by
+// a separator, for the callbacks that are set.
+func (tm *TrayManager) addTrackItems() {
+	if tm.callbacks.OnRate == nil && tm.callbacks.OnMarkPlayed == nil {
+		return
+	}
+
+	if tm.callbacks.OnRate != nil {
+		mRate := systray.AddMenuItem("Rate Current Track", "Rate the track playing in Plex")
+		for stars := 5; stars >= 1; stars-- {
+			title := strings.Repeat("★", stars) + strings.Repeat("☆", 5-stars)
+			mRate.AddSubMenuItem(title, "").Click(func() { tm.callbacks.OnRate(stars) })
+		}
+		mRate.AddSubMenuItem("Clear Rating", "").Click(func() { tm.callbacks.OnRate(0) })
+	}
+	if tm.callbacks.OnMarkPlayed != nil {
+		systray.AddMenuItem("Mark as Played", "Mark the current item as played").
+			Click(func() { tm.callbacks.OnMarkPlayed(true) })
+		systray.AddMenuItem("Mark as Unplayed", "Mark the current item as unplayed").
+			Click(func() { tm.callbacks.OnMarkPlayed(false) })
+	}
+	systray.AddSeparator()
+}
+
 // onExit runs in the systray event loop when the tray is torn down.
 func (tm *TrayManager) onExit() {
 	log.Printf("System tray: exited")
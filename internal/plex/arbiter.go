@@ -0,0 +1,181 @@
+package plex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SessionArbiter merges the session streams of several pollers (one per
+// server) into a single stream that carries only the session that should
+// drive presence.
+//
+// Selection rules, applied whenever any source reports a change:
+//  1. A playing session beats a paused (or buffering) one.
+//  2. Among sessions in the same state, the one that most recently started
+//     (StartedAt as stamped by the source, else when the arbiter first saw
+//     the item) or changed state wins. A session re-emitted for a seek or
+//     new metadata keeps its place.
+//
+// The output emits nil once no source has an active session, mirroring a
+// single poller's "playback stopped" signal.
+type SessionArbiter struct {
+	current map[string]arbitratedSession    // Latest session per source
+	sources map[string]<-chan *MediaSession // Channel read per source (see Add)
+	winner  string                          // Source of the last emitted session ("" = none)
+	out     chan *MediaSession
+	wg      sync.WaitGroup // Running source readers
+	mu      sync.Mutex
+}
+
+// arbitratedSession is a source's latest session plus when it started or
+// last changed state.
+type arbitratedSession struct {
+	started time.Time
+	session *MediaSession
+}
+
+// NewSessionArbiter creates an arbiter with no sources.
+func NewSessionArbiter() *SessionArbiter {
+	return &SessionArbiter{
+		current: make(map[string]arbitratedSession),
+		sources: make(map[string]<-chan *MediaSession),
+		out:     make(chan *MediaSession, 1),
+	}
+}
+
+// Run consumes every source channel until all are closed (or ctx is
+// cancelled) and returns the merged channel. The key of each source is an
+// opaque identifier, typically the server URL. The returned channel is closed
+// once all sources are drained.
+func (a *SessionArbiter) Run(ctx context.Context, sources map[string]<-chan *MediaSession) <-chan *MediaSession {
+	for source, ch := range sources {
+		a.Add(ctx, source, ch)
+	}
+
+	go func() {
+		a.wg.Wait()
+		close(a.out)
+	}()
+
+	return a.out
+}
+
+// Add starts reading ch as source's stream after Run, in place of the
+// channel source had, e.g. when the server's poller is restarted. The
+// replaced channel is ignored from then on. Call Add before stopping the
+// previous source so the merged channel stays open.
+func (a *SessionArbiter) Add(ctx context.Context, source string, ch <-chan *MediaSession) {
+	a.mu.Lock()
+	a.sources[source] = ch
+	a.mu.Unlock()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case session, ok := <-ch:
+				if !ok {
+					// A closed source no longer has a session to offer.
+					a.receive(ctx, source, ch, nil)
+					return
+				}
+				a.receive(ctx, source, ch, session)
+			}
+		}
+	}()
+}
+
+// Drop forgets source's session, e.g. while its server can't be reached,
+// and emits the new selection if that changes it. The source's next session
+// brings it back.
+func (a *SessionArbiter) Drop(ctx context.Context, source string) {
+	a.update(ctx, source, nil)
+}
+
+// receive is update for a session read from ch, ignored once ch no longer
+// is source's channel.
+func (a *SessionArbiter) receive(ctx context.Context, source string, ch <-chan *MediaSession, session *MediaSession) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.sources[source] != ch {
+		return
+	}
+	a.updateLocked(ctx, source, session)
+}
+
+// update records a source's new session and emits the winner if the selected
+// session changed as a result.
+func (a *SessionArbiter) update(ctx context.Context, source string, session *MediaSession) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.updateLocked(ctx, source, session)
+}
+
+// updateLocked is update with a.mu held.
+func (a *SessionArbiter) updateLocked(ctx context.Context, source string, session *MediaSession) {
+	if session == nil {
+		delete(a.current, source)
+	} else {
+		now := time.Now()
+		started := session.StartedAt
+		prev, ok := a.current[source]
+		if ok && prev.session.SessionKey == session.SessionKey && prev.session.RatingKey == session.RatingKey {
+			if prev.session.State != session.State {
+				started = now
+			} else if prev.started.After(started) {
+				started = prev.started
+			}
+		}
+		if started.IsZero() {
+			started = now
+		}
+		a.current[source] = arbitratedSession{session: session, started: started}
+	}
+
+	winner := a.pick()
+	// Sources other than the winner changing doesn't alter what's shown,
+	// unless the winner itself went away.
+	if winner != source && winner == a.winner {
+		return
+	}
+	if winner == "" && a.winner == "" {
+		return
+	}
+	a.winner = winner
+
+	var selected *MediaSession
+	if winner != "" {
+		selected = a.current[winner].session
+	}
+
+	select {
+	case a.out <- selected:
+	case <-ctx.Done():
+	}
+}
+
+// pick returns the source whose session should be shown, or "" if none.
+// Must be called with a.mu held.
+func (a *SessionArbiter) pick() string {
+	best := ""
+	for source, candidate := range a.current {
+		if best == "" {
+			best = source
+			continue
+		}
+		current := a.current[best]
+		candidatePlaying := candidate.session.State == "playing"
+		currentPlaying := current.session.State == "playing"
+		switch {
+		case candidatePlaying && !currentPlaying:
+			best = source
+		case candidatePlaying == currentPlaying && candidate.started.After(current.started):
+			best = source
+		}
+	}
+	return best
+}
@@ -0,0 +1,239 @@
+package plex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func arbiterSession(key, state string) *MediaSession {
+	return &MediaSession{SessionKey: key, State: state, Title: key}
+}
+
+// recvSession reads the next arbitrated session or fails after a timeout.
+func recvSession(t *testing.T, ch <-chan *MediaSession) *MediaSession {
+	t.Helper()
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for arbitrated session")
+		return nil
+	}
+}
+
+func TestSessionArbiterPrefersPlayingOverPaused(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	out := NewSessionArbiter().Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	a <- arbiterSession("a", "playing")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "a" {
+		t.Fatalf("expected session a, got %+v", got)
+	}
+
+	// A newer paused session must not displace the playing one
+	b <- arbiterSession("b", "paused")
+	select {
+	case got := <-out:
+		t.Fatalf("unexpected emission for paused session: %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Once a pauses, the most recently updated paused session wins
+	a <- arbiterSession("a", "paused")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "a" || got.State != "paused" {
+		t.Fatalf("expected paused session a, got %+v", got)
+	}
+
+	// b starts playing and takes over
+	b <- arbiterSession("b", "playing")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "b" {
+		t.Fatalf("expected session b, got %+v", got)
+	}
+}
+
+func TestSessionArbiterPrefersMostRecentWhenBothPlaying(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	out := NewSessionArbiter().Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	a <- arbiterSession("a", "playing")
+	recvSession(t, out)
+
+	time.Sleep(5 * time.Millisecond)
+	b <- arbiterSession("b", "playing")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "b" {
+		t.Fatalf("expected most recent session b, got %+v", got)
+	}
+}
+
+func TestSessionArbiterIgnoresReEmissions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	out := NewSessionArbiter().Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	now := time.Now()
+	older := arbiterSession("b", "playing")
+	older.StartedAt = now.Add(-time.Minute)
+	b <- older
+	recvSession(t, out)
+	newer := arbiterSession("a", "playing")
+	newer.StartedAt = now
+	a <- newer
+	if got := recvSession(t, out); got == nil || got.SessionKey != "a" {
+		t.Fatalf("expected the most recently started session a, got %+v", got)
+	}
+
+	// b seeks: it emits again but didn't start later than a
+	seeked := *older
+	seeked.ViewOffset = 60000
+	b <- &seeked
+	select {
+	case got := <-out:
+		t.Fatalf("unexpected emission for a re-emitted session: %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSessionArbiterKeepsFirstSeenForUnstampedSessions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	out := NewSessionArbiter().Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	b <- arbiterSession("b", "playing")
+	recvSession(t, out)
+	time.Sleep(5 * time.Millisecond)
+	a <- arbiterSession("a", "playing")
+	recvSession(t, out)
+
+	// The same item from b again doesn't count as a newer start
+	time.Sleep(5 * time.Millisecond)
+	b <- arbiterSession("b", "playing")
+	select {
+	case got := <-out:
+		t.Fatalf("unexpected emission for a re-emitted session: %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSessionArbiterAddReplacesSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	old := make(chan *MediaSession)
+	arbiter := NewSessionArbiter()
+	out := arbiter.Run(ctx, map[string]<-chan *MediaSession{"a": old})
+	old <- arbiterSession("a", "playing")
+	recvSession(t, out)
+
+	// The poller of a restarts: its new channel takes over and closing
+	// the old one neither withdraws the session nor closes the output
+	restarted := make(chan *MediaSession)
+	arbiter.Add(ctx, "a", restarted)
+	close(old)
+	select {
+	case got, ok := <-out:
+		t.Fatalf("unexpected emission for the replaced channel: %+v (open=%v)", got, ok)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	restarted <- arbiterSession("a2", "playing")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "a2" {
+		t.Fatalf("expected the restarted source's session, got %+v", got)
+	}
+	close(restarted)
+	if got := recvSession(t, out); got != nil {
+		t.Fatalf("expected nil once the source stops, got %+v", got)
+	}
+}
+
+func TestSessionArbiterEmitsNilWhenAllStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	out := NewSessionArbiter().Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	a <- arbiterSession("a", "playing")
+	recvSession(t, out)
+	b <- arbiterSession("b", "paused")
+	select {
+	case got := <-out:
+		t.Fatalf("unexpected emission for paused session: %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Winner stops: fall back to the remaining session
+	a <- nil
+	if got := recvSession(t, out); got == nil || got.SessionKey != "b" {
+		t.Fatalf("expected fallback to session b, got %+v", got)
+	}
+
+	b <- nil
+	if got := recvSession(t, out); got != nil {
+		t.Fatalf("expected nil after all sessions stopped, got %+v", got)
+	}
+
+	// Closing every source closes the merged channel
+	close(a)
+	close(b)
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("expected merged channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout waiting for merged channel to close")
+	}
+}
+
+func TestSessionArbiterDropFallsBackToOtherSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := make(chan *MediaSession)
+	b := make(chan *MediaSession)
+	arbiter := NewSessionArbiter()
+	out := arbiter.Run(ctx, map[string]<-chan *MediaSession{"a": a, "b": b})
+
+	a <- arbiterSession("a", "playing")
+	recvSession(t, out)
+	time.Sleep(5 * time.Millisecond)
+	b <- arbiterSession("b", "playing")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "b" {
+		t.Fatalf("expected session b, got %+v", got)
+	}
+
+	// b's server fails: a, still playing the same item, is shown again
+	arbiter.Drop(ctx, "b")
+	if got := recvSession(t, out); got == nil || got.SessionKey != "a" {
+		t.Fatalf("expected fallback to session a, got %+v", got)
+	}
+
+	// Dropping a source that isn't shown changes nothing
+	arbiter.Drop(ctx, "b")
+	select {
+	case got := <-out:
+		t.Fatalf("unexpected emission: %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	arbiter.Drop(ctx, "a")
+	if got := recvSession(t, out); got != nil {
+		t.Fatalf("expected nil once no source has a session, got %+v", got)
+	}
+}
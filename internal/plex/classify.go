@@ -0,0 +1,188 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+// sectionsRefreshAfter is how long the library list is trusted before a
+// session from an unknown section triggers a refetch.
+const sectionsRefreshAfter = 5 * time.Minute
+
+// LibrarySection is a library on the server, as listed by /library/sections.
+type LibrarySection struct {
+	ID    string `xml:"key,attr" json:"id"`
+	Title string `xml:"title,attr" json:"title"`
+	Type  string `xml:"type,attr" json:"type"`   // "artist", "movie", "show", "photo"
+	Agent string `xml:"agent,attr" json:"agent"` // Metadata agent, e.g. "com.plexapp.agents.audnexus"
+}
+
+// librarySectionsResponse is the XML response of /library/sections.
+type librarySectionsResponse struct {
+	XMLName  xml.Name         `xml:"MediaContainer"`
+	Sections []LibrarySection `xml:"Directory"`
+}
+
+// GetLibrarySections lists the server's libraries.
+func (c *Client) GetLibrarySections() ([]LibrarySection, error) {
+	var body []byte
+	err := c.withFailover(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		var err error
+		body, err = c.transport().get(ctx, "/library/sections")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp librarySectionsResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid library sections response")
+	}
+	return resp.Sections, nil
+}
+
+// sectionMediaType returns the media type a library's agent implies for the
+// audio in it: audiobook agents (Audnexus, Audiobooks) and podcast agents
+// mark the whole library, anything else keeps the item's own type.
+func sectionMediaType(section LibrarySection) string {
+	agent := strings.ToLower(section.Agent)
+	switch {
+	case strings.Contains(agent, "audiobook"), strings.Contains(agent, "audnexus"):
+		return MediaTypeAudiobook
+	case strings.Contains(agent, "podcast"):
+		return MediaTypePodcast
+	default:
+		return ""
+	}
+}
+
+// LibraryClassifier reclassifies music and TV sessions as audiobooks or
+// podcasts based on the library they come from: either a library the user
+// picked, or one whose metadata agent is an audiobook or podcast agent.
+// Section IDs are the server's own, so a classifier serves a single server.
+// Safe for concurrent use.
+type LibraryClassifier struct {
+	client            *Client
+	audiobookSections map[string]bool
+	podcastSections   map[string]bool
+	sectionsFetchedAt time.Time
+	sectionMediaTypes map[string]string // Section ID → media type implied by its agent
+	mu                sync.Mutex
+}
+
+// NewLibraryClassifier creates a classifier for client's server. Sessions
+// from audiobookSectionIDs and podcastSectionIDs are always classified as
+// such; other libraries are classified by their agent.
+func NewLibraryClassifier(client *Client, audiobookSectionIDs, podcastSectionIDs []string) *LibraryClassifier {
+	c := &LibraryClassifier{
+		client:            client,
+		audiobookSections: make(map[string]bool, len(audiobookSectionIDs)),
+		podcastSections:   make(map[string]bool, len(podcastSectionIDs)),
+	}
+	for _, id := range audiobookSectionIDs {
+		c.audiobookSections[id] = true
+	}
+	for _, id := range podcastSectionIDs {
+		c.podcastSections[id] = true
+	}
+	return c
+}
+
+// Classify changes the media type of a music or TV session from an
+// audiobook or podcast library. Other sessions are left unchanged.
+func (c *LibraryClassifier) Classify(session *MediaSession) {
+	if session == nil || session.LibrarySectionID == "" {
+		return
+	}
+	if session.MediaType != MediaTypeMusic && session.MediaType != MediaTypeTV {
+		return
+	}
+
+	id := session.LibrarySectionID
+	mediaType := ""
+	switch {
+	case c.audiobookSections[id]:
+		mediaType = MediaTypeAudiobook
+	case c.podcastSections[id]:
+		mediaType = MediaTypePodcast
+	default:
+		mediaType = c.sectionMediaType(id)
+	}
+
+	switch {
+	case mediaType == MediaTypeAudiobook && session.MediaType == MediaTypeMusic:
+		session.MediaType = MediaTypeAudiobook
+	case mediaType == MediaTypePodcast:
+		if session.MediaType == MediaTypeMusic {
+			// Tagged as music: the album (or artist) is the show
+			session.ShowTitle = session.Album
+			if session.ShowTitle == "" || session.ShowTitle == FallbackAlbum {
+				session.ShowTitle = session.Artist
+			}
+		}
+		session.MediaType = MediaTypePodcast
+		session.ApplyFallbacks()
+	}
+}
+
+// Filter classifies sessions and keeps those of the requested media types
+// (empty = all), preserving order.
+func (c *LibraryClassifier) Filter(sessions []MediaSession, mediaTypes []string) []MediaSession {
+	result := sessions[:0]
+	for i := range sessions {
+		c.Classify(&sessions[i])
+		if len(mediaTypes) > 0 && !containsString(mediaTypes, sessions[i].MediaType) {
+			continue
+		}
+		result = append(result, sessions[i])
+	}
+	return result
+}
+
+// sectionMediaType returns the media type implied by a section's agent,
+// fetching the library list on first use and when id isn't in it.
+func (c *LibraryClassifier) sectionMediaType(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if mediaType, ok := c.sectionMediaTypes[id]; ok {
+		return mediaType
+	}
+	if time.Since(c.sectionsFetchedAt) < sectionsRefreshAfter {
+		return ""
+	}
+
+	// Failures are remembered too, so an unreachable endpoint isn't asked on
+	// every poll.
+	c.sectionsFetchedAt = time.Now()
+	sections, err := c.client.GetLibrarySections()
+	if err != nil {
+		log.Printf("Library sections lookup failed: %v", err)
+		return ""
+	}
+	c.sectionMediaTypes = make(map[string]string, len(sections))
+	for _, s := range sections {
+		c.sectionMediaTypes[s.ID] = sectionMediaType(s)
+	}
+	return c.sectionMediaTypes[id]
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
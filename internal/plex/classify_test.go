@@ -0,0 +1,83 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const librarySectionsXML = `<MediaContainer size="3">
+  <Directory key="1" type="artist" title="Music" agent="tv.plex.agents.music"/>
+  <Directory key="2" type="artist" title="Audiobooks" agent="com.plexapp.agents.audnexus"/>
+  <Directory key="3" type="show" title="TV Shows" agent="tv.plex.agents.series"/>
+</MediaContainer>`
+
+func newSectionsServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/sections" {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+		_, _ = w.Write([]byte(librarySectionsXML))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLibraryClassifier_ClassifiesByAgentAndChoice(t *testing.T) {
+	var requests atomic.Int32
+	server := newSectionsServer(t, &requests)
+	classifier := NewLibraryClassifier(NewClient("token", server.URL), nil, []string{"3"})
+
+	book := MediaSession{MediaType: MediaTypeMusic, LibrarySectionID: "2", Title: "Chapter 1", Album: "The Hobbit"}
+	classifier.Classify(&book)
+	if book.MediaType != MediaTypeAudiobook {
+		t.Errorf("track in an Audnexus library: got %q, want audiobook", book.MediaType)
+	}
+
+	song := MediaSession{MediaType: MediaTypeMusic, LibrarySectionID: "1"}
+	classifier.Classify(&song)
+	if song.MediaType != MediaTypeMusic {
+		t.Errorf("track in a music library: got %q, want music", song.MediaType)
+	}
+
+	episode := MediaSession{MediaType: MediaTypeTV, LibrarySectionID: "3", Title: "Tides", ShowTitle: "Ocean Stories"}
+	classifier.Classify(&episode)
+	if episode.MediaType != MediaTypePodcast || episode.ShowTitle != "Ocean Stories" {
+		t.Errorf("episode in a chosen podcast library: got %+v", episode)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected the library list to be fetched once, got %d requests", got)
+	}
+}
+
+func TestLibraryClassifier_PodcastTaggedAsMusic(t *testing.T) {
+	classifier := NewLibraryClassifier(NewClient("token", "http://127.0.0.1:1"), nil, []string{"7"})
+
+	session := MediaSession{MediaType: MediaTypeMusic, LibrarySectionID: "7", Title: "Ep. 4", Artist: "Host", Album: "The Show"}
+	classifier.Classify(&session)
+
+	if session.MediaType != MediaTypePodcast || session.ShowTitle != "The Show" {
+		t.Errorf("expected a podcast of The Show, got %+v", session)
+	}
+}
+
+func TestLibraryClassifier_FilterAppliesMediaTypes(t *testing.T) {
+	var requests atomic.Int32
+	server := newSectionsServer(t, &requests)
+	classifier := NewLibraryClassifier(NewClient("token", server.URL), nil, nil)
+
+	sessions := []MediaSession{
+		{SessionKey: "book", MediaType: MediaTypeMusic, LibrarySectionID: "2"},
+		{SessionKey: "song", MediaType: MediaTypeMusic, LibrarySectionID: "1"},
+	}
+
+	got := classifier.Filter(sessions, []string{MediaTypeMusic})
+	if len(got) != 1 || got[0].SessionKey != "song" {
+		t.Errorf("music only should drop the audiobook, got %+v", got)
+	}
+}
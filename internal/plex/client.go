@@ -9,16 +9,27 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"plexcord/internal/errors"
 )
 
-// Client handles Plex Media Server communication
+// Client handles Plex Media Server communication.
+//
+// A client may know several candidate URLs for its server (see
+// SetConnections); serverURL is the one currently in use and can change at
+// runtime when the client fails over, hence the mutex.
 type Client struct {
+	lastRace   time.Time // When candidates were last raced (failover cooldown)
 	httpClient *http.Client
 	serverURL  string
 	token      string
+	candidates []string       // Candidate base URLs, serverURL included
+	machineID  string         // Expected machine identifier ("" = don't check)
+	verified   bool           // serverURL's identity checked since the last connectivity error
+	filter     *SessionFilter // Sessions to leave out (nil = all)
+	mu         sync.RWMutex
 }
 
 // NewClient creates a new Plex client with the given token and server URL
@@ -48,8 +59,21 @@ type LibraryResponse struct {
 	Size    int      `xml:"size,attr"`
 }
 
-// ValidateConnection validates the Plex server connection by querying server info and library count
+// ValidateConnection validates the Plex server connection by querying server info and library count.
+// If the current URL is unreachable and other connections are known, it fails
+// over to the fastest reachable one first.
 func (c *Client) ValidateConnection() (*ValidationResult, error) {
+	var result *ValidationResult
+	err := c.withFailover(func() error {
+		var err error
+		result, err = c.validateConnection()
+		return err
+	})
+	return result, err
+}
+
+// validateConnection performs the validation against the current URL.
+func (c *Client) validateConnection() (*ValidationResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -64,7 +88,8 @@ func (c *Client) ValidateConnection() (*ValidationResult, error) {
 
 	// Step 1: Get server identity using /identity endpoint
 	// Note: This endpoint doesn't require authentication
-	identityURL := fmt.Sprintf("%s/identity", c.serverURL)
+	serverURL := c.ServerURL()
+	identityURL := fmt.Sprintf("%s/identity", serverURL)
 	identityReq, err := http.NewRequestWithContext(ctx, "GET", identityURL, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create identity request")
@@ -96,9 +121,12 @@ func (c *Client) ValidateConnection() (*ValidationResult, error) {
 	if err := xml.Unmarshal(identityBody, &identity); err != nil {
 		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid server response format")
 	}
+	if err := c.checkMachineIdentifier(identity.MachineIdentifier); err != nil {
+		return nil, err
+	}
 
 	// Step 2: Get library count
-	libraryURL := fmt.Sprintf("%s/library/sections/?X-Plex-Token=%s", c.serverURL, url.QueryEscape(c.token))
+	libraryURL := fmt.Sprintf("%s/library/sections/?X-Plex-Token=%s", serverURL, url.QueryEscape(c.token))
 
 	libraryReq, err := http.NewRequestWithContext(ctx, "GET", libraryURL, nil)
 	if err != nil {
@@ -156,10 +184,21 @@ func (c *Client) ValidateConnection() (*ValidationResult, error) {
 
 // GetUsers retrieves the list of Plex users/accounts that can be monitored
 func (c *Client) GetUsers() ([]PlexUser, error) {
+	var users []PlexUser
+	err := c.withFailover(func() error {
+		var err error
+		users, err = c.getUsers()
+		return err
+	})
+	return users, err
+}
+
+// getUsers performs the accounts request against the current URL.
+func (c *Client) getUsers() ([]PlexUser, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	accountsURL := fmt.Sprintf("%s/accounts?X-Plex-Token=%s", c.serverURL, url.QueryEscape(c.token))
+	accountsURL := fmt.Sprintf("%s/accounts?X-Plex-Token=%s", c.ServerURL(), url.QueryEscape(c.token))
 	req, err := http.NewRequestWithContext(ctx, "GET", accountsURL, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create accounts request")
@@ -219,7 +258,7 @@ func (c *Client) GetSessions(userID string) ([]Session, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	defer cancel()
 
-	sessionsURL := fmt.Sprintf("%s/status/sessions?X-Plex-Token=%s", c.serverURL, url.QueryEscape(c.token))
+	sessionsURL := fmt.Sprintf("%s/status/sessions?X-Plex-Token=%s", c.ServerURL(), url.QueryEscape(c.token))
 	req, err := http.NewRequestWithContext(ctx, "GET", sessionsURL, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create sessions request")
@@ -287,7 +326,7 @@ func (c *Client) GetMusicSessions(userID string) ([]MusicSession, error) {
 	if err != nil {
 		return nil, err
 	}
-	return filterMusicSessions(sessionsResp, userID, c.buildArtworkURL), nil
+	return filterMusicSessions(sessionsResp, userID, c.sessionFilter(), c.buildArtworkURL), nil
 }
 
 // GetMediaSessions retrieves active media sessions of the specified types for the specified user.
@@ -298,17 +337,29 @@ func (c *Client) GetMediaSessions(userID string, mediaTypes []string) ([]MediaSe
 	if err != nil {
 		return nil, err
 	}
-	return filterMediaSessions(sessionsResp, userID, mediaTypes, c.buildArtworkURL), nil
+	sessions := filterMediaSessions(sessionsResp, userID, mediaTypes, c.sessionFilter(), c.buildArtworkURL)
+	serverID := c.MachineIdentifier()
+	for i := range sessions {
+		sessions[i].ServerID = serverID
+	}
+	return sessions, nil
 }
 
 // fetchSessions performs the HTTP GET to /status/sessions and parses the XML.
 // Centralized so all three GetXxxSessions methods share one transport path.
+// A connectivity failure triggers a failover race before the error reaches
+// the poller (and from there the retry manager).
 func (c *Client) fetchSessions() (*SessionsResponse, error) {
-	// Use 500ms timeout for polling performance (NFR5)
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	defer cancel()
-
-	body, err := c.transport().get(ctx, "/status/sessions")
+	var body []byte
+	err := c.withFailover(func() error {
+		// Use 500ms timeout for polling performance (NFR5)
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		var err error
+		body, err = c.transport().get(ctx, "/status/sessions")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -320,7 +371,7 @@ func (c *Client) fetchSessions() (*SessionsResponse, error) {
 func (c *Client) transport() *transport {
 	return &transport{
 		httpClient: c.httpClient,
-		serverURL:  c.serverURL,
+		serverURL:  c.ServerURL(),
 		token:      c.token,
 	}
 }
@@ -333,7 +384,7 @@ func (c *Client) buildArtworkURL(thumbPath string) string {
 		return ""
 	}
 	// Build absolute URL: {serverURL}{thumb}?X-Plex-Token={token}
-	return fmt.Sprintf("%s%s?X-Plex-Token=%s", c.serverURL, thumbPath, url.QueryEscape(c.token))
+	return fmt.Sprintf("%s%s?X-Plex-Token=%s", c.ServerURL(), thumbPath, url.QueryEscape(c.token))
 }
 
 // mapHTTPError maps HTTP client errors to appropriate error codes
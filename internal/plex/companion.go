@@ -0,0 +1,721 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"plexcord/internal/errors"
+	"plexcord/internal/version"
+)
+
+const (
+	// companionClientID identifies PlexCord as a Companion controller to the
+	// players it subscribes to. Each source suffixes it with its server's
+	// machine identifier (see clientIdentifier).
+	companionClientID = "plexcord-companion"
+
+	// companionResubscribeInterval keeps subscriptions alive. Players drop a
+	// subscriber they haven't heard from in about 90 seconds.
+	companionResubscribeInterval = 30 * time.Second
+
+	// companionTimelinePath is where players POST their timeline updates.
+	companionTimelinePath = "/:/timeline"
+
+	// companionResolveTimeout bounds the lookup of a player's addresses.
+	companionResolveTimeout = 2 * time.Second
+
+	// companionTimelineTTL expires the timeline of a player that stopped
+	// posting. Subscribed players post every few seconds, paused or not, so
+	// silence this long means the player quit or dropped off the network.
+	companionTimelineTTL = 60 * time.Second
+)
+
+// CompanionPlayer is a Plex player that can be subscribed to over the
+// Companion protocol.
+type CompanionPlayer struct {
+	ID   string `json:"id"`   // Client identifier
+	Name string `json:"name"` // Display name (used as the session's player name)
+	URI  string `json:"uri"`  // Base URL of the player's Companion endpoint
+}
+
+// timelineContainer is the XML body a player POSTs (or returns from
+// /player/timeline/poll). It holds one Timeline per media kind.
+type timelineContainer struct {
+	XMLName   xml.Name        `xml:"MediaContainer"`
+	Timelines []timelineEntry `xml:"Timeline"`
+}
+
+// timelineEntry is a player's state for one media kind (music, video or
+// photo). Only the IDs of the item are included; titles come from the
+// server's library metadata.
+type timelineEntry struct {
+	Type              string `xml:"type,attr"`  // "music", "video", "photo"
+	State             string `xml:"state,attr"` // "playing", "paused", "buffering", "stopped"
+	RatingKey         string `xml:"ratingKey,attr"`
+	Key               string `xml:"key,attr"`
+	MachineIdentifier string `xml:"machineIdentifier,attr"` // Server the item comes from
+	Time              int64  `xml:"time,attr"`              // Position in milliseconds
+	Duration          int64  `xml:"duration,attr"`          // Duration in milliseconds
+	PlayQueueID       string `xml:"playQueueID,attr"`
+	PlayQueueItemID   string `xml:"playQueueItemID,attr"`
+
+	received time.Time // When the player posted it, for seek detection
+}
+
+// parseTimeline returns the active timeline in a timeline body, or nil when
+// every media kind is stopped.
+func parseTimeline(body []byte) (*timelineEntry, error) {
+	var container timelineContainer
+	if err := xml.Unmarshal(body, &container); err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid timeline format")
+	}
+	for i := range container.Timelines {
+		tl := &container.Timelines[i]
+		if tl.State != "" && tl.State != "stopped" && tl.RatingKey != "" {
+			return tl, nil
+		}
+	}
+	return nil, nil
+}
+
+// CompanionSource reports what the user's players are playing from a server
+// by subscribing to their Companion timelines, instead of reading the
+// server's /status/sessions. That endpoint needs the server owner's token, so
+// this is the only way to follow playback on servers shared with the user.
+//
+// Each player pushes its timeline to a small local HTTP listener; the source
+// resolves the item's titles from the server's library metadata and emits a
+// MediaSession whenever the selected session changes (nil when nothing plays).
+type CompanionSource struct {
+	client      *Client // Server holding the media (metadata and artwork)
+	httpClient  *http.Client
+	playerToken string // Token the players accept (the account token)
+	listenAddr  string // Listener address; empty listens on the local address each player is reached through
+	players     []CompanionPlayer
+	enricher    *MetadataEnricher  // Optional metadata enrichment
+	classifier  *LibraryClassifier // Optional audiobook/podcast classification
+	queues      *PlayQueueResolver // Optional up-next resolution
+	mediaTypes  []string           // Media types to report (empty = all)
+	timelineTTL time.Duration      // How long a timeline stays without a new post
+
+	commandID atomic.Int64
+	cancel    context.CancelFunc
+
+	mu          sync.Mutex
+	timelines   map[string]timelineEntry // Active timeline per player ID
+	playerAddrs map[string][]net.IP      // Addresses of each player, the only senders accepted for its ID
+	metadata    map[string]*SessionEntry // Item metadata per rating key
+	subscribed  map[string]bool          // Players whose last subscribe succeeded
+	policy      SelectionPolicy          // Which player's session is emitted
+	clock       sessionClock             // When each player started its item, for PreferRecent
+	sessions    []MediaSession           // Every active player's session at the last timeline
+	last        *MediaSession            // Last emitted session
+	out         chan *MediaSession
+	emitMu      sync.Mutex // Serializes select-and-emit across timeline posts
+	running     bool
+	inError     bool // No player accepted the last subscription round
+
+	// Error callbacks, as on Poller
+	onError     func(err error)
+	onRecovered func()
+}
+
+// NewCompanionSource creates a source following the given players' playback
+// of items from client's server. playerToken authenticates the subscription
+// requests to the players.
+func NewCompanionSource(client *Client, players []CompanionPlayer, playerToken string) *CompanionSource {
+	return &CompanionSource{
+		client:      client,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		playerToken: playerToken,
+		players:     players,
+		timelineTTL: companionTimelineTTL,
+		timelines:   make(map[string]timelineEntry),
+		metadata:    make(map[string]*SessionEntry),
+		subscribed:  make(map[string]bool),
+	}
+}
+
+// SetErrorCallbacks sets callbacks for error handling, as on Poller.
+// onError is called when no player accepts the subscription anymore, so the
+// source can't see any playback; onRecovered when one accepts it again.
+func (s *CompanionSource) SetErrorCallbacks(onError func(err error), onRecovered func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onError = onError
+	s.onRecovered = onRecovered
+}
+
+// SetMetadataEnricher attaches full library metadata to emitted sessions.
+// Must be called before Start.
+func (s *CompanionSource) SetMetadataEnricher(enricher *MetadataEnricher) {
+	s.enricher = enricher
+}
+
+// SetLibraryClassifier classifies sessions from audiobook and podcast
+// libraries as such. Must be called before Start.
+func (s *CompanionSource) SetLibraryClassifier(classifier *LibraryClassifier) {
+	s.classifier = classifier
+}
+
+// SetPlayQueueResolver attaches the upcoming items of each player's play
+// queue. Must be called before Start.
+func (s *CompanionSource) SetPlayQueueResolver(resolver *PlayQueueResolver) {
+	s.queues = resolver
+}
+
+// SetSelectionPolicy sets how the emitted session is chosen when several
+// players are active. Takes effect on the next timeline.
+func (s *CompanionSource) SetSelectionPolicy(policy SelectionPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+}
+
+// Sessions returns the session of every active player as of the last
+// timeline, the emitted one included.
+func (s *CompanionSource) Sessions() []MediaSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]MediaSession(nil), s.sessions...)
+}
+
+// SetMediaTypes limits the reported sessions to the given media types
+// ("music", "movie", "tv", "photo"). Empty reports every type.
+// Must be called before Start.
+func (s *CompanionSource) SetMediaTypes(types []string) {
+	s.mediaTypes = types
+}
+
+// Start opens the timeline listener, subscribes to every player and returns
+// the session channel. The channel is closed when ctx is cancelled or Stop
+// is called; players are unsubscribed on the way out.
+func (s *CompanionSource) Start(ctx context.Context) (<-chan *MediaSession, error) {
+	addrs := s.resolvePlayers(ctx)
+	ports, listeners, err := s.listen(addrs)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(companionTimelinePath, s.handleTimeline)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.out = make(chan *MediaSession, 10)
+	s.cancel = cancel
+	s.playerAddrs = addrs
+	s.running = true
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				log.Printf("Companion timeline listener stopped: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(companionResubscribeInterval)
+		defer ticker.Stop()
+		expiry := time.NewTicker(s.timelineTTL / 4)
+		defer expiry.Stop()
+
+		s.subscribeAll(ports)
+		for {
+			select {
+			case <-ctx.Done():
+				s.unsubscribeAll()
+				if err := server.Close(); err != nil {
+					log.Printf("Warning: Failed to close timeline listener: %v", err)
+				}
+				s.emitMu.Lock()
+				s.mu.Lock()
+				s.running = false
+				close(s.out)
+				s.mu.Unlock()
+				s.emitMu.Unlock()
+				return
+			case <-ticker.C:
+				s.subscribeAll(ports)
+			case now := <-expiry.C:
+				if s.expireTimelines(now) {
+					s.emitCurrent()
+				}
+			}
+		}
+	}()
+
+	log.Printf("Companion source started: %d player(s), %d timeline listener(s)", len(s.players), len(listeners))
+	return s.out, nil
+}
+
+// Stop cancels the source. Safe to call multiple times.
+func (s *CompanionSource) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// IsRunning reports whether the source is started and not yet stopped.
+func (s *CompanionSource) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// IsInErrorState reports whether no player could be subscribed to, in which
+// case the source can't see any playback.
+func (s *CompanionSource) IsInErrorState() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.running || len(s.subscribed) == 0 {
+		return false // Not started, or first subscription still in flight
+	}
+	for _, ok := range s.subscribed {
+		if ok {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePlayers looks up the addresses of every player, from the host of
+// its URI. A player that can't be resolved gets none, so its timelines are
+// refused.
+func (s *CompanionSource) resolvePlayers(ctx context.Context) map[string][]net.IP {
+	addrs := make(map[string][]net.IP, len(s.players))
+	for _, p := range s.players {
+		u, err := url.Parse(p.URI)
+		if err != nil || u.Hostname() == "" {
+			log.Printf("Warning: Invalid Companion URI for %s: %q", p.Name, p.URI)
+			continue
+		}
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			addrs[p.ID] = []net.IP{ip}
+			continue
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, companionResolveTimeout)
+		ips, err := net.DefaultResolver.LookupIP(lookupCtx, "ip", u.Hostname())
+		cancel()
+		if err != nil {
+			log.Printf("Warning: Failed to resolve Companion player %s: %v", p.Name, err)
+			continue
+		}
+		addrs[p.ID] = ips
+	}
+	return addrs
+}
+
+// listen opens the timeline listeners and returns the port to announce to
+// each player. Rather than every interface, it listens only on the local
+// address each player is reached through, which is the one the player posts
+// back to; players sharing that address share a listener.
+func (s *CompanionSource) listen(addrs map[string][]net.IP) (map[string]int, []net.Listener, error) {
+	ports := make(map[string]int, len(s.players))
+	byAddr := make(map[string]net.Listener)
+	var listeners []net.Listener
+	for _, p := range s.players {
+		addr := s.listenAddr
+		if addr == "" {
+			addr = net.JoinHostPort(localAddrFor(addrs[p.ID]), "0")
+		}
+		listener, ok := byAddr[addr]
+		if !ok {
+			var err error
+			listener, err = net.Listen("tcp", addr)
+			if err != nil {
+				for _, l := range listeners {
+					_ = l.Close()
+				}
+				return nil, nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to open timeline listener")
+			}
+			byAddr[addr] = listener
+			listeners = append(listeners, listener)
+		}
+		ports[p.ID] = listener.Addr().(*net.TCPAddr).Port
+	}
+	return ports, listeners, nil
+}
+
+// localAddrFor returns the local address the system routes to the first of
+// ips through, or the loopback address when there is no route. Connecting a
+// UDP socket picks the route without sending anything.
+func localAddrFor(ips []net.IP) string {
+	if len(ips) == 0 {
+		return "127.0.0.1"
+	}
+	conn, err := net.Dial("udp", net.JoinHostPort(ips[0].String(), "9"))
+	if err != nil {
+		return "127.0.0.1"
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+}
+
+// fromPlayer reports whether a timeline claiming to come from playerID was
+// sent from one of that player's addresses. Unknown player IDs are refused.
+func (s *CompanionSource) fromPlayer(playerID, remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	sender := net.ParseIP(host)
+	if sender == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ip := range s.playerAddrs[playerID] {
+		if ip.Equal(sender) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribeAll (re)subscribes to every player's timeline, announcing the
+// port of the listener it posts to, and reports the source failing when no
+// player accepted it.
+func (s *CompanionSource) subscribeAll(ports map[string]int) {
+	var lastErr error
+	accepted := false
+	for _, p := range s.players {
+		err := s.playerCommand(p, "/player/timeline/subscribe", url.Values{
+			"protocol": {"http"},
+			"port":     {strconv.Itoa(ports[p.ID])},
+		})
+		if err != nil {
+			log.Printf("Companion subscribe to %s failed: %v", p.Name, err)
+			lastErr = err
+		} else {
+			accepted = true
+		}
+		s.mu.Lock()
+		s.subscribed[p.ID] = err == nil
+		s.mu.Unlock()
+	}
+	if len(s.players) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	wasInError := s.inError
+	s.inError = !accepted
+	onError, onRecovered := s.onError, s.onRecovered
+	s.mu.Unlock()
+
+	switch {
+	case !accepted && !wasInError && onError != nil:
+		onError(lastErr)
+	case accepted && wasInError && onRecovered != nil:
+		onRecovered()
+	}
+}
+
+// expireTimelines drops the timelines no post refreshed for timelineTTL, and
+// reports whether any was dropped.
+func (s *CompanionSource) expireTimelines(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expired := false
+	for id, tl := range s.timelines {
+		if now.Sub(tl.received) >= s.timelineTTL {
+			log.Printf("Companion timeline of %s expired: no update for %v", id, now.Sub(tl.received).Round(time.Second))
+			delete(s.timelines, id)
+			expired = true
+		}
+	}
+	return expired
+}
+
+// unsubscribeAll tells every player to stop sending timelines.
+func (s *CompanionSource) unsubscribeAll() {
+	for _, p := range s.players {
+		if err := s.playerCommand(p, "/player/timeline/unsubscribe", url.Values{}); err != nil {
+			log.Printf("Companion unsubscribe from %s failed: %v", p.Name, err)
+		}
+	}
+}
+
+// Command sends a playback command (see CommandPlay) straight to a followed
+// player. Shared servers don't proxy commands for the account, so this is
+// how their sessions are controlled.
+func (s *CompanionSource) Command(playerID, mediaType, command string) error {
+	if !validCommand(command) {
+		return errors.New(errors.INVALID_INPUT, "unknown playback command: "+command)
+	}
+	for _, p := range s.players {
+		if p.ID == playerID {
+			return s.playerCommand(p, "/player/playback/"+command, url.Values{"type": {commandMediaType(mediaType)}})
+		}
+	}
+	return errors.New(errors.PLAYBACK_UNAVAILABLE, "player is not followed: "+playerID)
+}
+
+// playerCommand sends a Companion command to a player.
+func (s *CompanionSource) playerCommand(p CompanionPlayer, path string, query url.Values) error {
+	query.Set("commandID", strconv.FormatInt(s.commandID.Add(1), 10))
+
+	req, err := http.NewRequest("GET", p.URI+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create player request")
+	}
+	req.Header.Set("X-Plex-Client-Identifier", s.clientIdentifier())
+	req.Header.Set("X-Plex-Target-Client-Identifier", p.ID)
+	req.Header.Set("X-Plex-Device-Name", productName)
+	req.Header.Set("X-Plex-Product", productName)
+	req.Header.Set("X-Plex-Version", version.Version)
+	req.Header.Set("X-Plex-Token", s.playerToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errors.PLEX_UNREACHABLE, "player unreachable")
+	}
+	if err := resp.Body.Close(); err != nil {
+		log.Printf("Warning: Failed to close response body: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return mapHTTPStatusCode(resp.StatusCode)
+	}
+	return nil
+}
+
+// clientIdentifier returns the identifier the source subscribes with.
+// Players keep one subscription per controller identifier, so sources
+// following different servers must not share one: the server's machine
+// identifier tells them apart.
+func (s *CompanionSource) clientIdentifier() string {
+	if id := s.client.MachineIdentifier(); id != "" {
+		return companionClientID + "-" + id
+	}
+	return companionClientID
+}
+
+// handleTimeline receives a player's timeline POST and updates the session.
+func (s *CompanionSource) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	playerID := r.Header.Get("X-Plex-Client-Identifier")
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil || playerID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// Only followed players may update their own session
+	if !s.fromPlayer(playerID, r.RemoteAddr) {
+		log.Printf("Ignoring timeline for player %q from %s: not a followed player's address", playerID, r.RemoteAddr)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+
+	tl, err := parseTimeline(body)
+	if err != nil {
+		log.Printf("Ignoring malformed timeline from %s: %v", playerID, err)
+		return
+	}
+	// Items played from other servers belong to another source
+	if tl != nil && tl.MachineIdentifier != "" && !s.fromServer(tl.MachineIdentifier) {
+		tl = nil
+	}
+
+	s.mu.Lock()
+	if tl != nil {
+		tl.received = time.Now()
+		s.timelines[playerID] = *tl
+	} else {
+		delete(s.timelines, playerID)
+	}
+	s.mu.Unlock()
+
+	s.emitCurrent()
+}
+
+// fromServer reports whether machineID is the source's server. Without a
+// pinned identifier every timeline is accepted.
+func (s *CompanionSource) fromServer(machineID string) bool {
+	s.client.mu.RLock()
+	defer s.client.mu.RUnlock()
+	return s.client.machineID == "" || s.client.machineID == machineID
+}
+
+// emitCurrent selects the session to report and emits it if it changed.
+func (s *CompanionSource) emitCurrent() {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	session := s.currentSession()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := mediaSessionChanged(s.last, session) || mediaSessionSeeked(s.last, session, DefaultSeekTolerance)
+	if !s.running || !changed {
+		return
+	}
+	s.last = session
+	select {
+	case s.out <- session:
+	default:
+		log.Printf("Companion session channel full, skipping update")
+	}
+}
+
+// currentSession builds the session of every active player and returns the
+// one the selection policy picks. Playing players are listed first, so they
+// win the policy's remaining ties. Returns nil when no player is active.
+func (s *CompanionSource) currentSession() *MediaSession {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.timelines))
+	timelines := make(map[string]timelineEntry, len(s.timelines))
+	for id, tl := range s.timelines {
+		ids = append(ids, id)
+		timelines[id] = tl
+	}
+	policy := s.policy
+	s.mu.Unlock()
+
+	sort.Slice(ids, func(i, j int) bool {
+		iPlaying := timelines[ids[i]].State == "playing"
+		jPlaying := timelines[ids[j]].State == "playing"
+		if iPlaying != jPlaying {
+			return iPlaying
+		}
+		return ids[i] < ids[j]
+	})
+
+	sessions := make([]MediaSession, 0, len(ids))
+	for _, id := range ids {
+		if session := s.sessionFor(id, timelines[id]); session != nil {
+			sessions = append(sessions, *session)
+		}
+	}
+
+	s.clock.stampMedia(sessions, time.Now())
+	s.mu.Lock()
+	s.sessions = sessions
+	s.mu.Unlock()
+
+	if len(sessions) == 0 {
+		return nil
+	}
+	selected := sessions[policy.SelectMedia(sessions)]
+	return &selected
+}
+
+// sessionFor builds the session of one player's timeline. Returns nil when
+// its media type isn't reported.
+func (s *CompanionSource) sessionFor(playerID string, tl timelineEntry) *MediaSession {
+	entry := SessionEntry{Type: timelineItemType(tl.Type), RatingKey: tl.RatingKey, Key: tl.Key}
+	if meta := s.lookupMetadata(tl.RatingKey); meta != nil {
+		entry = *meta
+	}
+	entry.SessionKey = fmt.Sprintf("companion:%s", playerID)
+	entry.Player = SessionPlayer{State: tl.State, Title: s.playerName(playerID), MachineIdentifier: playerID}
+	if !s.client.sessionFilter().Allows(&entry) {
+		return nil
+	}
+	entry.ViewOffset = tl.Time
+	entry.PlayQueueID, entry.PlayQueueItemID = tl.PlayQueueID, tl.PlayQueueItemID
+	if tl.Duration > 0 {
+		entry.Duration = tl.Duration
+	}
+
+	thumbURL := ""
+	if entry.Thumb != "" {
+		thumbURL = s.client.buildArtworkURL(entry.Thumb)
+	}
+	session := NewMediaSessionFromEntry(entry, thumbURL)
+	session.ObservedAt = tl.received
+	session.ServerID = tl.MachineIdentifier
+	if session.ServerID == "" {
+		session.ServerID = s.client.MachineIdentifier()
+	}
+	session.ApplyFallbacks()
+	if s.classifier != nil {
+		s.classifier.Classify(&session)
+	}
+	if !s.wantsMediaType(session.MediaType) {
+		return nil
+	}
+	if s.enricher != nil {
+		s.enricher.EnrichMedia(&session)
+	}
+	if s.queues != nil {
+		s.queues.ResolveMedia(&session)
+	}
+	return &session
+}
+
+// wantsMediaType reports whether sessions of mediaType are reported.
+func (s *CompanionSource) wantsMediaType(mediaType string) bool {
+	if len(s.mediaTypes) == 0 {
+		return true
+	}
+	for _, t := range s.mediaTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupMetadata returns the cached metadata of an item, fetching it from the
+// server on first use. Returns nil if the server can't provide it.
+func (s *CompanionSource) lookupMetadata(ratingKey string) *SessionEntry {
+	s.mu.Lock()
+	meta, ok := s.metadata[ratingKey]
+	s.mu.Unlock()
+	if ok {
+		return meta
+	}
+
+	meta, err := s.client.GetMetadata(ratingKey)
+	if err != nil {
+		log.Printf("Companion metadata lookup for %s failed: %v", ratingKey, err)
+		return nil
+	}
+	s.mu.Lock()
+	s.metadata[ratingKey] = meta
+	s.mu.Unlock()
+	return meta
+}
+
+// playerName returns the display name of a subscribed player.
+func (s *CompanionSource) playerName(playerID string) string {
+	for _, p := range s.players {
+		if p.ID == playerID {
+			return p.Name
+		}
+	}
+	return playerID
+}
+
+// timelineItemType maps a timeline's media kind to the Plex item type used
+// when no metadata is available.
+func timelineItemType(kind string) string {
+	switch kind {
+	case "music":
+		return "track"
+	case "photo":
+		return "photo"
+	default:
+		return "movie"
+	}
+}
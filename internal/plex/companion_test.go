@@ -0,0 +1,322 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const timelineXML = `<MediaContainer commandID="1">
+  <Timeline type="music" state="%s" ratingKey="123" key="/library/metadata/123" machineIdentifier="%s" time="5000" duration="200000"/>
+  <Timeline type="video" state="stopped"/>
+  <Timeline type="photo" state="stopped"/>
+</MediaContainer>`
+
+// fakePlayer is a Companion player that records the subscriber's port and
+// can push timelines to it.
+type fakePlayer struct {
+	server   *httptest.Server
+	ports    chan string
+	unsubs   chan struct{}
+	commands chan string // Playback commands, as "path type"
+}
+
+func newFakePlayer(t *testing.T) *fakePlayer {
+	t.Helper()
+	p := &fakePlayer{ports: make(chan string, 4), unsubs: make(chan struct{}, 4), commands: make(chan string, 4)}
+	p.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != "account-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("X-Plex-Client-Identifier") == "" || r.URL.Query().Get("commandID") == "" {
+			t.Errorf("subscription request missing client identifier or commandID: %s", r.URL)
+		}
+		switch r.URL.Path {
+		case "/player/timeline/subscribe":
+			if r.URL.Query().Get("protocol") != "http" {
+				t.Errorf("expected http protocol, got %q", r.URL.Query().Get("protocol"))
+			}
+			p.ports <- r.URL.Query().Get("port")
+		case "/player/timeline/unsubscribe":
+			p.unsubs <- struct{}{}
+		default:
+			p.commands <- r.URL.Path + " " + r.URL.Query().Get("type")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// push POSTs a timeline to the subscriber listening on port.
+func (p *fakePlayer) push(t *testing.T, port, state, machineID string) {
+	t.Helper()
+	pushAs(t, port, "player1", state, machineID)
+}
+
+// pushAs POSTs a timeline claiming to come from playerID and returns the
+// response status.
+func pushAs(t *testing.T, port, playerID, state, machineID string) int {
+	t.Helper()
+	req, _ := http.NewRequest("POST", "http://127.0.0.1:"+port+companionTimelinePath,
+		strings.NewReader(fmt.Sprintf(timelineXML, state, machineID)))
+	req.Header.Set("X-Plex-Client-Identifier", playerID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("timeline POST failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// metadataServer is server1, serving the library metadata of item 123.
+func metadataServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/identity" {
+			w.Write([]byte(`<MediaContainer machineIdentifier="server1" version="1.40.0"/>`))
+			return
+		}
+		if r.URL.Path != "/library/metadata/123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<MediaContainer size="1">
+  <Track ratingKey="123" type="track" title="Song" grandparentTitle="Artist" parentTitle="Album" duration="200000" thumb="/thumb/1"/>
+</MediaContainer>`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func receiveSession(t *testing.T, ch <-chan *MediaSession) *MediaSession {
+	t.Helper()
+	select {
+	case s, ok := <-ch:
+		if !ok {
+			t.Fatal("session channel closed unexpectedly")
+		}
+		return s
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for session")
+		return nil
+	}
+}
+
+func TestCompanionSourceFollowsTimelines(t *testing.T) {
+	player := newFakePlayer(t)
+	server := metadataServer(t)
+
+	client := NewClient("shared-token", server.URL)
+	client.SetMachineIdentifier("server1")
+	source := NewCompanionSource(client, []CompanionPlayer{{ID: "player1", Name: "Living Room", URI: player.server.URL}}, "account-token")
+	source.listenAddr = "127.0.0.1:0"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sessions, err := source.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	var port string
+	select {
+	case port = <-player.ports:
+	case <-time.After(2 * time.Second):
+		t.Fatal("player was never subscribed to")
+	}
+
+	player.push(t, port, "playing", "server1")
+	s := receiveSession(t, sessions)
+	if s == nil {
+		t.Fatal("expected a session, got nil")
+	}
+	if s.Title != "Song" || s.Artist != "Artist" || s.Album != "Album" {
+		t.Errorf("metadata not applied: %+v", s)
+	}
+	if s.State != "playing" || s.PlayerName != "Living Room" || s.PlayerID != "player1" || s.ViewOffset != 5000 {
+		t.Errorf("timeline not applied: %+v", s)
+	}
+	if !strings.Contains(s.ThumbURL, "/thumb/1") {
+		t.Errorf("expected artwork URL from the server, got %q", s.ThumbURL)
+	}
+
+	// Items from another server are not this source's playback
+	player.push(t, port, "playing", "other-server")
+	if s := receiveSession(t, sessions); s != nil {
+		t.Errorf("expected nil session for another server's item, got %+v", s)
+	}
+
+	player.push(t, port, "paused", "server1")
+	if s := receiveSession(t, sessions); s == nil || s.State != "paused" {
+		t.Errorf("expected paused session, got %+v", s)
+	}
+
+	player.push(t, port, "stopped", "server1")
+	if s := receiveSession(t, sessions); s != nil {
+		t.Errorf("expected nil session after stop, got %+v", s)
+	}
+
+	source.Stop()
+	select {
+	case <-player.unsubs:
+	case <-time.After(2 * time.Second):
+		t.Error("player was not unsubscribed on stop")
+	}
+	select {
+	case _, ok := <-sessions:
+		if ok {
+			t.Error("expected session channel to be closed after Stop")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("session channel not closed after Stop")
+	}
+}
+
+func TestCompanionSourceCommand(t *testing.T) {
+	player := newFakePlayer(t)
+	source := NewCompanionSource(NewClient("shared-token", "http://127.0.0.1:1"),
+		[]CompanionPlayer{{ID: "player1", Name: "TV", URI: player.server.URL}}, "account-token")
+
+	if err := source.Command("player1", MediaTypeMovie, CommandPause); err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	select {
+	case got := <-player.commands:
+		if got != "/player/playback/pause video" {
+			t.Errorf("player received %q, want a video pause", got)
+		}
+	default:
+		t.Error("the command did not reach the player")
+	}
+
+	if err := source.Command("player2", MediaTypeMovie, CommandPause); err == nil {
+		t.Error("expected an error for a player that isn't followed")
+	}
+	if err := source.Command("player1", MediaTypeMovie, "seekTo"); err == nil {
+		t.Error("expected an error for an unknown command")
+	}
+}
+
+func TestCompanionSourceErrorState(t *testing.T) {
+	player := newFakePlayer(t)
+	client := NewClient("shared-token", "http://127.0.0.1:1")
+	source := NewCompanionSource(client, []CompanionPlayer{{ID: "player1", Name: "TV", URI: player.server.URL}}, "wrong-token")
+	source.listenAddr = "127.0.0.1:0"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := source.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !source.IsInErrorState() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !source.IsInErrorState() {
+		t.Error("expected error state when no player accepts the subscription")
+	}
+}
+
+func TestCompanionSourceRejectsForeignTimelines(t *testing.T) {
+	player := newFakePlayer(t)
+	server := metadataServer(t)
+
+	client := NewClient("shared-token", server.URL)
+	client.SetMachineIdentifier("server1")
+	source := NewCompanionSource(client, []CompanionPlayer{
+		{ID: "player1", Name: "Living Room", URI: player.server.URL},
+		// Followed, but reached at another address than the test's
+		{ID: "player2", Name: "Bedroom", URI: "http://192.0.2.1:32500"},
+	}, "account-token")
+	source.httpClient.Timeout = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sessions, err := source.Start(ctx)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	var port string
+	select {
+	case port = <-player.ports:
+	case <-time.After(2 * time.Second):
+		t.Fatal("player was never subscribed to")
+	}
+
+	if status := pushAs(t, port, "player9", "playing", "server1"); status != http.StatusForbidden {
+		t.Errorf("unknown player: status = %d, want %d", status, http.StatusForbidden)
+	}
+	if status := pushAs(t, port, "player2", "playing", "server1"); status != http.StatusForbidden {
+		t.Errorf("spoofed player: status = %d, want %d", status, http.StatusForbidden)
+	}
+
+	// Neither was taken as a session: the first one emitted is player1's
+	player.push(t, port, "paused", "server1")
+	if s := receiveSession(t, sessions); s == nil || s.PlayerID != "player1" || s.State != "paused" {
+		t.Errorf("expected player1's paused session, got %+v", s)
+	}
+}
+
+func TestCompanionSourceErrorCallbacks(t *testing.T) {
+	player := newFakePlayer(t)
+	source := NewCompanionSource(NewClient("shared-token", "http://127.0.0.1:1"),
+		[]CompanionPlayer{{ID: "player1", Name: "TV", URI: player.server.URL}}, "wrong-token")
+
+	var errs, recoveries int
+	source.SetErrorCallbacks(func(error) { errs++ }, func() { recoveries++ })
+	ports := map[string]int{"player1": 1}
+
+	source.subscribeAll(ports)
+	source.subscribeAll(ports)
+	if errs != 1 || recoveries != 0 {
+		t.Errorf("after failed subscriptions: %d errors, %d recoveries; want 1, 0", errs, recoveries)
+	}
+
+	source.playerToken = "account-token"
+	source.subscribeAll(ports)
+	source.subscribeAll(ports)
+	if errs != 1 || recoveries != 1 {
+		t.Errorf("after the player accepted: %d errors, %d recoveries; want 1, 1", errs, recoveries)
+	}
+}
+
+func TestCompanionSourceClientIdentifierPerServer(t *testing.T) {
+	identifiers := make(map[string]bool)
+	for _, machineID := range []string{"server1", "server2"} {
+		client := NewClient("shared-token", "http://127.0.0.1:1")
+		client.SetMachineIdentifier(machineID)
+		identifiers[NewCompanionSource(client, nil, "account-token").clientIdentifier()] = true
+	}
+	if len(identifiers) != 2 {
+		t.Errorf("sources of different servers share a client identifier: %v", identifiers)
+	}
+}
+
+func TestCompanionSourceExpiresSilentPlayers(t *testing.T) {
+	source := NewCompanionSource(NewClient("shared-token", "http://127.0.0.1:1"), nil, "account-token")
+	now := time.Now()
+	source.timelines["player1"] = timelineEntry{State: "playing", RatingKey: "123", received: now.Add(-companionTimelineTTL)}
+	source.timelines["player2"] = timelineEntry{State: "paused", RatingKey: "456", received: now.Add(-time.Second)}
+
+	if !source.expireTimelines(now) {
+		t.Fatal("expected the silent player's timeline to expire")
+	}
+	if _, ok := source.timelines["player1"]; ok {
+		t.Error("the silent player's timeline was kept")
+	}
+	if _, ok := source.timelines["player2"]; !ok {
+		t.Error("the active player's timeline was dropped")
+	}
+	if source.expireTimelines(now) {
+		t.Error("nothing left to expire, but expireTimelines reported a change")
+	}
+}
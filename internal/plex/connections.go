@@ -0,0 +1,236 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+const (
+	// raceTimeout bounds a connection race. Candidates that haven't answered
+	// /identity by then are considered unreachable.
+	raceTimeout = 3 * time.Second
+
+	// raceCooldown stops a server that is down on every URI from being
+	// re-raced on every single poll; the retry manager takes over instead.
+	raceCooldown = 10 * time.Second
+)
+
+// SetConnections sets the candidate base URLs for this server (e.g. its
+// local, remote, relay and plex.direct URIs). The client keeps using its
+// current URL until Race is called or a request fails, at which point the
+// fastest reachable candidate takes over. The current URL is always kept as
+// a candidate.
+func (c *Client) SetConnections(uris []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	candidates := []string{c.serverURL}
+	for _, uri := range uris {
+		if uri != "" && uri != c.serverURL {
+			candidates = append(candidates, uri)
+		}
+	}
+	c.candidates = candidates
+}
+
+// SetMachineIdentifier pins the client to the server with the given machine
+// identifier. Validation and connection races then reject any URL answered
+// by a different server (PLEX_SERVER_MISMATCH), so a server that took over
+// an old IP is never polled by mistake. An empty ID disables the check.
+func (c *Client) SetMachineIdentifier(machineID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.machineID = machineID
+}
+
+// MachineIdentifier returns the machine identifier the client is pinned to,
+// or "" when it isn't.
+func (c *Client) MachineIdentifier() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.machineID
+}
+
+// checkMachineIdentifier compares an /identity answer with the pinned ID.
+func (c *Client) checkMachineIdentifier(got string) error {
+	c.mu.RLock()
+	want := c.machineID
+	c.mu.RUnlock()
+	if want == "" || got == want {
+		return nil
+	}
+	return errors.New(errors.PLEX_SERVER_MISMATCH,
+		fmt.Sprintf("expected server %s but %s answered", want, got))
+}
+
+// ServerURL returns the base URL the client currently talks to.
+func (c *Client) ServerURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.serverURL
+}
+
+// Race probes every candidate connection's /identity endpoint in parallel and
+// switches the client to the first one that answers. Returns the selected URL,
+// or the error of the last candidate to fail when none is reachable.
+// With a single candidate this is a plain reachability check.
+func (c *Client) Race(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	candidates := c.candidates
+	if len(candidates) == 0 {
+		candidates = []string{c.serverURL}
+	}
+	c.lastRace = time.Now()
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, raceTimeout)
+	defer cancel()
+
+	type result struct {
+		err error
+		uri string
+	}
+	results := make(chan result, len(candidates))
+	for _, uri := range candidates {
+		go func(uri string) {
+			results <- result{uri: uri, err: c.probeIdentity(ctx, uri)}
+		}(uri)
+	}
+
+	var lastErr error
+	for range candidates {
+		r := <-results
+		if r.err != nil {
+			lastErr = r.err
+			continue
+		}
+		// First successful answer wins; cancel() stops the slower probes.
+		c.mu.Lock()
+		previous := c.serverURL
+		c.serverURL = r.uri
+		c.verified = true // probeIdentity already checked the machine identifier
+		c.mu.Unlock()
+		if previous != r.uri {
+			log.Printf("Plex connection switched from %s to %s", previous, r.uri)
+		}
+		return r.uri, nil
+	}
+	return "", lastErr
+}
+
+// probeIdentity checks that baseURL answers the unauthenticated /identity
+// endpoint, and that it is the expected server when one is pinned.
+func (c *Client) probeIdentity(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/identity", baseURL), nil)
+	if err != nil {
+		return errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create identity request")
+	}
+	req.Header.Set("User-Agent", "PlexCord/1.0")
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return mapHTTPError(err, ctx)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Warning: Failed to close response body: %v", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return mapHTTPStatusCode(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to read identity response")
+	}
+	var identity IdentityResponse
+	if err := xml.Unmarshal(body, &identity); err != nil {
+		return errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid server response format")
+	}
+	return c.checkMachineIdentifier(identity.MachineIdentifier)
+}
+
+// withFailover runs fn and, when it fails with a connectivity error and the
+// server has other candidate connections, re-races them and retries fn once
+// on the newly selected URL. Auth and protocol errors are returned as-is:
+// another URI for the same server won't fix them.
+//
+// When a machine identifier is pinned, the server's identity is checked
+// before fn on every (re)connect, i.e. the first request and the first one
+// after any connectivity error.
+func (c *Client) withFailover(fn func() error) error {
+	err := c.verifyIdentity()
+	if err == nil {
+		err = fn()
+	}
+	if err == nil || !isConnectivityError(err) {
+		return err
+	}
+	c.mu.Lock()
+	c.verified = false
+	c.mu.Unlock()
+	if !c.canRace() {
+		return err
+	}
+
+	previous := c.ServerURL()
+	selected, raceErr := c.Race(context.Background())
+	if raceErr != nil || selected == previous {
+		// Nothing better to fail over to; surface the original error so the
+		// retry manager sees the real cause.
+		return err
+	}
+	return fn()
+}
+
+// verifyIdentity checks the current URL still belongs to the pinned server,
+// unless it was already verified since the last connectivity error.
+func (c *Client) verifyIdentity() error {
+	c.mu.RLock()
+	skip := c.machineID == "" || c.verified
+	serverURL := c.serverURL
+	c.mu.RUnlock()
+	if skip {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), raceTimeout)
+	defer cancel()
+	if err := c.probeIdentity(ctx, serverURL); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.verified = true
+	c.mu.Unlock()
+	return nil
+}
+
+// canRace reports whether a failover race is worthwhile: there must be more
+// than one candidate and the last race must be outside the cooldown.
+func (c *Client) canRace() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.candidates) > 1 && time.Since(c.lastRace) >= raceCooldown
+}
+
+// isConnectivityError reports whether err means the URL itself is unusable
+// (as opposed to the server rejecting the request). A different server
+// answering at the URL counts: another URI may still reach the right one.
+func isConnectivityError(err error) bool {
+	switch errors.GetCode(err) {
+	case errors.PLEX_UNREACHABLE, errors.TIMEOUT, errors.PLEX_SERVER_MISMATCH:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,153 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+// deadURL returns the URL of a server that has already been shut down, so
+// connections to it are refused.
+func deadURL() string {
+	s := httptest.NewServer(http.NotFoundHandler())
+	u := s.URL
+	s.Close()
+	return u
+}
+
+func identityServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Path == "/identity" {
+			w.Write([]byte(`<MediaContainer machineIdentifier="abc" version="1.0"/>`))
+			return
+		}
+		w.Write([]byte(`<MediaContainer size="0"></MediaContainer>`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestClientRacePicksFastestReachable(t *testing.T) {
+	slow := identityServer(t, 300*time.Millisecond)
+	fast := identityServer(t, 0)
+
+	client := NewClient("token", deadURL())
+	client.SetConnections([]string{slow.URL, fast.URL})
+
+	selected, err := client.Race(context.Background())
+	if err != nil {
+		t.Fatalf("Race() error = %v", err)
+	}
+	if selected != fast.URL {
+		t.Errorf("Race() = %q, want fastest %q", selected, fast.URL)
+	}
+	if client.ServerURL() != fast.URL {
+		t.Errorf("ServerURL() = %q, want %q", client.ServerURL(), fast.URL)
+	}
+}
+
+func TestClientRaceAllUnreachable(t *testing.T) {
+	client := NewClient("token", deadURL())
+	client.SetConnections([]string{deadURL()})
+
+	if _, err := client.Race(context.Background()); err == nil {
+		t.Fatal("expected Race() to fail when no connection answers")
+	} else if code := errors.GetCode(err); code != errors.PLEX_UNREACHABLE {
+		t.Errorf("expected %s, got %v", errors.PLEX_UNREACHABLE, err)
+	}
+}
+
+func TestClientFailsOverOnUnreachable(t *testing.T) {
+	live := identityServer(t, 0)
+	dead := deadURL()
+
+	client := NewClient("token", dead)
+	client.SetConnections([]string{live.URL})
+
+	sessions, err := client.GetMusicSessions("")
+	if err != nil {
+		t.Fatalf("GetMusicSessions() error = %v, expected failover to succeed", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("expected no sessions, got %d", len(sessions))
+	}
+	if client.ServerURL() != live.URL {
+		t.Errorf("ServerURL() = %q after failover, want %q", client.ServerURL(), live.URL)
+	}
+}
+
+func TestClientDoesNotFailOverOnAuthError(t *testing.T) {
+	var liveHits int32
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&liveHits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer live.Close()
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	client := NewClient("token", unauthorized.URL)
+	client.SetConnections([]string{live.URL})
+
+	_, err := client.GetMusicSessions("")
+	if code := errors.GetCode(err); code != errors.PLEX_AUTH_FAILED {
+		t.Errorf("expected %s, got %v", errors.PLEX_AUTH_FAILED, err)
+	}
+	if atomic.LoadInt32(&liveHits) != 0 {
+		t.Error("auth errors must not trigger a failover race")
+	}
+	if client.ServerURL() != unauthorized.URL {
+		t.Errorf("ServerURL() changed to %q on auth error", client.ServerURL())
+	}
+}
+
+func TestClientRejectsDifferentServer(t *testing.T) {
+	impostor := identityServer(t, 0) // answers machineIdentifier="abc"
+
+	client := NewClient("token", impostor.URL)
+	client.SetMachineIdentifier("original")
+
+	if _, err := client.GetMusicSessions(""); errors.GetCode(err) != errors.PLEX_SERVER_MISMATCH {
+		t.Errorf("GetMusicSessions() error = %v, want %s", err, errors.PLEX_SERVER_MISMATCH)
+	}
+	if _, err := client.ValidateConnection(); errors.GetCode(err) != errors.PLEX_SERVER_MISMATCH {
+		t.Errorf("ValidateConnection() error = %v, want %s", err, errors.PLEX_SERVER_MISMATCH)
+	}
+
+	// The right server answering verifies the connection
+	client.SetMachineIdentifier("abc")
+	if _, err := client.GetMusicSessions(""); err != nil {
+		t.Errorf("GetMusicSessions() error = %v with matching identifier", err)
+	}
+}
+
+func TestClientRaceSkipsDifferentServer(t *testing.T) {
+	impostor := identityServer(t, 0)
+	original := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond) // slower than the impostor
+		w.Write([]byte(`<MediaContainer machineIdentifier="original"/>`))
+	}))
+	defer original.Close()
+
+	client := NewClient("token", impostor.URL)
+	client.SetMachineIdentifier("original")
+	client.SetConnections([]string{original.URL})
+
+	selected, err := client.Race(context.Background())
+	if err != nil {
+		t.Fatalf("Race() error = %v", err)
+	}
+	if selected != original.URL {
+		t.Errorf("Race() = %q, want the pinned server %q", selected, original.URL)
+	}
+}
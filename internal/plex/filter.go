@@ -0,0 +1,74 @@
+package plex
+
+import "strings"
+
+// SessionFilter includes or excludes sessions by where they play and what
+// library they come from. For each attribute, a non-empty include list
+// admits only sessions matching one of its values, and the exclude list then
+// drops sessions matching any of its values. Player titles and products
+// match case-insensitively; machine identifiers and library section IDs
+// exactly. Library section IDs are those of the client's server, so each
+// server's client gets a filter with its own library rules. Items without a
+// library section (Live TV, provider podcasts) never match a library include
+// list.
+type SessionFilter struct {
+	IncludePlayers   []string // Player titles, e.g. "Plexamp"
+	ExcludePlayers   []string
+	IncludeProducts  []string // Player products, e.g. "Plex for Android (TV)"
+	ExcludeProducts  []string
+	IncludePlayerIDs []string // Player machine identifiers
+	ExcludePlayerIDs []string
+	IncludeLibraries []string // Library section IDs
+	ExcludeLibraries []string
+}
+
+// Allows reports whether a session passes the filter. A nil filter allows
+// every session.
+func (f *SessionFilter) Allows(entry *SessionEntry) bool {
+	if f == nil {
+		return true
+	}
+	return filterRule(f.IncludePlayers, f.ExcludePlayers, entry.Player.Title, strings.EqualFold) &&
+		filterRule(f.IncludeProducts, f.ExcludeProducts, entry.Player.Product, strings.EqualFold) &&
+		filterRule(f.IncludePlayerIDs, f.ExcludePlayerIDs, entry.Player.MachineIdentifier, equalString) &&
+		filterRule(f.IncludeLibraries, f.ExcludeLibraries, entry.LibrarySectionID, equalString)
+}
+
+// filterRule applies one attribute's include and exclude lists to value.
+func filterRule(include, exclude []string, value string, match func(a, b string) bool) bool {
+	matchesAny := func(list []string) bool {
+		if value == "" {
+			return false
+		}
+		for _, v := range list {
+			if match(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(include) > 0 && !matchesAny(include) {
+		return false
+	}
+	return !matchesAny(exclude)
+}
+
+// equalString is == as a match function.
+func equalString(a, b string) bool {
+	return a == b
+}
+
+// SetSessionFilter sets the filter applied to the sessions this client
+// returns. Nil disables filtering. Safe to call while polling.
+func (c *Client) SetSessionFilter(filter *SessionFilter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filter = filter
+}
+
+// sessionFilter returns the configured filter, if any.
+func (c *Client) sessionFilter() *SessionFilter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.filter
+}
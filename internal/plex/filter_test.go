@@ -0,0 +1,47 @@
+package plex
+
+import "testing"
+
+func TestSessionFilter_Allows(t *testing.T) {
+	entry := SessionEntry{
+		LibrarySectionID: "4",
+		Player: SessionPlayer{
+			Title:             "Living Room TV",
+			Product:           "Plex for Android (TV)",
+			MachineIdentifier: "abc123",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter *SessionFilter
+		want   bool
+	}{
+		{"nil filter", nil, true},
+		{"empty filter", &SessionFilter{}, true},
+		{"excluded player, any case", &SessionFilter{ExcludePlayers: []string{"living room tv"}}, false},
+		{"included player", &SessionFilter{IncludePlayers: []string{"Living Room TV", "Plexamp"}}, true},
+		{"not an included player", &SessionFilter{IncludePlayers: []string{"Plexamp"}}, false},
+		{"excluded product", &SessionFilter{ExcludeProducts: []string{"Plex for Android (TV)"}}, false},
+		{"excluded machine identifier", &SessionFilter{ExcludePlayerIDs: []string{"abc123"}}, false},
+		{"machine identifiers match exactly", &SessionFilter{ExcludePlayerIDs: []string{"ABC123"}}, true},
+		{"excluded library", &SessionFilter{ExcludeLibraries: []string{"4"}}, false},
+		{"not an included library", &SessionFilter{IncludeLibraries: []string{"1", "2"}}, false},
+		{"exclude wins over include", &SessionFilter{IncludeLibraries: []string{"4"}, ExcludePlayers: []string{"Living Room TV"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Allows(&entry); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionFilter_LibraryIncludeRejectsItemsWithoutLibrary(t *testing.T) {
+	filter := &SessionFilter{IncludeLibraries: []string{"1"}}
+	if filter.Allows(&SessionEntry{Live: "1"}) {
+		t.Error("an item without a library should not match a library include list")
+	}
+}
@@ -0,0 +1,68 @@
+package plex
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Base URLs of the pages a playing item can link to.
+const (
+	plexWebURL     = "https://app.plex.tv/desktop/#!/"
+	watchURL       = "https://watch.plex.tv/"
+	musicBrainzURL = "https://musicbrainz.org/"
+	imdbURL        = "https://www.imdb.com/title/"
+)
+
+// PlexWebURL returns the item's page in Plex Web, which only people with
+// access to its server can open, or "" when the server isn't known.
+func (m *MediaSession) PlexWebURL() string {
+	if m.ServerID == "" || m.RatingKey == "" {
+		return ""
+	}
+	key := url.QueryEscape("/library/metadata/" + m.RatingKey)
+	return plexWebURL + "server/" + url.PathEscape(m.ServerID) + "/details?key=" + key
+}
+
+// WatchURL returns the public page of a movie, or of an episode's show, on
+// watch.plex.tv. Only items matched by the Plex agent have one; "" otherwise.
+func (m *MediaSession) WatchURL() string {
+	guid, slug, kind := m.GUID, m.Slug, "movie"
+	if m.MediaType == MediaTypeTV {
+		guid, slug, kind = m.ShowGUID, m.ShowSlug, "show"
+	} else if m.MediaType != MediaTypeMovie {
+		return ""
+	}
+	if !strings.HasPrefix(guid, "plex://"+kind+"/") {
+		return ""
+	}
+	if slug == "" {
+		// Servers before slugs were added: the agent ID opens the same page
+		// through the Discover provider
+		id := strings.TrimPrefix(guid, "plex://"+kind+"/")
+		return plexWebURL + "provider/tv.plex.provider.discover/details?key=" + url.QueryEscape("/library/metadata/"+id)
+	}
+	return watchURL + kind + "/" + url.PathEscape(slug)
+}
+
+// MusicBrainzURL returns the MusicBrainz page of a track's recording, or ""
+// without a MusicBrainz ID (which needs metadata enrichment).
+func (m *MediaSession) MusicBrainzURL() string {
+	if m.MediaType != MediaTypeMusic {
+		return ""
+	}
+	id := m.Metadata.ExternalID("mbid")
+	if id == "" {
+		return ""
+	}
+	return musicBrainzURL + "recording/" + url.PathEscape(id)
+}
+
+// IMDbURL returns the IMDb page of a movie or episode, or "" without an
+// IMDb ID (which needs metadata enrichment).
+func (m *MediaSession) IMDbURL() string {
+	id := m.Metadata.ExternalID("imdb")
+	if id == "" {
+		return ""
+	}
+	return imdbURL + url.PathEscape(id) + "/"
+}
@@ -0,0 +1,42 @@
+package plex
+
+import "testing"
+
+func TestMediaSessionLinks(t *testing.T) {
+	movie := &MediaSession{
+		MediaType: MediaTypeMovie, RatingKey: "42", ServerID: "abc123",
+		GUID: "plex://movie/5d7768ba96b655001fdc0408", Slug: "the-matrix",
+		Metadata: &ItemMetadata{GUIDs: []string{"imdb://tt0133093", "tmdb://603"}},
+	}
+	if got, want := movie.PlexWebURL(), "https://app.plex.tv/desktop/#!/server/abc123/details?key=%2Flibrary%2Fmetadata%2F42"; got != want {
+		t.Errorf("PlexWebURL() = %q, want %q", got, want)
+	}
+	if got, want := movie.WatchURL(), "https://watch.plex.tv/movie/the-matrix"; got != want {
+		t.Errorf("WatchURL() = %q, want %q", got, want)
+	}
+	if got, want := movie.IMDbURL(), "https://www.imdb.com/title/tt0133093/"; got != want {
+		t.Errorf("IMDbURL() = %q, want %q", got, want)
+	}
+
+	// Episodes link to their show; without a slug, through Discover
+	episode := &MediaSession{MediaType: MediaTypeTV, GUID: "plex://episode/1", ShowGUID: "plex://show/5d9c086c46115600200aa2fe"}
+	if got, want := episode.WatchURL(), "https://app.plex.tv/desktop/#!/provider/tv.plex.provider.discover/details?key=%2Flibrary%2Fmetadata%2F5d9c086c46115600200aa2fe"; got != want {
+		t.Errorf("WatchURL() = %q, want %q", got, want)
+	}
+
+	track := &MediaSession{
+		MediaType: MediaTypeMusic, RatingKey: "7", GUID: "plex://track/1",
+		Metadata: &ItemMetadata{GUIDs: []string{"mbid://5b11f4ce-a62d-471e-81fc-a69a8278c7da"}},
+	}
+	if got, want := track.MusicBrainzURL(), "https://musicbrainz.org/recording/5b11f4ce-a62d-471e-81fc-a69a8278c7da"; got != want {
+		t.Errorf("MusicBrainzURL() = %q, want %q", got, want)
+	}
+	if track.PlexWebURL() != "" || track.WatchURL() != "" || track.IMDbURL() != "" {
+		t.Errorf("expected no Plex Web, watch or IMDb link for %+v", track)
+	}
+
+	local := &MediaSession{MediaType: MediaTypeMovie, GUID: "local://42"}
+	if local.WatchURL() != "" {
+		t.Error("expected no watch link for an unmatched movie")
+	}
+}
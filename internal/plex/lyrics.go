@@ -0,0 +1,106 @@
+package plex
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+// lyricsResponse is the XML form of a lyrics stream, which servers return
+// for lyrics fetched from an online provider rather than a sidecar file.
+type lyricsResponse struct {
+	XMLName xml.Name `xml:"MediaContainer"`
+	Lyrics  []struct {
+		Timed string `xml:"timed,attr"` // "1" when lines carry offsets
+		Lines []struct {
+			StartOffset int64 `xml:"startOffset,attr"` // Milliseconds
+			Spans       []struct {
+				Text string `xml:"text,attr"`
+			} `xml:"Span"`
+		} `xml:"Line"`
+	} `xml:"Lyrics"`
+}
+
+// GetLyrics returns a track's lyrics from the server in LRC format, or ""
+// when it has none. Synced lyrics are preferred over plain text ones.
+func (c *Client) GetLyrics(ratingKey string) (string, error) {
+	entry, err := c.GetMetadata(ratingKey)
+	if err != nil {
+		return "", err
+	}
+	key := entry.lyricsKey()
+	if key == "" {
+		return "", nil
+	}
+
+	var body []byte
+	err = c.withFailover(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var err error
+		body, err = c.transport().get(ctx, key)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return string(body), nil
+	}
+	var resp lyricsResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return "", errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid lyrics response")
+	}
+	return resp.lrc(), nil
+}
+
+// lyricsKey returns the download path of the item's lyrics stream, or ""
+// when it has none.
+func (e *SessionEntry) lyricsKey() string {
+	key := ""
+	for _, m := range e.Media {
+		for _, p := range m.Parts {
+			for _, s := range p.Streams {
+				if s.StreamType != streamTypeLyrics || s.Key == "" {
+					continue
+				}
+				if strings.EqualFold(s.Codec, "lrc") {
+					return s.Key
+				}
+				if key == "" {
+					key = s.Key
+				}
+			}
+		}
+	}
+	return key
+}
+
+// lrc converts the first set of lyrics to LRC. Untimed lyrics are returned
+// as plain lines.
+func (r *lyricsResponse) lrc() string {
+	if len(r.Lyrics) == 0 {
+		return ""
+	}
+	lyrics := r.Lyrics[0]
+
+	var sb strings.Builder
+	for _, line := range lyrics.Lines {
+		if lyrics.Timed == "1" {
+			ms := line.StartOffset
+			fmt.Fprintf(&sb, "[%02d:%02d.%02d]", ms/60000, ms/1000%60, ms%1000/10)
+		}
+		for _, span := range line.Spans {
+			sb.WriteString(span.Text)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLyrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/library/metadata/5":
+			w.Write([]byte(`<MediaContainer size="1">
+  <Track ratingKey="5" type="track" title="Hysteria">
+    <Media><Part>
+      <Stream streamType="2" codec="flac"/>
+      <Stream streamType="4" codec="txt" key="/library/streams/8"/>
+      <Stream streamType="4" codec="lrc" key="/library/streams/9"/>
+    </Part></Media>
+  </Track>
+</MediaContainer>`))
+		case "/library/metadata/6":
+			w.Write([]byte(`<MediaContainer size="1"><Track ratingKey="6" type="track" title="Instrumental"/></MediaContainer>`))
+		case "/library/metadata/7":
+			w.Write([]byte(`<MediaContainer size="1"><Track ratingKey="7" type="track"><Media><Part><Stream streamType="4" key="/library/streams/10"/></Part></Media></Track></MediaContainer>`))
+		case "/library/streams/9":
+			w.Write([]byte(`<MediaContainer size="1">
+  <Lyrics timed="1" provider="com.plexapp.agents.lyricfind">
+    <Line startOffset="12500"><Span text="Out of touch, "/><Span text="out of reach yeah"/></Line>
+    <Line startOffset="61000"><Span text="Hysteria"/></Line>
+  </Lyrics>
+</MediaContainer>`))
+		case "/library/streams/10":
+			w.Write([]byte("[00:01.00]Sidecar line\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient("token", server.URL)
+
+	got, err := client.GetLyrics("5")
+	if err != nil {
+		t.Fatalf("GetLyrics() error = %v", err)
+	}
+	if want := "[00:12.50]Out of touch, out of reach yeah\n[01:01.00]Hysteria\n"; got != want {
+		t.Errorf("GetLyrics() = %q, want %q", got, want)
+	}
+
+	if got, err := client.GetLyrics("6"); err != nil || got != "" {
+		t.Errorf("expected no lyrics without a lyrics stream, got %q, %v", got, err)
+	}
+	if got, err := client.GetLyrics("7"); err != nil || got != "[00:01.00]Sidecar line\n" {
+		t.Errorf("expected the LRC file as is, got %q, %v", got, err)
+	}
+	if _, err := client.GetLyrics("404"); err == nil {
+		t.Error("expected an error for an unknown item")
+	}
+}
@@ -9,12 +9,13 @@ package plex
 // as parameters so they can be unit-tested with table-driven tests.
 
 // filterMusicSessions returns only the music sessions from the parsed
-// response that belong to the given user. An empty userID matches all
-// users. Fallback metadata is applied and artwork URLs are built via
-// the provided thumb URL builder.
+// response that belong to the given user and pass the filter (nil = all).
+// An empty userID matches all users. Fallback metadata is applied and
+// artwork URLs are built via the provided thumb URL builder.
 func filterMusicSessions(
 	sessionsResp *SessionsResponse,
 	userID string,
+	filter *SessionFilter,
 	buildThumbURL func(string) string,
 ) []MusicSession {
 	result := make([]MusicSession, 0, len(sessionsResp.Tracks))
@@ -27,6 +28,9 @@ func filterMusicSessions(
 		if entry.Type != "track" {
 			continue
 		}
+		if !filter.Allows(&entry) {
+			continue
+		}
 
 		thumbURL := ""
 		if entry.Thumb != "" && buildThumbURL != nil {
@@ -36,6 +40,8 @@ func filterMusicSessions(
 		session := MusicSession{
 			Session: Session{
 				SessionKey: entry.SessionKey,
+				RatingKey:  entry.RatingKey,
+				Key:        entry.Key,
 				UserID:     entry.User.ID,
 				UserName:   entry.User.Title,
 				Type:       entry.Type,
@@ -49,6 +55,12 @@ func filterMusicSessions(
 			ThumbURL:   thumbURL,
 			Duration:   entry.Duration,
 			ViewOffset: entry.ViewOffset,
+			Quality:    entry.quality(),
+			UserRating: entry.UserRating,
+
+			TrackNumber:     entry.Index,
+			PlayQueueID:     entry.PlayQueueID,
+			PlayQueueItemID: entry.PlayQueueItemID,
 		}
 
 		session.ApplyFallbacks()
@@ -58,12 +70,13 @@ func filterMusicSessions(
 }
 
 // filterMediaSessions returns MediaSessions matching the requested media
-// types (empty = all) for the given user. Applies fallbacks and builds
-// artwork URLs.
+// types (empty = all) and the filter (nil = all) for the given user.
+// Applies fallbacks and builds artwork URLs.
 func filterMediaSessions(
 	sessionsResp *SessionsResponse,
 	userID string,
 	mediaTypes []string,
+	filter *SessionFilter,
 	buildThumbURL func(string) string,
 ) []MediaSession {
 	wantType := make(map[string]bool, len(mediaTypes))
@@ -80,6 +93,9 @@ func filterMediaSessions(
 		if userID != "" && entry.User.ID != userID {
 			continue
 		}
+		if !filter.Allows(&entry) {
+			continue
+		}
 
 		thumbURL := ""
 		if entry.Thumb != "" && buildThumbURL != nil {
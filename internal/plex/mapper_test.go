@@ -26,7 +26,7 @@ func TestFilterMusicSessions_FiltersByUser(t *testing.T) {
 		},
 	}
 
-	got := filterMusicSessions(resp, "alice", nil)
+	got := filterMusicSessions(resp, "alice", nil, nil)
 
 	if len(got) != 1 {
 		t.Fatalf("expected 1 session for alice, got %d", len(got))
@@ -44,7 +44,7 @@ func TestFilterMusicSessions_EmptyUserIDReturnsAll(t *testing.T) {
 		},
 	}
 
-	got := filterMusicSessions(resp, "", nil)
+	got := filterMusicSessions(resp, "", nil, nil)
 
 	if len(got) != 2 {
 		t.Errorf("expected 2 sessions when userID empty, got %d", len(got))
@@ -58,7 +58,7 @@ func TestFilterMusicSessions_AppliesFallbacks(t *testing.T) {
 		},
 	}
 
-	got := filterMusicSessions(resp, "alice", nil)
+	got := filterMusicSessions(resp, "alice", nil, nil)
 
 	if len(got) != 1 {
 		t.Fatalf("expected 1 session, got %d", len(got))
@@ -77,7 +77,7 @@ func TestFilterMusicSessions_BuildsArtworkURL(t *testing.T) {
 	}
 	builder := func(thumb string) string { return "http://server" + thumb + "?token=abc" }
 
-	got := filterMusicSessions(resp, "u", builder)
+	got := filterMusicSessions(resp, "u", nil, builder)
 
 	if got[0].ThumbURL != "http://server/library/thumb/123?token=abc" {
 		t.Errorf("unexpected ThumbURL: %s", got[0].ThumbURL)
@@ -99,19 +99,19 @@ func TestFilterMediaSessions_FiltersByMediaType(t *testing.T) {
 	}
 
 	// Only music
-	got := filterMediaSessions(resp, "u", []string{"music"}, nil)
+	got := filterMediaSessions(resp, "u", []string{"music"}, nil, nil)
 	if len(got) != 1 {
 		t.Errorf("expected 1 music session, got %d", len(got))
 	}
 
 	// Music + movie
-	got = filterMediaSessions(resp, "u", []string{"music", "movie"}, nil)
+	got = filterMediaSessions(resp, "u", []string{"music", "movie"}, nil, nil)
 	if len(got) != 2 {
 		t.Errorf("expected 2 sessions (music + movie), got %d", len(got))
 	}
 
 	// Empty filter = all
-	got = filterMediaSessions(resp, "u", nil, nil)
+	got = filterMediaSessions(resp, "u", nil, nil, nil)
 	if len(got) != 4 {
 		t.Errorf("expected 4 sessions with empty filter, got %d", len(got))
 	}
@@ -125,7 +125,7 @@ func TestFilterMediaSessions_FiltersByUser(t *testing.T) {
 		},
 	}
 
-	got := filterMediaSessions(resp, "alice", nil, nil)
+	got := filterMediaSessions(resp, "alice", nil, nil, nil)
 
 	if len(got) != 1 {
 		t.Errorf("expected 1 session for alice, got %d", len(got))
@@ -162,3 +162,117 @@ func TestParseSessionsResponse_InvalidXML(t *testing.T) {
 		t.Error("expected error parsing invalid XML")
 	}
 }
+
+func TestFilterMediaSessions_MapsPhotosAndClips(t *testing.T) {
+	resp := &SessionsResponse{
+		Videos: []SessionEntry{
+			{Type: "clip", Title: "Video", GrandparentTitle: "Artist", User: SessionUser{ID: "u"}},
+		},
+		Photos: []SessionEntry{
+			{Type: "photo", Title: "IMG_0001", ParentTitle: "Vacation", User: SessionUser{ID: "u"}},
+		},
+	}
+
+	got := filterMediaSessions(resp, "u", []string{"photo", "clip"}, nil, nil)
+	if len(got) != 2 {
+		t.Fatalf("expected photo and clip sessions, got %d", len(got))
+	}
+	clip, photo := got[0], got[1]
+	if clip.MediaType != MediaTypeClip || clip.Artist != "Artist" {
+		t.Errorf("unexpected clip session: %+v", clip)
+	}
+	if photo.MediaType != MediaTypePhoto || photo.Album != "Vacation" {
+		t.Errorf("unexpected photo session: %+v", photo)
+	}
+}
+
+func TestNewMediaSessionFromEntry_LiveTV(t *testing.T) {
+	xml := []byte(`<?xml version="1.0"?>
+<MediaContainer size="1">
+  <Video sessionKey="7" type="episode" live="1" title="Pilot" grandparentTitle="The News" duration="3600000" viewOffset="600000">
+    <Media channelTitle="KABC" channelCallSign="KABC-DT" channelIdentifier="7.1"/>
+    <User id="u" title="User"/>
+    <Player state="playing" title="Living Room"/>
+  </Video>
+</MediaContainer>`)
+
+	resp, err := parseSessionsResponse(xml)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Videos) != 1 {
+		t.Fatalf("expected 1 video, got %d", len(resp.Videos))
+	}
+
+	ms := NewMediaSessionFromEntry(resp.Videos[0], "")
+	if ms.MediaType != MediaTypeLive {
+		t.Fatalf("expected live media type, got %q", ms.MediaType)
+	}
+	if ms.ProgramTitle != "The News" || ms.Title != "Pilot" {
+		t.Errorf("unexpected titles: program %q, title %q", ms.ProgramTitle, ms.Title)
+	}
+	if ms.ChannelName != "KABC" || ms.ChannelNumber != "7.1" {
+		t.Errorf("unexpected channel: %q %q", ms.ChannelName, ms.ChannelNumber)
+	}
+	if ms.Duration != 0 {
+		t.Errorf("expected no duration for live sessions, got %d", ms.Duration)
+	}
+}
+
+func TestNewMediaSessionFromEntry_LiveTVFallbacks(t *testing.T) {
+	ms := NewMediaSessionFromEntry(SessionEntry{Type: "movie", Live: "1", Title: "Heat"}, "")
+	ms.ApplyFallbacks()
+
+	if ms.ProgramTitle != "Heat" {
+		t.Errorf("expected movie title as program, got %q", ms.ProgramTitle)
+	}
+	if ms.ChannelName != FallbackChannel {
+		t.Errorf("expected %q channel fallback, got %q", FallbackChannel, ms.ChannelName)
+	}
+}
+
+func TestNewMediaSessionFromEntry_ProviderPodcast(t *testing.T) {
+	entry := SessionEntry{
+		Type:        "episode",
+		GUID:        "plex://podcast/5d9f4c3e/episode/42",
+		Title:       "Tides",
+		ParentTitle: "Ocean Stories",
+	}
+
+	ms := NewMediaSessionFromEntry(entry, "")
+
+	if ms.MediaType != MediaTypePodcast || ms.ShowTitle != "Ocean Stories" {
+		t.Errorf("expected a podcast of Ocean Stories, got %+v", ms)
+	}
+}
+
+func TestFilterMediaSessions_AppliesSessionFilter(t *testing.T) {
+	resp, err := parseSessionsResponse([]byte(`<?xml version="1.0"?>
+<MediaContainer size="2">
+  <Video sessionKey="1" type="movie" title="Kids Movie" librarySectionID="9">
+    <User id="u" title="User"/>
+    <Player state="playing" title="Tablet" machineIdentifier="tab-1"/>
+  </Video>
+  <Track sessionKey="2" type="track" title="Song" librarySectionID="1">
+    <User id="u" title="User"/>
+    <Player state="playing" title="Plexamp" machineIdentifier="amp-1"/>
+  </Track>
+</MediaContainer>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Videos[0].Player.MachineIdentifier != "tab-1" || resp.Videos[0].LibrarySectionID != "9" {
+		t.Fatalf("player identifier or library not parsed: %+v", resp.Videos[0])
+	}
+
+	filter := &SessionFilter{ExcludeLibraries: []string{"9"}}
+	got := filterMediaSessions(resp, "u", nil, filter, nil)
+	if len(got) != 1 || got[0].Title != "Song" {
+		t.Errorf("expected only the song, got %+v", got)
+	}
+
+	filter = &SessionFilter{ExcludePlayerIDs: []string{"amp-1"}}
+	if music := filterMusicSessions(resp, "u", filter, nil); len(music) != 0 {
+		t.Errorf("expected the Plexamp track to be excluded, got %+v", music)
+	}
+}
@@ -0,0 +1,288 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+const (
+	// metadataCacheSize bounds the enrichment cache. Items are looked up once
+	// per play, so this comfortably covers a long listening session; the
+	// cache is simply reset when it fills up.
+	metadataCacheSize = 256
+
+	// metadataRetryAfter is how long a failed lookup is remembered, so a
+	// server that can't serve an item's metadata isn't asked on every poll.
+	metadataRetryAfter = time.Minute
+)
+
+// ItemMetadata is the library metadata of a playing item that the sessions
+// endpoint doesn't include. For tracks, episodes and photos, fields the item
+// itself lacks (genres, moods, labels, studio) are taken from its album or
+// season.
+type ItemMetadata struct {
+	RatingKey  string   `json:"ratingKey"`
+	Genres     []string `json:"genres,omitempty"`
+	Moods      []string `json:"moods,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Studio     string   `json:"studio,omitempty"`     // Studio (video) or record label (music)
+	TrackCount int      `json:"trackCount,omitempty"` // Tracks on the album, episodes in the season, or photos in the photo album
+	GUIDs      []string `json:"guids,omitempty"`      // External IDs, e.g. "mbid://...", "tmdb://...", "imdb://..."
+}
+
+// ExternalID returns the ID for the given GUID scheme ("mbid", "tmdb",
+// "imdb", "tvdb") without its prefix, or "" when the item has none.
+func (m *ItemMetadata) ExternalID(scheme string) string {
+	if m == nil {
+		return ""
+	}
+	prefix := scheme + "://"
+	for _, guid := range m.GUIDs {
+		if strings.HasPrefix(guid, prefix) {
+			return strings.TrimPrefix(guid, prefix)
+		}
+	}
+	return ""
+}
+
+// metadataResponse is the XML response of /library/metadata/{ratingKey}.
+// Albums, artists, seasons and shows are returned as <Directory>.
+type metadataResponse struct {
+	XMLName     xml.Name       `xml:"MediaContainer"`
+	Tracks      []metadataItem `xml:"Track"`
+	Videos      []metadataItem `xml:"Video"`
+	Directories []metadataItem `xml:"Directory"`
+	Photos      []metadataItem `xml:"Photo"`
+}
+
+// metadataItem is a single library item with its tag and GUID children.
+type metadataItem struct {
+	RatingKey       string        `xml:"ratingKey,attr"`
+	ParentRatingKey string        `xml:"parentRatingKey,attr"` // Album (music) or season (TV)
+	Type            string        `xml:"type,attr"`
+	Studio          string        `xml:"studio,attr"`
+	LeafCount       int           `xml:"leafCount,attr"` // Tracks/episodes below a Directory
+	Genres          []metadataTag `xml:"Genre"`
+	Moods           []metadataTag `xml:"Mood"`
+	Labels          []metadataTag `xml:"Label"`
+	GUIDs           []metadataTag `xml:"Guid"`
+}
+
+// metadataTag is a <Genre tag="..."/>-style child. GUIDs use id instead.
+type metadataTag struct {
+	Tag string `xml:"tag,attr"`
+	ID  string `xml:"id,attr"`
+}
+
+// toItemMetadata converts the raw item, without parent fields.
+func (it *metadataItem) toItemMetadata() *ItemMetadata {
+	tags := func(in []metadataTag) []string {
+		var out []string
+		for _, t := range in {
+			if t.Tag != "" {
+				out = append(out, t.Tag)
+			}
+		}
+		return out
+	}
+	meta := &ItemMetadata{
+		RatingKey:  it.RatingKey,
+		Genres:     tags(it.Genres),
+		Moods:      tags(it.Moods),
+		Labels:     tags(it.Labels),
+		Studio:     it.Studio,
+		TrackCount: it.LeafCount,
+	}
+	for _, g := range it.GUIDs {
+		if g.ID != "" {
+			meta.GUIDs = append(meta.GUIDs, g.ID)
+		}
+	}
+	return meta
+}
+
+// GetMetadata fetches the library metadata of a single item by rating key.
+// The item is returned as a SessionEntry (the /library/metadata container
+// uses the same Track/Video/Photo elements as /status/sessions) without any
+// User or Player information.
+func (c *Client) GetMetadata(ratingKey string) (*SessionEntry, error) {
+	if ratingKey == "" {
+		return nil, errors.New(errors.PLEX_CONN_FAILED, "rating key cannot be empty")
+	}
+
+	body, err := c.fetchMetadata(metadataPath(ratingKey, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := parseSessionsResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	entries := resp.AllEntries()
+	if len(entries) == 0 {
+		return nil, errors.New(errors.PLEX_CONN_FAILED, fmt.Sprintf("no metadata for item %s", ratingKey))
+	}
+	return &entries[0], nil
+}
+
+// getMetadataItem fetches the tags and GUIDs of the item at path.
+func (c *Client) getMetadataItem(path string) (*metadataItem, error) {
+	body, err := c.fetchMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseMetadataResponse(body)
+}
+
+// fetchMetadata performs a metadata request, asking for external GUIDs.
+func (c *Client) fetchMetadata(path string) ([]byte, error) {
+	var body []byte
+	err := c.withFailover(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		var err error
+		body, err = c.transport().getQuery(ctx, path, url.Values{"includeGuids": {"1"}})
+		return err
+	})
+	return body, err
+}
+
+// metadataPath returns the request path of an item: the session's key when
+// it is a library path, else the path built from the rating key.
+func metadataPath(ratingKey, key string) string {
+	if strings.HasPrefix(key, "/library/metadata/") {
+		return key
+	}
+	return "/library/metadata/" + url.PathEscape(ratingKey)
+}
+
+// MetadataEnricher attaches full library metadata to sessions. Lookups are
+// cached per rating key, so each item costs one request (two for tracks,
+// episodes and photos, whose album or season is fetched too) however often it is
+// polled. Safe for concurrent use.
+type MetadataEnricher struct {
+	client *Client
+
+	mu     sync.Mutex
+	cache  map[string]*metadataItem
+	failed map[string]time.Time // Rating keys whose last lookup failed
+}
+
+// NewMetadataEnricher creates an enricher fetching from client's server.
+func NewMetadataEnricher(client *Client) *MetadataEnricher {
+	return &MetadataEnricher{
+		client: client,
+		cache:  make(map[string]*metadataItem),
+		failed: make(map[string]time.Time),
+	}
+}
+
+// EnrichMusic attaches metadata to a music session. No-op for nil sessions.
+func (e *MetadataEnricher) EnrichMusic(session *MusicSession) {
+	if session == nil {
+		return
+	}
+	session.Metadata = e.Lookup(session.RatingKey, session.Key)
+	if session.Metadata != nil {
+		session.TrackCount = session.Metadata.TrackCount
+	}
+}
+
+// EnrichMedia attaches metadata to a media session. No-op for nil sessions
+// and Live TV, whose airings aren't library items.
+func (e *MetadataEnricher) EnrichMedia(session *MediaSession) {
+	if session == nil || session.MediaType == MediaTypeLive {
+		return
+	}
+	session.Metadata = e.Lookup(session.RatingKey, session.Key)
+	if session.Metadata == nil {
+		return
+	}
+	switch session.MediaType {
+	case MediaTypePhoto:
+		session.PhotoCount = session.Metadata.TrackCount
+	case MediaTypeMusic, MediaTypeAudiobook:
+		session.TrackCount = session.Metadata.TrackCount
+	}
+}
+
+// Lookup returns the metadata of an item, merged with its parent's, or nil
+// when it can't be fetched. key is the session's item path and may be empty.
+func (e *MetadataEnricher) Lookup(ratingKey, key string) *ItemMetadata {
+	if ratingKey == "" && key == "" {
+		return nil
+	}
+	item := e.item(ratingKey, key)
+	if item == nil {
+		return nil
+	}
+
+	meta := item.toItemMetadata()
+	if item.ParentRatingKey != "" && (item.Type == "track" || item.Type == "episode" || item.Type == "photo") {
+		if parent := e.item(item.ParentRatingKey, ""); parent != nil {
+			p := parent.toItemMetadata()
+			if len(meta.Genres) == 0 {
+				meta.Genres = p.Genres
+			}
+			if len(meta.Moods) == 0 {
+				meta.Moods = p.Moods
+			}
+			if len(meta.Labels) == 0 {
+				meta.Labels = p.Labels
+			}
+			if meta.Studio == "" {
+				meta.Studio = p.Studio
+			}
+			meta.TrackCount = p.TrackCount
+		}
+	}
+	return meta
+}
+
+// item returns the cached raw item, fetching it on a miss.
+func (e *MetadataEnricher) item(ratingKey, key string) *metadataItem {
+	cacheKey := ratingKey
+	if cacheKey == "" {
+		cacheKey = key
+	}
+
+	e.mu.Lock()
+	if it, ok := e.cache[cacheKey]; ok {
+		e.mu.Unlock()
+		return it
+	}
+	if at, ok := e.failed[cacheKey]; ok && time.Since(at) < metadataRetryAfter {
+		e.mu.Unlock()
+		return nil
+	}
+	e.mu.Unlock()
+
+	it, err := e.client.getMetadataItem(metadataPath(ratingKey, key))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		log.Printf("Metadata lookup for %s failed: %v", cacheKey, err)
+		if len(e.failed) >= metadataCacheSize {
+			e.failed = make(map[string]time.Time)
+		}
+		e.failed[cacheKey] = time.Now()
+		return nil
+	}
+	if len(e.cache) >= metadataCacheSize {
+		e.cache = make(map[string]*metadataItem)
+	}
+	delete(e.failed, cacheKey)
+	e.cache[cacheKey] = it
+	return it
+}
@@ -0,0 +1,117 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const trackMetadataXML = `<MediaContainer size="1">
+  <Track ratingKey="123" key="/library/metadata/123" parentRatingKey="100" type="track" title="Song">
+    <Mood tag="Energetic"/>
+    <Guid id="mbid://5b11f4ce-a62d-471e-81fc-a69a8278c7da"/>
+  </Track>
+</MediaContainer>`
+
+const albumMetadataXML = `<MediaContainer size="1">
+  <Directory ratingKey="100" type="album" title="Album" studio="Sub Pop" leafCount="12">
+    <Genre tag="Grunge"/>
+    <Genre tag="Alternative"/>
+    <Label tag="Favorites"/>
+    <Guid id="mbid://1b022e01-4da6-387b-8658-8678046e4cef"/>
+  </Directory>
+</MediaContainer>`
+
+func TestParseMetadataResponse(t *testing.T) {
+	item, err := parseMetadataResponse([]byte(albumMetadataXML))
+	if err != nil {
+		t.Fatalf("parseMetadataResponse() error = %v", err)
+	}
+	meta := item.toItemMetadata()
+	if meta.RatingKey != "100" || meta.Studio != "Sub Pop" || meta.TrackCount != 12 {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+	if len(meta.Genres) != 2 || meta.Genres[0] != "Grunge" || len(meta.Labels) != 1 {
+		t.Errorf("unexpected tags: genres=%v labels=%v", meta.Genres, meta.Labels)
+	}
+
+	if _, err := parseMetadataResponse([]byte(`<MediaContainer size="0"/>`)); err == nil {
+		t.Error("expected error for an empty container")
+	}
+	if _, err := parseMetadataResponse([]byte(`not xml`)); err == nil {
+		t.Error("expected error for invalid XML")
+	}
+}
+
+func TestItemMetadataExternalID(t *testing.T) {
+	meta := &ItemMetadata{GUIDs: []string{"imdb://tt0111161", "tmdb://278"}}
+	if got := meta.ExternalID("tmdb"); got != "278" {
+		t.Errorf("ExternalID(tmdb) = %q, want 278", got)
+	}
+	if got := meta.ExternalID("mbid"); got != "" {
+		t.Errorf("ExternalID(mbid) = %q, want empty", got)
+	}
+	var none *ItemMetadata
+	if got := none.ExternalID("imdb"); got != "" {
+		t.Errorf("ExternalID on nil metadata = %q, want empty", got)
+	}
+}
+
+func TestMetadataEnricherMergesParentAndCaches(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("includeGuids") != "1" || r.URL.Query().Get("X-Plex-Token") != "token" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/library/metadata/123":
+			w.Write([]byte(trackMetadataXML))
+		case "/library/metadata/100":
+			w.Write([]byte(albumMetadataXML))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	enricher := NewMetadataEnricher(NewClient("token", server.URL))
+	session := &MusicSession{Session: Session{RatingKey: "123", Key: "/library/metadata/123"}}
+	enricher.EnrichMusic(session)
+
+	meta := session.Metadata
+	if meta == nil {
+		t.Fatal("expected metadata to be attached")
+	}
+	// Track's own fields win; missing ones come from the album
+	if len(meta.Moods) != 1 || meta.Moods[0] != "Energetic" {
+		t.Errorf("expected the track's moods, got %v", meta.Moods)
+	}
+	if len(meta.Genres) != 2 || meta.Studio != "Sub Pop" || meta.TrackCount != 12 {
+		t.Errorf("expected album genres, label and track count, got %+v", meta)
+	}
+	if session.TrackCount != 12 {
+		t.Errorf("expected the album's track count on the session, got %d", session.TrackCount)
+	}
+	if got := meta.ExternalID("mbid"); got != "5b11f4ce-a62d-471e-81fc-a69a8278c7da" {
+		t.Errorf("expected the track's MusicBrainz ID, got %q", got)
+	}
+
+	// A second play of the same item is served from the cache
+	enricher.EnrichMedia(&MediaSession{RatingKey: "123", Key: "/library/metadata/123"})
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected 2 requests (track and album), got %d", n)
+	}
+
+	// Failed lookups are not retried on every poll
+	missing := &MediaSession{RatingKey: "999"}
+	enricher.EnrichMedia(missing)
+	enricher.EnrichMedia(missing)
+	if missing.Metadata != nil {
+		t.Error("expected no metadata for an unknown item")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("expected a single request for the failing item, got %d total", n)
+	}
+}
@@ -0,0 +1,333 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"plexcord/internal/errors"
+)
+
+const (
+	// notificationsPath is the Plex Media Server event stream endpoint.
+	notificationsPath = "/:/websockets/notifications"
+
+	// notificationResyncInterval is how often the poller still fetches
+	// /status/sessions while the notifications socket is healthy. Events drive
+	// every state change; this slow resync only guards against a missed frame.
+	notificationResyncInterval = 30 * time.Second
+
+	// Reconnect backoff bounds for a dropped notifications socket. While the
+	// socket is down the poller is back on its regular interval, so these only
+	// control how quickly we return to event-driven mode.
+	notificationMinReconnect = time.Second
+	notificationMaxReconnect = 30 * time.Second
+)
+
+// notificationEnvelope is the top-level JSON frame sent on the notifications
+// socket. Only "playing" containers carry PlaySessionStateNotification entries;
+// other types (timeline, activity, status, ...) are ignored.
+type notificationEnvelope struct {
+	Container struct {
+		Type         string                         `json:"type"`
+		PlaySessions []PlaySessionStateNotification `json:"PlaySessionStateNotification"`
+		Size         int                            `json:"size"`
+	} `json:"NotificationContainer"`
+}
+
+// PlaySessionStateNotification is a single playback state change pushed by
+// the server. It identifies the session and item but carries no titles, so the
+// poller still fetches /status/sessions to build the full session.
+type PlaySessionStateNotification struct {
+	SessionKey       string `json:"sessionKey"`
+	ClientIdentifier string `json:"clientIdentifier"`
+	RatingKey        string `json:"ratingKey"`
+	Key              string `json:"key"`
+	State            string `json:"state"` // "playing", "paused", "buffering", "stopped"
+	ViewOffset       int64  `json:"viewOffset"`
+	PlayQueueID      int64  `json:"playQueueID"`
+	PlayQueueItemID  int64  `json:"playQueueItemID"`
+}
+
+// parsePlayNotifications extracts the play session state changes from a raw
+// notifications frame. Frames of other types yield an empty slice.
+func parsePlayNotifications(data []byte) ([]PlaySessionStateNotification, error) {
+	var env notificationEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Container.Type != "playing" {
+		return nil, nil
+	}
+	return env.Container.PlaySessions, nil
+}
+
+// NotificationSource turns the server's notifications WebSocket into wake-ups
+// for the poller. Each PlaySessionStateNotification that changes a session's
+// state or item, or moves its position by more than DefaultSeekTolerance from
+// where playback would be, triggers an immediate /status/sessions fetch, so
+// updates reach the session channel within milliseconds instead of on the
+// next tick.
+//
+// When the socket is down the source reports itself disconnected and the
+// poller falls back to its regular interval until the socket reconnects.
+type NotificationSource struct {
+	client *Client
+	dialer *websocket.Dialer
+	wakeC  chan struct{}
+
+	// lastSeen holds the last notification per session key so repeated
+	// progress notifications for an unchanged session don't trigger
+	// redundant fetches.
+	lastSeen map[string]notifiedSession
+
+	// playQueues holds the play queue ID per session key, which
+	// /status/sessions doesn't report.
+	playQueues map[string]string
+
+	mu        sync.RWMutex // Protects lastSeen, playQueues and connected
+	connected bool
+}
+
+// notifiedSession is what the last notification said about a session.
+type notifiedSession struct {
+	state     string
+	ratingKey string
+	position  playbackPosition
+}
+
+// NewNotificationSource creates a notification source bound to the client's
+// server URL and token. Call Probe to check the server supports it before
+// handing it to Poller.SetNotificationSource.
+func NewNotificationSource(client *Client) *NotificationSource {
+	return &NotificationSource{
+		client:     client,
+		dialer:     notificationDialer(client),
+		wakeC:      make(chan struct{}, 1),
+		lastSeen:   make(map[string]notifiedSession),
+		playQueues: make(map[string]string),
+	}
+}
+
+// Probe checks that the server accepts a notifications socket with the
+// client's token. Servers that are too old, sit behind a proxy that strips
+// upgrades, or reject the token return an error and should be polled instead.
+func (n *NotificationSource) Probe(ctx context.Context) error {
+	conn, err := n.dial(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.Close(); err != nil {
+		log.Printf("Warning: Failed to close notifications probe socket: %v", err)
+	}
+	return nil
+}
+
+// Connected reports whether the notifications socket is currently open.
+func (n *NotificationSource) Connected() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.connected
+}
+
+// PlayQueueID returns the play queue a session plays from, as last
+// notified, or "" when unknown.
+func (n *NotificationSource) PlayQueueID(sessionKey string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.playQueues[sessionKey]
+}
+
+// Wake returns the channel that fires when the poller should fetch sessions
+// immediately: on a relevant notification and on every connect/disconnect.
+func (n *NotificationSource) Wake() <-chan struct{} {
+	return n.wakeC
+}
+
+// run keeps the notifications socket open until ctx is cancelled or stopCh is
+// closed, reconnecting with backoff whenever it drops.
+func (n *NotificationSource) run(ctx context.Context, stopCh <-chan struct{}) {
+	delay := notificationMinReconnect
+	for {
+		conn, err := n.dial(ctx)
+		if err == nil {
+			delay = notificationMinReconnect
+			n.setConnected(true)
+			log.Printf("Plex notifications socket connected")
+			n.readLoop(ctx, stopCh, conn)
+			n.setConnected(false)
+			log.Printf("Plex notifications socket closed, falling back to polling")
+		} else {
+			log.Printf("Plex notifications socket unavailable: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-stopCh:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > notificationMaxReconnect {
+			delay = notificationMaxReconnect
+		}
+	}
+}
+
+// readLoop consumes frames until the socket fails or the source is stopped.
+func (n *NotificationSource) readLoop(ctx context.Context, stopCh <-chan struct{}, conn *websocket.Conn) {
+	done := make(chan struct{})
+	defer close(done)
+
+	// ReadMessage blocks; closing the socket is the only way to interrupt it.
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopCh:
+		case <-done:
+		}
+		if err := conn.Close(); err != nil {
+			log.Printf("Warning: Failed to close notifications socket: %v", err)
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		notifications, err := parsePlayNotifications(data)
+		if err != nil {
+			log.Printf("Ignoring malformed Plex notification: %v", err)
+			continue
+		}
+		if n.observe(notifications, time.Now()) {
+			n.wake()
+		}
+	}
+}
+
+// observe records the notifications received at now and reports whether any
+// of them changed a session's state or item, or seeked it, since the last one
+// seen for that session.
+func (n *NotificationSource) observe(notifications []PlaySessionStateNotification, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	changed := false
+	for _, ns := range notifications {
+		if ns.State == "stopped" {
+			delete(n.lastSeen, ns.SessionKey)
+			delete(n.playQueues, ns.SessionKey)
+			changed = true
+			continue
+		}
+		if ns.PlayQueueID > 0 {
+			n.playQueues[ns.SessionKey] = strconv.FormatInt(ns.PlayQueueID, 10)
+		}
+		curr := notifiedSession{
+			state:     ns.State,
+			ratingKey: ns.RatingKey,
+			position:  playbackPosition{offset: ns.ViewOffset, at: now, playing: ns.State == "playing"},
+		}
+		prev, seen := n.lastSeen[ns.SessionKey]
+		n.lastSeen[ns.SessionKey] = curr
+		if !seen || prev.state != curr.state || prev.ratingKey != curr.ratingKey ||
+			curr.position.seekedFrom(prev.position, DefaultSeekTolerance) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// setConnected flips the connection flag and wakes the poller so it picks up
+// the new interval (and resyncs sessions) straight away.
+func (n *NotificationSource) setConnected(connected bool) {
+	n.mu.Lock()
+	n.connected = connected
+	n.mu.Unlock()
+	n.wake()
+}
+
+// wake performs a non-blocking send; a pending wake-up already covers this one.
+func (n *NotificationSource) wake() {
+	select {
+	case n.wakeC <- struct{}{}:
+	default:
+	}
+}
+
+// dial opens the notifications socket for the client's current server URL.
+func (n *NotificationSource) dial(ctx context.Context) (*websocket.Conn, error) {
+	wsURL, err := notificationsURL(n.client.ServerURL(), n.client.token)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("User-Agent", "PlexCord/1.0")
+
+	conn, resp, err := n.dialer.DialContext(ctx, wsURL, header)
+	if resp != nil && resp.Body != nil {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Warning: Failed to close response body: %v", closeErr)
+		}
+	}
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			return nil, mapHTTPStatusCode(resp.StatusCode)
+		}
+		return nil, errors.Wrap(err, errors.PLEX_UNREACHABLE, "failed to open notifications socket")
+	}
+	return conn, nil
+}
+
+// notificationDialer returns a websocket dialer that reaches the server the
+// way the client's HTTP requests do, with the same proxy, TLS settings and
+// dial function, so a server that answers polling also answers the socket.
+func notificationDialer(client *Client) *websocket.Dialer {
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: 5 * time.Second,
+		Proxy:            http.ProxyFromEnvironment,
+	}
+	rt := client.httpClient.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if t, ok := rt.(*http.Transport); ok {
+		dialer.Proxy = t.Proxy
+		dialer.NetDialContext = t.DialContext
+		if t.TLSClientConfig != nil {
+			dialer.TLSClientConfig = t.TLSClientConfig.Clone()
+		}
+	}
+	return dialer
+}
+
+// notificationsURL converts an http(s) server URL into the ws(s) URL of the
+// notifications endpoint, authenticated via the X-Plex-Token query parameter.
+func notificationsURL(serverURL, token string) (string, error) {
+	u, err := url.Parse(strings.TrimRight(serverURL, "/"))
+	if err != nil {
+		return "", errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid server URL")
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return "", errors.New(errors.PLEX_CONN_FAILED, "server URL must use http or https scheme")
+	}
+	u.Path += notificationsPath
+	u.RawQuery = url.Values{"X-Plex-Token": {token}}.Encode()
+	return u.String(), nil
+}
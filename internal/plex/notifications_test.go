@@ -0,0 +1,302 @@
+package plex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const playingFrame = `{"NotificationContainer":{"type":"playing","size":1,"PlaySessionStateNotification":[
+  {"sessionKey":"7","clientIdentifier":"abc","ratingKey":"101","key":"/library/metadata/101","state":"%s","viewOffset":%d}
+]}}`
+
+// notificationServer serves /status/sessions and the notifications socket.
+// Frames written to the returned channel are pushed to connected sockets.
+// offset is the session's reported position.
+func notificationServer(t *testing.T, state *atomic.Value, offset *atomic.Int64) (*httptest.Server, chan string) {
+	t.Helper()
+	frames := make(chan string, 4)
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == notificationsPath {
+			if r.URL.Query().Get("X-Plex-Token") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			for frame := range frames {
+				if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+					return
+				}
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<MediaContainer size="1">
+  <Track sessionKey="7" type="track" title="Song" viewOffset="` + strconv.FormatInt(offset.Load(), 10) + `">
+    <User id="user1" title="User"/>
+    <Player state="` + state.Load().(string) + `" title="Player"/>
+  </Track>
+</MediaContainer>`))
+	}))
+	t.Cleanup(func() {
+		close(frames)
+		server.Close()
+	})
+	return server, frames
+}
+
+func TestParsePlayNotifications(t *testing.T) {
+	got, err := parsePlayNotifications([]byte(fmt.Sprintf(playingFrame, "paused", 1000)))
+	if err != nil {
+		t.Fatalf("parsePlayNotifications() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(got))
+	}
+	n := got[0]
+	if n.SessionKey != "7" || n.RatingKey != "101" || n.State != "paused" || n.ViewOffset != 1000 {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+
+	other, err := parsePlayNotifications([]byte(`{"NotificationContainer":{"type":"activity","size":1}}`))
+	if err != nil {
+		t.Fatalf("parsePlayNotifications() error = %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("expected non-playing frames to be ignored, got %d", len(other))
+	}
+
+	if _, err := parsePlayNotifications([]byte("not json")); err == nil {
+		t.Error("expected error for malformed frame")
+	}
+}
+
+func TestNotificationsURL(t *testing.T) {
+	tests := []struct {
+		serverURL string
+		want      string
+		wantErr   bool
+	}{
+		{"http://192.168.1.10:32400", "ws://192.168.1.10:32400/:/websockets/notifications?X-Plex-Token=tok", false},
+		{"https://plex.example.com/", "wss://plex.example.com/:/websockets/notifications?X-Plex-Token=tok", false},
+		{"ftp://plex.example.com", "", true},
+	}
+	for _, tt := range tests {
+		got, err := notificationsURL(tt.serverURL, "tok")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("notificationsURL(%q) error = %v, wantErr %v", tt.serverURL, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("notificationsURL(%q) = %q, want %q", tt.serverURL, got, tt.want)
+		}
+	}
+}
+
+func TestNotificationSourceObserveDedupes(t *testing.T) {
+	n := NewNotificationSource(NewClient("token", "http://localhost:32400"))
+
+	now := time.Now()
+	playing := func(offset int64) []PlaySessionStateNotification {
+		return []PlaySessionStateNotification{{SessionKey: "7", RatingKey: "101", State: "playing", ViewOffset: offset}}
+	}
+	if !n.observe(playing(1000), now) {
+		t.Error("first notification should be a change")
+	}
+	if n.observe(playing(11000), now.Add(10*time.Second)) {
+		t.Error("repeated progress notification should not be a change")
+	}
+	if !n.observe(playing(90000), now.Add(20*time.Second)) {
+		t.Error("position jump should be a change")
+	}
+	if !n.observe([]PlaySessionStateNotification{{SessionKey: "7", RatingKey: "101", State: "paused"}}, now) {
+		t.Error("state change should be a change")
+	}
+	if !n.observe([]PlaySessionStateNotification{{SessionKey: "7", RatingKey: "102", State: "paused"}}, now) {
+		t.Error("item change should be a change")
+	}
+	if !n.observe([]PlaySessionStateNotification{{SessionKey: "7", State: "stopped"}}, now) {
+		t.Error("stop should be a change")
+	}
+}
+
+func TestNotificationSourcePlayQueueID(t *testing.T) {
+	n := NewNotificationSource(NewClient("token", "http://localhost:32400"))
+
+	n.observe([]PlaySessionStateNotification{{SessionKey: "7", RatingKey: "101", State: "playing", PlayQueueID: 3120}}, time.Now())
+	if got := n.PlayQueueID("7"); got != "3120" {
+		t.Errorf("PlayQueueID = %q, want 3120", got)
+	}
+	n.observe([]PlaySessionStateNotification{{SessionKey: "7", State: "stopped"}}, time.Now())
+	if got := n.PlayQueueID("7"); got != "" {
+		t.Errorf("expected the queue to be forgotten on stop, got %q", got)
+	}
+}
+
+func TestNotificationSourceProbe(t *testing.T) {
+	var state atomic.Value
+	state.Store("playing")
+	server, _ := notificationServer(t, &state, new(atomic.Int64))
+
+	ok := NewNotificationSource(NewClient("token", server.URL))
+	if err := ok.Probe(context.Background()); err != nil {
+		t.Errorf("Probe() error = %v", err)
+	}
+
+	bad := NewNotificationSource(NewClient("wrong", server.URL))
+	if err := bad.Probe(context.Background()); err == nil {
+		t.Error("expected Probe() to fail with an invalid token")
+	}
+
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+	unsupported := NewNotificationSource(NewClient("token", plain.URL))
+	if err := unsupported.Probe(context.Background()); err == nil {
+		t.Error("expected Probe() to fail when the server has no notifications endpoint")
+	}
+}
+
+// TestNotificationSourceProbeUsesClientTransport verifies the socket is
+// dialed with the client's TLS settings, so an HTTPS server with a
+// certificate the client trusts accepts it like it accepts polling.
+func TestNotificationSourceProbeUsesClientTransport(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient("token", server.URL)
+	client.httpClient = server.Client()
+	if err := NewNotificationSource(client).Probe(context.Background()); err != nil {
+		t.Errorf("Probe() error = %v", err)
+	}
+}
+
+// TestPollerNotificationDrivenUpdate verifies a play-state notification makes
+// the poller fetch and emit immediately, well before its interval elapses.
+func TestPollerNotificationDrivenUpdate(t *testing.T) {
+	var state atomic.Value
+	state.Store("playing")
+	var offset atomic.Int64
+	offset.Store(1000)
+	server, frames := notificationServer(t, &state, &offset)
+
+	client := NewClient("token", server.URL)
+	poller := NewPoller(client, "user1", 60*time.Second)
+	source := NewNotificationSource(client)
+	poller.SetNotificationSource(source)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sessionCh := poller.Start(ctx)
+	defer poller.Stop()
+
+	select {
+	case session := <-sessionCh:
+		if session == nil || session.State != "playing" {
+			t.Fatalf("expected initial playing session, got %+v", session)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for initial session")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !poller.UsesNotifications() {
+		if time.Now().After(deadline) {
+			t.Fatal("notifications socket never connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := poller.pollInterval(); got != notificationResyncInterval {
+		t.Errorf("pollInterval() = %v while connected, want %v", got, notificationResyncInterval)
+	}
+
+	state.Store("paused")
+	frames <- fmt.Sprintf(playingFrame, "paused", offset.Load())
+
+	select {
+	case session := <-sessionCh:
+		if session == nil || session.State != "paused" {
+			t.Errorf("expected paused session, got %+v", session)
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout waiting for notification-driven update")
+	}
+}
+
+// TestPollerNotificationDrivenSeek verifies a seek reported only through
+// the notification's position is fetched and emitted straight away, not on
+// the next resync.
+func TestPollerNotificationDrivenSeek(t *testing.T) {
+	var state atomic.Value
+	state.Store("playing")
+	var offset atomic.Int64
+	offset.Store(1000)
+	server, frames := notificationServer(t, &state, &offset)
+
+	client := NewClient("token", server.URL)
+	poller := NewPoller(client, "user1", 60*time.Second)
+	poller.SetNotificationSource(NewNotificationSource(client))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sessionCh := poller.Start(ctx)
+	defer poller.Stop()
+
+	select {
+	case session := <-sessionCh:
+		if session == nil || session.ViewOffset != 1000 {
+			t.Fatalf("expected initial session at 1s, got %+v", session)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for initial session")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !poller.UsesNotifications() {
+		if time.Now().After(deadline) {
+			t.Fatal("notifications socket never connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// The session as the source first hears of it; let the fetches it and
+	// the connect trigger settle before moving the position
+	frames <- fmt.Sprintf(playingFrame, "playing", offset.Load())
+	time.Sleep(200 * time.Millisecond)
+
+	// Same state and item, but two minutes further in
+	offset.Store(121000)
+	frames <- fmt.Sprintf(playingFrame, "playing", offset.Load())
+
+	select {
+	case session := <-sessionCh:
+		if session == nil || session.ViewOffset != 121000 || !session.Seeked {
+			t.Errorf("expected a seeked session at 121s, got %+v", session)
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout waiting for the seek to be fetched")
+	}
+}
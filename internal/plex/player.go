@@ -0,0 +1,109 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+// Playback commands, named after their /player/playback/* endpoints.
+const (
+	CommandPlay         = "play"
+	CommandPause        = "pause"
+	CommandSkipNext     = "skipNext"
+	CommandSkipPrevious = "skipPrevious"
+	CommandStop         = "stop"
+)
+
+// playerControlClientID identifies PlexCord to the players it controls.
+const playerControlClientID = "plexcord-remote"
+
+// validCommand reports whether command is one of the playback commands.
+func validCommand(command string) bool {
+	switch command {
+	case CommandPlay, CommandPause, CommandSkipNext, CommandSkipPrevious, CommandStop:
+		return true
+	}
+	return false
+}
+
+// commandMediaType returns the timeline kind ("music", "video" or "photo")
+// a playback command applies to for a session's media type.
+func commandMediaType(mediaType string) string {
+	switch mediaType {
+	case MediaTypeMusic, MediaTypeAudiobook, MediaTypePodcast:
+		return "music"
+	case MediaTypePhoto:
+		return "photo"
+	default:
+		return "video"
+	}
+}
+
+// PlayerController remote-controls players through the server: the
+// /player/playback/* commands are sent to the server, which forwards them to
+// the player named by its machine identifier. Safe for concurrent use.
+type PlayerController struct {
+	client    *Client
+	commandID atomic.Int64
+}
+
+// NewPlayerController creates a controller for the players of client's
+// server.
+func NewPlayerController(client *Client) *PlayerController {
+	return &PlayerController{client: client}
+}
+
+// Play resumes playback on the player.
+func (pc *PlayerController) Play(playerID, mediaType string) error {
+	return pc.Command(playerID, mediaType, CommandPlay)
+}
+
+// Pause pauses playback on the player.
+func (pc *PlayerController) Pause(playerID, mediaType string) error {
+	return pc.Command(playerID, mediaType, CommandPause)
+}
+
+// SkipNext skips to the next item of the player's queue.
+func (pc *PlayerController) SkipNext(playerID, mediaType string) error {
+	return pc.Command(playerID, mediaType, CommandSkipNext)
+}
+
+// SkipPrevious goes back to the previous item, or the start of the current
+// one, as the player decides.
+func (pc *PlayerController) SkipPrevious(playerID, mediaType string) error {
+	return pc.Command(playerID, mediaType, CommandSkipPrevious)
+}
+
+// Command sends a playback command to the player with machine identifier
+// playerID, for its session of mediaType.
+func (pc *PlayerController) Command(playerID, mediaType, command string) error {
+	if playerID == "" {
+		return errors.New(errors.INVALID_INPUT, "player identifier cannot be empty")
+	}
+	if !validCommand(command) {
+		return errors.New(errors.INVALID_INPUT, "unknown playback command: "+command)
+	}
+
+	query := url.Values{
+		"type":      {commandMediaType(mediaType)},
+		"commandID": {strconv.FormatInt(pc.commandID.Add(1), 10)},
+	}
+	header := http.Header{}
+	header.Set("X-Plex-Client-Identifier", playerControlClientID)
+	header.Set("X-Plex-Target-Client-Identifier", playerID)
+
+	return pc.client.withFailover(func() error {
+		// Players can take a moment to act on a command
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := pc.client.transport().getHeader(ctx, "/player/playback/"+command, query, header)
+		return err
+	})
+}
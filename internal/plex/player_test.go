@@ -0,0 +1,70 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPlayerControllerCommands(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/player/playback/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("X-Plex-Target-Client-Identifier") != "player-1" || r.Header.Get("X-Plex-Client-Identifier") == "" {
+			t.Errorf("missing target or client identifier: %v", r.Header)
+		}
+		if r.URL.Query().Get("X-Plex-Token") != "token" || r.URL.Query().Get("commandID") == "" {
+			t.Errorf("missing token or commandID: %s", r.URL.RawQuery)
+		}
+		mu.Lock()
+		got = append(got, strings.TrimPrefix(r.URL.Path, "/player/playback/")+" "+r.URL.Query().Get("type"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	pc := NewPlayerController(NewClient("token", server.URL))
+	steps := []func() error{
+		func() error { return pc.Pause("player-1", MediaTypeMusic) },
+		func() error { return pc.Play("player-1", MediaTypeMusic) },
+		func() error { return pc.SkipNext("player-1", MediaTypeTV) },
+		func() error { return pc.SkipPrevious("player-1", MediaTypeAudiobook) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("command %d failed: %v", i, err)
+		}
+	}
+
+	want := []string{"pause music", "play music", "skipNext video", "skipPrevious music"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("commands = %v, want %v", got, want)
+	}
+
+	if err := pc.Command("player-1", MediaTypeMusic, "shuffle"); err == nil {
+		t.Error("expected an error for an unknown command")
+	}
+	if err := pc.Pause("", MediaTypeMusic); err == nil {
+		t.Error("expected an error without a player")
+	}
+	if len(got) != 4 {
+		t.Errorf("invalid commands should not reach the server, got %v", got)
+	}
+}
+
+func TestPlayerControllerPlayerUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server answers 404 for players it can't reach
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if err := NewPlayerController(NewClient("token", server.URL)).Pause("gone", MediaTypeMovie); err == nil {
+		t.Error("expected an error when the server can't reach the player")
+	}
+}
@@ -0,0 +1,206 @@
+package plex
+
+import (
+	"context"
+	"encoding/xml"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+const (
+	// upNextLimit is how many upcoming items are attached to a session.
+	upNextLimit = 5
+
+	// playQueueRefreshAfter is how long a fetched play queue is trusted while
+	// its item keeps playing. Moving to another item refetches it straight
+	// away; this only bounds how long an edit of the queue goes unnoticed.
+	playQueueRefreshAfter = time.Minute
+
+	// playQueueCacheSize bounds the resolver's cache. A user rarely has more
+	// than a couple of queues at once; the cache is reset when it fills up.
+	playQueueCacheSize = 16
+)
+
+// PlayQueueItem is an entry of a play queue.
+type PlayQueueItem struct {
+	ItemID    string `json:"itemId"`    // Play queue item ID, unique within the queue
+	RatingKey string `json:"ratingKey"` // Library item ID
+	Type      string `json:"type"`      // Plex type: "track", "episode", "movie", ...
+	Title     string `json:"title"`
+	Artist    string `json:"artist,omitempty"`    // Artist (music) or show (TV)
+	Album     string `json:"album,omitempty"`     // Album (music) or season (TV)
+	Duration  int64  `json:"duration,omitempty"`  // Milliseconds
+	ThumbPath string `json:"thumbPath,omitempty"` // Relative artwork path
+}
+
+// PlayQueue is a window of a play queue around its selected item, as
+// returned by /playQueues/{id}.
+type PlayQueue struct {
+	ID             string          `json:"id"`
+	SelectedItemID string          `json:"selectedItemId"` // Item the player is on
+	TotalCount     int             `json:"totalCount"`     // Items in the whole queue, not just Items
+	Items          []PlayQueueItem `json:"items"`
+}
+
+// After returns up to n items following itemID (the selected item when
+// itemID is empty). Nil when the item isn't in the fetched window or is last.
+func (q *PlayQueue) After(itemID string, n int) []PlayQueueItem {
+	if itemID == "" {
+		itemID = q.SelectedItemID
+	}
+	for i, item := range q.Items {
+		if item.ItemID != itemID {
+			continue
+		}
+		rest := q.Items[i+1:]
+		if len(rest) > n {
+			rest = rest[:n]
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		return append([]PlayQueueItem(nil), rest...)
+	}
+	return nil
+}
+
+// playQueueResponse is the XML response of /playQueues/{id}. Items use the
+// same Track/Video elements as /status/sessions.
+type playQueueResponse struct {
+	XMLName        xml.Name       `xml:"MediaContainer"`
+	ID             string         `xml:"playQueueID,attr"`
+	SelectedItemID string         `xml:"playQueueSelectedItemID,attr"`
+	TotalCount     int            `xml:"playQueueTotalCount,attr"`
+	Tracks         []SessionEntry `xml:"Track"`
+	Videos         []SessionEntry `xml:"Video"`
+}
+
+// GetPlayQueue fetches a play queue by ID.
+func (c *Client) GetPlayQueue(id string) (*PlayQueue, error) {
+	if id == "" {
+		return nil, errors.New(errors.PLEX_CONN_FAILED, "play queue ID cannot be empty")
+	}
+
+	var body []byte
+	err := c.withFailover(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		var err error
+		body, err = c.transport().get(ctx, "/playQueues/"+url.PathEscape(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parsePlayQueue(body)
+}
+
+// parsePlayQueue parses a /playQueues/{id} body.
+func parsePlayQueue(body []byte) (*PlayQueue, error) {
+	var resp playQueueResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid play queue response")
+	}
+
+	queue := &PlayQueue{
+		ID:             resp.ID,
+		SelectedItemID: resp.SelectedItemID,
+		TotalCount:     resp.TotalCount,
+	}
+	// Queues hold a single kind of media, so the order within it is kept
+	for _, entries := range [][]SessionEntry{resp.Tracks, resp.Videos} {
+		for _, e := range entries {
+			queue.Items = append(queue.Items, PlayQueueItem{
+				ItemID:    e.PlayQueueItemID,
+				RatingKey: e.RatingKey,
+				Type:      e.Type,
+				Title:     e.Title,
+				Artist:    e.GrandparentTitle,
+				Album:     e.ParentTitle,
+				Duration:  e.Duration,
+				ThumbPath: e.Thumb,
+			})
+		}
+	}
+	return queue, nil
+}
+
+// cachedPlayQueue is a fetched queue and what it was fetched for.
+type cachedPlayQueue struct {
+	queue     *PlayQueue // Nil when the fetch failed
+	itemID    string     // Item playing when it was fetched
+	fetchedAt time.Time
+}
+
+// PlayQueueResolver attaches the upcoming items of a session's play queue.
+// A queue is fetched again when its session moves to another item, and
+// otherwise at most every playQueueRefreshAfter, so polling costs about one
+// request per item. Safe for concurrent use.
+type PlayQueueResolver struct {
+	client *Client
+
+	mu     sync.Mutex
+	queues map[string]cachedPlayQueue // Play queue ID → last fetch
+}
+
+// NewPlayQueueResolver creates a resolver fetching from client's server.
+func NewPlayQueueResolver(client *Client) *PlayQueueResolver {
+	return &PlayQueueResolver{
+		client: client,
+		queues: make(map[string]cachedPlayQueue),
+	}
+}
+
+// ResolveMedia sets session.UpNext. No-op for nil sessions and sessions
+// without a play queue.
+func (r *PlayQueueResolver) ResolveMedia(session *MediaSession) {
+	if session == nil {
+		return
+	}
+	session.UpNext = r.upNext(session.PlayQueueID, session.PlayQueueItemID)
+}
+
+// ResolveMusic is ResolveMedia for music-only polling.
+func (r *PlayQueueResolver) ResolveMusic(session *MusicSession) {
+	if session == nil {
+		return
+	}
+	session.UpNext = r.upNext(session.PlayQueueID, session.PlayQueueItemID)
+}
+
+// upNext returns the items after itemID in the queue, fetching the queue if
+// it isn't cached for that item.
+func (r *PlayQueueResolver) upNext(queueID, itemID string) []PlayQueueItem {
+	if queueID == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	cached, ok := r.queues[queueID]
+	r.mu.Unlock()
+	if !ok || cached.itemID != itemID || time.Since(cached.fetchedAt) >= playQueueRefreshAfter {
+		queue, err := r.client.GetPlayQueue(queueID)
+		if err != nil {
+			// Remembered too, so a failing queue isn't asked on every poll
+			log.Printf("Play queue lookup for %s failed: %v", queueID, err)
+		}
+		cached = cachedPlayQueue{queue: queue, itemID: itemID, fetchedAt: time.Now()}
+
+		r.mu.Lock()
+		if len(r.queues) >= playQueueCacheSize {
+			r.queues = make(map[string]cachedPlayQueue)
+		}
+		r.queues[queueID] = cached
+		r.mu.Unlock()
+	}
+
+	if cached.queue == nil {
+		return nil
+	}
+	return cached.queue.After(itemID, upNextLimit)
+}
@@ -0,0 +1,86 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const playQueueXML = `<MediaContainer size="4" playQueueID="3120" playQueueSelectedItemID="12" playQueueTotalCount="4">
+  <Track playQueueItemID="11" ratingKey="101" type="track" title="Women" grandparentTitle="Def Leppard" parentTitle="Hysteria"/>
+  <Track playQueueItemID="12" ratingKey="102" type="track" title="Rocket" grandparentTitle="Def Leppard" parentTitle="Hysteria"/>
+  <Track playQueueItemID="13" ratingKey="103" type="track" title="Animal" grandparentTitle="Def Leppard" parentTitle="Hysteria" duration="244000"/>
+  <Track playQueueItemID="14" ratingKey="104" type="track" title="Love Bites" grandparentTitle="Def Leppard" parentTitle="Hysteria"/>
+</MediaContainer>`
+
+func TestParsePlayQueue(t *testing.T) {
+	queue, err := parsePlayQueue([]byte(playQueueXML))
+	if err != nil {
+		t.Fatalf("parsePlayQueue() error = %v", err)
+	}
+	if queue.ID != "3120" || queue.SelectedItemID != "12" || queue.TotalCount != 4 || len(queue.Items) != 4 {
+		t.Fatalf("unexpected queue: %+v", queue)
+	}
+
+	next := queue.After("", 1)
+	if len(next) != 1 || next[0].Title != "Animal" || next[0].Artist != "Def Leppard" || next[0].Duration != 244000 {
+		t.Errorf("expected Animal after the selected item, got %+v", next)
+	}
+	if got := queue.After("12", 5); len(got) != 2 {
+		t.Errorf("expected the 2 remaining items, got %d", len(got))
+	}
+	if got := queue.After("14", 5); got != nil {
+		t.Errorf("expected nothing after the last item, got %+v", got)
+	}
+	if got := queue.After("99", 5); got != nil {
+		t.Errorf("expected nothing for an unknown item, got %+v", got)
+	}
+
+	if _, err := parsePlayQueue([]byte(`not xml`)); err == nil {
+		t.Error("expected error for invalid XML")
+	}
+}
+
+func TestPlayQueueResolverCachesPerItem(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/playQueues/3120" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(playQueueXML))
+	}))
+	defer server.Close()
+
+	resolver := NewPlayQueueResolver(NewClient("token", server.URL))
+	session := &MediaSession{PlayQueueID: "3120", PlayQueueItemID: "12"}
+	resolver.ResolveMedia(session)
+	if len(session.UpNext) != 2 || session.UpNext[0].RatingKey != "103" {
+		t.Fatalf("unexpected up next: %+v", session.UpNext)
+	}
+
+	// Polling the same item is served from the cache
+	resolver.ResolveMedia(session)
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request while the item plays, got %d", n)
+	}
+
+	// Moving on refetches the queue
+	music := &MusicSession{PlayQueueID: "3120", PlayQueueItemID: "13"}
+	resolver.ResolveMusic(music)
+	if len(music.UpNext) != 1 || music.UpNext[0].Title != "Love Bites" {
+		t.Errorf("unexpected up next: %+v", music.UpNext)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected a refetch for the next item, got %d requests", n)
+	}
+
+	// Sessions without a queue cost nothing
+	none := &MediaSession{}
+	resolver.ResolveMedia(none)
+	if none.UpNext != nil || requests.Load() != 2 {
+		t.Error("expected no lookup without a play queue")
+	}
+}
@@ -14,9 +14,13 @@ import (
 // Type parameter T is the session type (*MusicSession or *MediaSession).
 // The nil-check of the session goes through fetch's second return value
 // (true = valid result including "no session"; false = error, skip update).
+//
+// wake triggers an out-of-band fetch (e.g. from the notifications socket);
+// a nil channel disables it and the loop is driven by the ticker alone.
 func runPollLoop[T any](
 	ctx context.Context,
 	stopCh <-chan struct{},
+	wake <-chan struct{},
 	getInterval func() time.Duration,
 	fetch func() (T, bool),
 	changed func(prev, curr T) bool,
@@ -30,6 +34,33 @@ func runPollLoop[T any](
 	var lastSession T
 	var isZero = true
 
+	// Refresh ticker if interval changed at runtime
+	refreshInterval := func() {
+		newInterval := getInterval()
+		if newInterval != interval {
+			ticker.Reset(newInterval)
+			interval = newInterval
+			log.Printf("%s poller interval changed to %v", label, interval)
+		}
+	}
+
+	poll := func() {
+		session, ok := fetch()
+		// Only emit if poll succeeded and session state changed. On error,
+		// emit nothing (no false "stopped" event) but forget lastSession:
+		// the error callback may have withdrawn the session downstream, so
+		// the first successful poll re-emits it even if unchanged.
+		if !ok {
+			isZero = true
+			return
+		}
+		if isZero || changed(lastSession, session) {
+			emit(session)
+			lastSession = session
+			isZero = false
+		}
+	}
+
 	// Perform immediate first poll
 	if session, ok := fetch(); ok {
 		emit(session)
@@ -46,25 +77,17 @@ func runPollLoop[T any](
 			log.Printf("%s poller stopped: stop signal received", label)
 			return
 		case <-ticker.C:
-			// Refresh ticker if interval changed at runtime
-			newInterval := getInterval()
-			if newInterval != interval {
-				ticker.Reset(newInterval)
-				interval = newInterval
-				log.Printf("%s poller interval changed to %v", label, interval)
-			}
-
-			session, ok := fetch()
-			// Only emit if poll succeeded and session state changed.
-			// On error, keep lastSession unchanged to avoid false "stopped" events.
-			if !ok {
-				continue
-			}
-			if isZero || changed(lastSession, session) {
-				emit(session)
-				lastSession = session
-				isZero = false
-			}
+			refreshInterval()
+			poll()
+			// The poll itself may change the interval (adaptive polling), so
+			// re-evaluate it for the next tick.
+			refreshInterval()
+		case <-wake:
+			// The interval may depend on the wake source's state (socket up
+			// vs. down), so re-evaluate it before fetching.
+			refreshInterval()
+			poll()
+			refreshInterval()
 		}
 	}
 }
@@ -7,6 +7,15 @@ import (
 	"time"
 )
 
+const (
+	// adaptiveIdleGrace is how many empty polls adaptive polling waits before
+	// backing off, so the gap between two tracks doesn't slow it down.
+	adaptiveIdleGrace = 3
+
+	// DefaultAdaptiveCeiling is the slowest adaptive polling gets when idle.
+	DefaultAdaptiveCeiling = 30 * time.Second
+)
+
 // Poller manages periodic polling of Plex sessions for a specific user.
 // It uses time.Ticker for accurate interval-based polling (not busy-waiting)
 // to meet CPU efficiency requirements (NFR3: <1% average CPU).
@@ -15,9 +24,17 @@ import (
 //   - Music-only mode (default): Uses Start() and emits *MusicSession on the channel.
 //   - Multi-media mode: Uses StartMedia() and emits *MediaSession on the media channel.
 //     Enabled by setting MediaTypes before calling StartMedia().
+//
+// Either mode can be made event-driven with SetNotificationSource: while the
+// server's notifications socket is open, fetches are triggered by playback
+// notifications and the interval ticker only resyncs occasionally.
 type Poller struct {
 	lastErrorTime time.Time // Track when last error occurred
 	client        *Client
+	notifications *NotificationSource // Optional event-driven wake source (nil = pure polling)
+	enricher      *MetadataEnricher   // Optional metadata enrichment (nil = sessions as reported)
+	classifier    *LibraryClassifier  // Optional audiobook/podcast classification (media mode)
+	queues        *PlayQueueResolver  // Optional up-next resolution
 	stopCh        chan struct{}
 	sessionC      chan *MusicSession // nil indicates no session / stopped playback (music mode)
 	mediaC        chan *MediaSession // nil indicates no session / stopped playback (media mode)
@@ -28,7 +45,18 @@ type Poller struct {
 
 	userID     string
 	interval   time.Duration
-	mediaTypes []string // Media types to poll for (e.g., ["music", "movie", "tv"]). Empty = music only.
+	mediaTypes []string        // Media types to report in media mode (e.g., ["music", "movie", "tv"]). Empty = all.
+	policy     SelectionPolicy // Which of the user's concurrent sessions is emitted
+	clock      sessionClock    // When each session started, for PreferRecent
+
+	seekTolerance time.Duration // Position jump that counts as a seek
+
+	// Adaptive polling: after adaptiveIdleGrace empty polls the interval
+	// doubles with each further one, up to ceiling; any session resets it.
+	adaptive  bool
+	ceiling   time.Duration
+	idlePolls int            // Consecutive successful polls that found no session
+	sessions  []MediaSession // Every matching session of the last successful media poll
 
 	// Synchronization
 	mu           sync.RWMutex
@@ -54,14 +82,19 @@ func NewPoller(client *Client, userID string, interval time.Duration) *Poller {
 		userID:   userID,
 		interval: interval,
 		stopCh:   make(chan struct{}),
-		sessionC: make(chan *MusicSession, 1), // Buffered to prevent blocking
-		mediaC:   make(chan *MediaSession, 1), // Buffered to prevent blocking
+
+		seekTolerance: DefaultSeekTolerance,
+		sessionC:      make(chan *MusicSession, 1), // Buffered to prevent blocking
+		mediaC:        make(chan *MediaSession, 1), // Buffered to prevent blocking
 	}
 }
 
-// SetMediaTypes sets the media types that the poller should monitor.
-// Valid types: "music", "movie", "tv", "photo".
-// An empty or nil slice defaults to music-only polling (backward compatible).
+// SetMediaTypes sets the media types that the poller should report in media
+// mode (see the MediaType constants: music, movie, tv, photo, clip, live,
+// audiobook and podcast). An empty or nil slice reports every type.
+// With a library classifier every session is fetched and the filter applies
+// once audiobooks and podcasts are told apart from music and TV; without
+// one, only the requested types are fetched.
 // Must be called before StartMedia().
 func (p *Poller) SetMediaTypes(types []string) {
 	p.mu.Lock()
@@ -69,6 +102,70 @@ func (p *Poller) SetMediaTypes(types []string) {
 	p.mediaTypes = types
 }
 
+// SetNotificationSource makes the poller event-driven: while the source's
+// socket is connected, sessions are fetched as soon as a playback notification
+// arrives and the regular interval is relaxed to a slow resync. When the
+// socket drops the poller falls back to its configured interval.
+// Must be called before Start()/StartMedia().
+func (p *Poller) SetNotificationSource(source *NotificationSource) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notifications = source
+}
+
+// SetMetadataEnricher attaches full library metadata (genres, GUIDs, ...) to
+// every emitted session. Lookups are cached, so only a new item costs a
+// request. Must be called before Start()/StartMedia().
+func (p *Poller) SetMetadataEnricher(enricher *MetadataEnricher) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enricher = enricher
+}
+
+// SetLibraryClassifier classifies sessions from audiobook and podcast
+// libraries as such before they're filtered by media type. Only applies to
+// StartMedia(). Must be called before StartMedia().
+func (p *Poller) SetLibraryClassifier(classifier *LibraryClassifier) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.classifier = classifier
+}
+
+// SetPlayQueueResolver attaches the upcoming items of the emitted session's
+// play queue. The queue ID is taken from the notifications socket, so
+// without a notification source only sessions that report it are resolved.
+// Must be called before Start()/StartMedia().
+func (p *Poller) SetPlayQueueResolver(resolver *PlayQueueResolver) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queues = resolver
+}
+
+// SetSelectionPolicy sets how the emitted session is chosen when the user
+// has several. Takes effect on the next poll.
+func (p *Poller) SetSelectionPolicy(policy SelectionPolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.policy = policy
+}
+
+// Sessions returns every session of the user found by the last successful
+// media poll, the emitted one included. Empty in music-only mode.
+func (p *Poller) Sessions() []MediaSession {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]MediaSession(nil), p.sessions...)
+}
+
+// UsesNotifications reports whether the poller is currently driven by the
+// notifications socket rather than its interval.
+func (p *Poller) UsesNotifications() bool {
+	p.mu.RLock()
+	source := p.notifications
+	p.mu.RUnlock()
+	return source != nil && source.Connected()
+}
+
 // GetMediaTypes returns the currently configured media types.
 func (p *Poller) GetMediaTypes() []string {
 	p.mu.RLock()
@@ -153,11 +250,87 @@ func (p *Poller) SetInterval(interval time.Duration) {
 	p.mu.Unlock()
 }
 
-// GetInterval returns the current polling interval.
+// SetSeekTolerance sets how far the reported position may stray from the
+// one extrapolated from wall-clock time before an update is emitted as a
+// seek. Smaller drift is ignored, so the progress bar isn't re-anchored on
+// every poll. Non-positive values restore DefaultSeekTolerance.
+func (p *Poller) SetSeekTolerance(tolerance time.Duration) {
+	if tolerance <= 0 {
+		tolerance = DefaultSeekTolerance
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seekTolerance = tolerance
+}
+
+// SetAdaptive enables or disables adaptive polling. While enabled the
+// configured interval is used during playback, and once nothing has played
+// for a few polls the interval backs off step by step up to ceiling (clamped
+// to the configured interval and 60s). Polling snaps back to the configured
+// interval as soon as a session shows up. Takes effect on the next poll.
+func (p *Poller) SetAdaptive(enabled bool, ceiling time.Duration) {
+	if ceiling > 60*time.Second {
+		ceiling = 60 * time.Second
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.adaptive = enabled
+	p.ceiling = ceiling
+}
+
+// GetInterval returns the effective polling interval: the configured one, or
+// the backed-off one while adaptive polling is idle.
 func (p *Poller) GetInterval() time.Duration {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	return p.interval
+
+	steps := p.idlePolls - adaptiveIdleGrace
+	if !p.adaptive || steps <= 0 || p.ceiling <= p.interval {
+		return p.interval
+	}
+	interval := p.interval
+	for ; steps > 0 && interval < p.ceiling; steps-- {
+		interval *= 2
+	}
+	if interval > p.ceiling {
+		interval = p.ceiling
+	}
+	return interval
+}
+
+// recordActivity feeds a successful poll's outcome to adaptive polling.
+func (p *Poller) recordActivity(active bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if active {
+		p.idlePolls = 0
+	} else if p.idlePolls <= adaptiveIdleGrace+8 { // Enough steps to reach any ceiling
+		p.idlePolls++
+	}
+}
+
+// pollInterval returns the interval the loop should tick at right now: the
+// configured interval, or the slow resync interval while notifications drive
+// the updates.
+func (p *Poller) pollInterval() time.Duration {
+	if p.UsesNotifications() {
+		return notificationResyncInterval
+	}
+	return p.GetInterval()
+}
+
+// startNotifications launches the notification source (if any) for this run
+// and returns its wake channel. A nil channel leaves the loop ticker-driven.
+func (p *Poller) startNotifications(ctx context.Context, stopCh <-chan struct{}) <-chan struct{} {
+	p.mu.RLock()
+	source := p.notifications
+	p.mu.RUnlock()
+	if source == nil {
+		return nil
+	}
+	go source.run(ctx, stopCh)
+	return source.Wake()
 }
 
 // IsRunning returns whether the poller is currently running.
@@ -211,9 +384,12 @@ func (p *Poller) pollLoop(ctx context.Context) {
 	runPollLoop[*MusicSession](
 		ctx,
 		stopCh,
-		p.GetInterval,
+		p.startNotifications(ctx, stopCh),
+		p.pollInterval,
 		p.doPoll,
-		sessionChanged,
+		func(prev, curr *MusicSession) bool {
+			return sessionChanged(prev, curr) || musicSessionSeeked(prev, curr, p.getSeekTolerance())
+		},
 		func(session *MusicSession) {
 			select {
 			case sessionC <- session:
@@ -225,12 +401,20 @@ func (p *Poller) pollLoop(ctx context.Context) {
 	)
 }
 
+// getSeekTolerance returns the configured seek tolerance.
+func (p *Poller) getSeekTolerance() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.seekTolerance
+}
+
 // doPoll performs a single poll for music sessions.
 // Returns the current music session, or nil if no music is playing.
 // The second return value indicates whether the result is valid (not an error).
 // Also handles error state transitions and callbacks (Story 6.5).
 func (p *Poller) doPoll() (*MusicSession, bool) {
 	sessions, err := p.client.GetMusicSessions(p.userID)
+	observedAt := time.Now()
 	if err != nil {
 		// Log error but continue polling (AC4: failed polls continue polling)
 		log.Printf("Poll error: %v", err)
@@ -263,12 +447,28 @@ func (p *Poller) doPoll() (*MusicSession, bool) {
 		onRecovered()
 	}
 
+	p.recordActivity(len(sessions) > 0)
 	if len(sessions) == 0 {
 		return nil, true
 	}
 
-	// Return the first (most recent) music session
-	return &sessions[0], true
+	p.mu.RLock()
+	policy := p.policy
+	p.mu.RUnlock()
+
+	p.clock.stampMusic(sessions, observedAt)
+	session := &sessions[policy.SelectMusic(sessions)]
+	session.ObservedAt = observedAt
+	if enricher := p.metadataEnricher(); enricher != nil {
+		enricher.EnrichMusic(session)
+	}
+	if resolver, source := p.playQueueResolver(); resolver != nil {
+		if session.PlayQueueID == "" && source != nil {
+			session.PlayQueueID = source.PlayQueueID(session.SessionKey)
+		}
+		resolver.ResolveMusic(session)
+	}
+	return session, true
 }
 
 // mediaPollLoop is the main polling goroutine for multi-media mode.
@@ -295,9 +495,12 @@ func (p *Poller) mediaPollLoop(ctx context.Context) {
 	runPollLoop[*MediaSession](
 		ctx,
 		stopCh,
-		p.GetInterval,
+		p.startNotifications(ctx, stopCh),
+		p.pollInterval,
 		p.doMediaPoll,
-		mediaSessionChanged,
+		func(prev, curr *MediaSession) bool {
+			return mediaSessionChanged(prev, curr) || mediaSessionSeeked(prev, curr, p.getSeekTolerance())
+		},
 		func(session *MediaSession) {
 			select {
 			case mediaC <- session:
@@ -315,9 +518,18 @@ func (p *Poller) mediaPollLoop(ctx context.Context) {
 func (p *Poller) doMediaPoll() (*MediaSession, bool) {
 	p.mu.RLock()
 	mediaTypes := p.mediaTypes
+	classifier := p.classifier
+	policy := p.policy
 	p.mu.RUnlock()
 
-	sessions, err := p.client.GetMediaSessions(p.userID, mediaTypes)
+	// A track may turn out to be an audiobook and an episode a podcast, so
+	// with a classifier every type is fetched and filtered once classified.
+	fetchTypes := mediaTypes
+	if classifier != nil {
+		fetchTypes = nil
+	}
+	sessions, err := p.client.GetMediaSessions(p.userID, fetchTypes)
+	observedAt := time.Now()
 	if err != nil {
 		log.Printf("Media poll error: %v", err)
 
@@ -348,12 +560,47 @@ func (p *Poller) doMediaPoll() (*MediaSession, bool) {
 		onRecovered()
 	}
 
+	if classifier != nil {
+		sessions = classifier.Filter(sessions, mediaTypes)
+	}
+	p.clock.stampMedia(sessions, observedAt)
+
+	p.mu.Lock()
+	p.sessions = append([]MediaSession(nil), sessions...)
+	p.mu.Unlock()
+	p.recordActivity(len(sessions) > 0)
+
 	if len(sessions) == 0 {
 		return nil, true
 	}
 
-	// Return the first (most recent) session
-	return &sessions[0], true
+	session := &sessions[policy.SelectMedia(sessions)]
+	session.ObservedAt = observedAt
+	if enricher := p.metadataEnricher(); enricher != nil {
+		enricher.EnrichMedia(session)
+	}
+	if resolver, source := p.playQueueResolver(); resolver != nil {
+		if session.PlayQueueID == "" && source != nil {
+			session.PlayQueueID = source.PlayQueueID(session.SessionKey)
+		}
+		resolver.ResolveMedia(session)
+	}
+	return session, true
+}
+
+// metadataEnricher returns the configured enricher, if any.
+func (p *Poller) metadataEnricher() *MetadataEnricher {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.enricher
+}
+
+// playQueueResolver returns the configured resolver, if any, and the
+// notification source that knows the sessions' queue IDs.
+func (p *Poller) playQueueResolver() (*PlayQueueResolver, *NotificationSource) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.queues, p.notifications
 }
 
 // mediaSessionChanged determines if the media session state has meaningfully changed.
@@ -400,6 +647,26 @@ func mediaSessionChanged(prev, curr *MediaSession) bool {
 		return true
 	}
 
+	// Transcoding started or stopped
+	if prev.Quality.Decision != curr.Quality.Decision {
+		return true
+	}
+
+	// Rated from PlexCord or another app
+	if prev.UserRating != curr.UserRating {
+		return true
+	}
+
+	// Queue edited: something else plays next
+	if nextRatingKey(prev.UpNext) != nextRatingKey(curr.UpNext) {
+		return true
+	}
+
+	// Live TV channel switch
+	if prev.ChannelName != curr.ChannelName || prev.ChannelNumber != curr.ChannelNumber {
+		return true
+	}
+
 	if prev.Season != curr.Season {
 		return true
 	}
@@ -411,6 +678,14 @@ func mediaSessionChanged(prev, curr *MediaSession) bool {
 	return false
 }
 
+// nextRatingKey returns the rating key of the first upcoming item, or "".
+func nextRatingKey(upNext []PlayQueueItem) string {
+	if len(upNext) == 0 {
+		return ""
+	}
+	return upNext[0].RatingKey
+}
+
 // sessionChanged determines if the session state has meaningfully changed.
 // Used to avoid emitting duplicate updates.
 func sessionChanged(prev, curr *MusicSession) bool {
@@ -446,6 +721,10 @@ func sessionChanged(prev, curr *MusicSession) bool {
 		return true
 	}
 
+	if nextRatingKey(prev.UpNext) != nextRatingKey(curr.UpNext) {
+		return true
+	}
+
 	// ViewOffset changes are expected during playback, don't emit for every update
 	// Only emit if track, state, or metadata changed
 
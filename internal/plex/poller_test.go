@@ -973,3 +973,55 @@ func TestPollerNilOnNoSession(t *testing.T) {
 		t.Error("Timeout waiting for nil session")
 	}
 }
+
+func TestPollerAdaptiveIntervalBacksOffAndSnapsBack(t *testing.T) {
+	var playing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		if !playing.Load() {
+			_, _ = w.Write([]byte(`<MediaContainer size="0"/>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<MediaContainer size="1">
+  <Track sessionKey="1" type="track" title="Song">
+    <User id="user1" title="User1"/>
+    <Player state="playing" title="Plexamp"/>
+  </Track>
+</MediaContainer>`))
+	}))
+	defer server.Close()
+
+	poller := NewPoller(NewClient("token", server.URL), "user1", 2*time.Second)
+	poller.SetMediaTypes([]string{MediaTypeMusic})
+	poller.SetAdaptive(true, 10*time.Second)
+
+	for i := 0; i < adaptiveIdleGrace; i++ {
+		poller.doMediaPoll()
+	}
+	if got := poller.GetInterval(); got != 2*time.Second {
+		t.Errorf("expected the fast interval during the idle grace, got %v", got)
+	}
+
+	want := []time.Duration{4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for _, w := range want {
+		poller.doMediaPoll()
+		if got := poller.GetInterval(); got != w {
+			t.Errorf("expected backed-off interval %v, got %v", w, got)
+		}
+	}
+
+	playing.Store(true)
+	poller.doMediaPoll()
+	if got := poller.GetInterval(); got != 2*time.Second {
+		t.Errorf("expected the fast interval once a session appears, got %v", got)
+	}
+
+	poller.SetAdaptive(false, 10*time.Second)
+	playing.Store(false)
+	for i := 0; i < adaptiveIdleGrace+3; i++ {
+		poller.doMediaPoll()
+	}
+	if got := poller.GetInterval(); got != 2*time.Second {
+		t.Errorf("expected the configured interval with adaptive polling off, got %v", got)
+	}
+}
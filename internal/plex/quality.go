@@ -0,0 +1,142 @@
+package plex
+
+import "strings"
+
+// Delivery decisions for StreamQuality.Decision, as Plex players label them.
+const (
+	DecisionDirectPlay   = "Direct Play"   // The player reads the file as is
+	DecisionDirectStream = "Direct Stream" // Remuxed, streams copied
+	DecisionTranscode    = "Transcode"     // Re-encoded by the server
+)
+
+// Values of SessionStream.StreamType.
+const (
+	streamTypeVideo  = 1
+	streamTypeAudio  = 2
+	streamTypeLyrics = 4
+)
+
+// SessionPart is a file of a media version, with its streams.
+type SessionPart struct {
+	Decision string          `xml:"decision,attr"` // "directplay", "transcode", ... (sessions only)
+	Streams  []SessionStream `xml:"Stream"`
+}
+
+// SessionStream is an audio, video, subtitle or lyrics stream of a part.
+type SessionStream struct {
+	StreamType   int    `xml:"streamType,attr"` // 1 video, 2 audio, 3 subtitle, 4 lyrics
+	Codec        string `xml:"codec,attr"`
+	Bitrate      int    `xml:"bitrate,attr"`      // kbps
+	BitDepth     int    `xml:"bitDepth,attr"`     // Bits per sample
+	SamplingRate int    `xml:"samplingRate,attr"` // Hz
+	Selected     string `xml:"selected,attr"`     // "1" for the stream being played
+	Key          string `xml:"key,attr"`          // Download path of lyrics, e.g. "/library/streams/123"
+}
+
+// TranscodeSession describes an ongoing transcode of a session's stream.
+type TranscodeSession struct {
+	VideoDecision string `xml:"videoDecision,attr"` // "transcode", "copy" or "directplay"
+	AudioDecision string `xml:"audioDecision,attr"`
+	AudioCodec    string `xml:"audioCodec,attr"` // Codec sent to the player
+	VideoCodec    string `xml:"videoCodec,attr"`
+}
+
+// StreamQuality is the format of a playing item and how it reaches the
+// player. Zero fields are unknown: items looked up outside /status/sessions
+// (Companion) carry the source format but no decision.
+type StreamQuality struct {
+	Codec      string `json:"codec,omitempty"`      // Audio codec for audio items, video codec for video
+	Bitrate    int    `json:"bitrate,omitempty"`    // kbps
+	BitDepth   int    `json:"bitDepth,omitempty"`   // Audio bits per sample
+	SampleRate int    `json:"sampleRate,omitempty"` // Audio sample rate in Hz
+	Resolution string `json:"resolution,omitempty"` // Video resolution as Plex reports it ("1080", "4k", "sd")
+	Lossless   bool   `json:"lossless,omitempty"`   // Lossless audio codec
+	Decision   string `json:"decision,omitempty"`   // DecisionDirectPlay, DecisionDirectStream or DecisionTranscode
+}
+
+// losslessCodecs are the audio codecs that don't discard information.
+var losslessCodecs = map[string]bool{
+	"flac": true, "alac": true, "wav": true, "pcm": true, "aiff": true,
+	"ape": true, "wavpack": true, "dsd": true, "truehd": true,
+}
+
+// quality derives the stream quality from the entry's first media version,
+// its selected streams, and the transcode session if any.
+func (e *SessionEntry) quality() StreamQuality {
+	var q StreamQuality
+	if len(e.Media) == 0 {
+		return q
+	}
+	media := e.Media[0]
+	video := media.VideoCodec != "" || media.VideoResolution != ""
+
+	q.Bitrate = media.Bitrate
+	q.Resolution = media.VideoResolution
+	if video {
+		q.Codec = media.VideoCodec
+	} else {
+		q.Codec = media.AudioCodec
+	}
+
+	var partDecision string
+	if len(media.Parts) > 0 {
+		part := media.Parts[0]
+		partDecision = part.Decision
+		if audio := selectedStream(part.Streams, streamTypeAudio); audio != nil {
+			q.BitDepth = audio.BitDepth
+			q.SampleRate = audio.SamplingRate
+			if !video && audio.Codec != "" {
+				q.Codec = audio.Codec
+			}
+		}
+	}
+
+	switch {
+	case e.Transcode != nil && (e.Transcode.VideoDecision == "transcode" || e.Transcode.AudioDecision == "transcode"):
+		q.Decision = DecisionTranscode
+	case e.Transcode != nil:
+		q.Decision = DecisionDirectStream
+	case partDecision == "directplay":
+		q.Decision = DecisionDirectPlay
+	case partDecision == "transcode":
+		q.Decision = DecisionTranscode
+	case partDecision != "":
+		q.Decision = DecisionDirectStream
+	}
+
+	// A transcode sends another format than the source's: report that one,
+	// whose depth and rate aren't known
+	if q.Decision == DecisionTranscode && e.Transcode != nil {
+		codec := e.Transcode.AudioCodec
+		if video {
+			codec = e.Transcode.VideoCodec
+		}
+		if codec != "" && !strings.EqualFold(codec, q.Codec) {
+			q.Codec = codec
+			q.BitDepth, q.SampleRate = 0, 0
+		}
+	}
+
+	q.Codec = strings.ToLower(q.Codec)
+	q.Lossless = !video && (losslessCodecs[q.Codec] || strings.HasPrefix(q.Codec, "pcm_") || strings.HasPrefix(q.Codec, "dsd_"))
+	return q
+}
+
+// selectedStream returns the selected stream of the given type, or the
+// first one when none is marked selected.
+func selectedStream(streams []SessionStream, streamType int) *SessionStream {
+	var first *SessionStream
+	for i := range streams {
+		s := &streams[i]
+		if s.StreamType != streamType {
+			continue
+		}
+		if s.Selected == "1" {
+			return s
+		}
+		if first == nil {
+			first = s
+		}
+	}
+	return first
+}
@@ -0,0 +1,80 @@
+package plex
+
+import "testing"
+
+func TestSessionQuality_LosslessDirectPlay(t *testing.T) {
+	resp, err := parseSessionsResponse([]byte(`<?xml version="1.0"?>
+<MediaContainer size="1">
+  <Track sessionKey="1" type="track" title="Song">
+    <Media audioCodec="flac" bitrate="4608" container="flac">
+      <Part decision="directplay">
+        <Stream streamType="2" codec="flac" bitDepth="24" samplingRate="96000" selected="1"/>
+      </Part>
+    </Media>
+    <User id="u" title="User"/>
+    <Player state="playing" title="Plexamp"/>
+  </Track>
+</MediaContainer>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := NewMediaSessionFromEntry(resp.Tracks[0], "").Quality
+	want := StreamQuality{Codec: "flac", Bitrate: 4608, BitDepth: 24, SampleRate: 96000, Lossless: true, Decision: DecisionDirectPlay}
+	if got != want {
+		t.Errorf("Quality = %+v, want %+v", got, want)
+	}
+}
+
+func TestSessionQuality_Transcode(t *testing.T) {
+	resp, err := parseSessionsResponse([]byte(`<?xml version="1.0"?>
+<MediaContainer size="2">
+  <Video sessionKey="1" type="movie" title="Heat">
+    <Media videoCodec="hevc" audioCodec="truehd" bitrate="40000" videoResolution="4k">
+      <Part decision="transcode">
+        <Stream streamType="1" codec="hevc"/>
+        <Stream streamType="2" codec="truehd" bitDepth="24" samplingRate="48000" selected="1"/>
+      </Part>
+    </Media>
+    <TranscodeSession videoDecision="transcode" audioDecision="transcode" videoCodec="h264" audioCodec="aac"/>
+    <User id="u" title="User"/>
+    <Player state="playing" title="TV"/>
+  </Video>
+  <Track sessionKey="2" type="track" title="Song">
+    <Media audioCodec="flac">
+      <Part decision="transcode">
+        <Stream streamType="2" codec="flac" bitDepth="16" samplingRate="44100"/>
+      </Part>
+    </Media>
+    <TranscodeSession audioDecision="transcode" audioCodec="mp3"/>
+    <User id="u" title="User"/>
+    <Player state="playing" title="Phone"/>
+  </Track>
+</MediaContainer>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	movie := NewMediaSessionFromEntry(resp.Videos[0], "").Quality
+	if movie.Codec != "h264" || movie.Resolution != "4k" || movie.Decision != DecisionTranscode || movie.Lossless {
+		t.Errorf("unexpected movie quality: %+v", movie)
+	}
+
+	track := NewMediaSessionFromEntry(resp.Tracks[0], "").Quality
+	if track.Codec != "mp3" || track.Lossless || track.BitDepth != 0 || track.Decision != DecisionTranscode {
+		t.Errorf("a transcoded track should report the sent format, got %+v", track)
+	}
+}
+
+func TestSessionQuality_DirectStream(t *testing.T) {
+	entry := SessionEntry{
+		Media:     []SessionMedia{{AudioCodec: "aac", Parts: []SessionPart{{Decision: "copy"}}}},
+		Transcode: &TranscodeSession{AudioDecision: "copy"},
+	}
+	if got := entry.quality().Decision; got != DecisionDirectStream {
+		t.Errorf("Decision = %q, want %q", got, DecisionDirectStream)
+	}
+	if got := (&SessionEntry{}).quality(); got != (StreamQuality{}) {
+		t.Errorf("expected an unknown quality without media, got %+v", got)
+	}
+}
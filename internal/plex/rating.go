@@ -0,0 +1,60 @@
+package plex
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"plexcord/internal/errors"
+)
+
+// libraryIdentifier is the identifier of the library plugin, which the
+// rating and play state endpoints expect alongside the item key.
+const libraryIdentifier = "com.plexapp.plugins.library"
+
+// MaxRating is the top of Plex's rating scale: ten points, shown as five
+// stars in half-star steps.
+const MaxRating = 10
+
+// Rate sets the user's rating of an item, from 0 to MaxRating. A negative
+// rating clears it.
+func (c *Client) Rate(ratingKey string, rating float64) error {
+	if rating > MaxRating {
+		return errors.New(errors.INVALID_INPUT, "rating must be at most 10")
+	}
+	if rating < 0 {
+		rating = -1 // What the server takes as "unrated"
+	}
+	query := url.Values{"rating": {strconv.FormatFloat(rating, 'f', -1, 64)}}
+	return c.libraryAction("PUT", "/:/rate", ratingKey, query)
+}
+
+// Scrobble marks an item as played.
+func (c *Client) Scrobble(ratingKey string) error {
+	return c.libraryAction("GET", "/:/scrobble", ratingKey, nil)
+}
+
+// Unscrobble marks an item as unplayed.
+func (c *Client) Unscrobble(ratingKey string) error {
+	return c.libraryAction("GET", "/:/unscrobble", ratingKey, nil)
+}
+
+// libraryAction calls one of the item state endpoints for ratingKey.
+func (c *Client) libraryAction(method, path, ratingKey string, query url.Values) error {
+	if ratingKey == "" {
+		return errors.New(errors.INVALID_INPUT, "rating key cannot be empty")
+	}
+	params := url.Values{"key": {ratingKey}, "identifier": {libraryIdentifier}}
+	for k, v := range query {
+		params[k] = v
+	}
+
+	return c.withFailover(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := c.transport().request(ctx, method, path, params, nil)
+		return err
+	})
+}
@@ -0,0 +1,57 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClientRateAndScrobble(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("key") != "42" || q.Get("identifier") != libraryIdentifier || q.Get("X-Plex-Token") != "token" {
+			t.Errorf("missing key, identifier or token: %s", r.URL.RawQuery)
+		}
+		call := r.Method + " " + r.URL.Path
+		if rating := q.Get("rating"); rating != "" {
+			call += " " + rating
+		}
+		mu.Lock()
+		got = append(got, call)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := NewClient("token", server.URL)
+	steps := []func() error{
+		func() error { return client.Rate("42", 8) },
+		func() error { return client.Rate("42", 0) },
+		func() error { return client.Rate("42", -3) },
+		func() error { return client.Scrobble("42") },
+		func() error { return client.Unscrobble("42") },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+
+	want := []string{"PUT /:/rate 8", "PUT /:/rate 0", "PUT /:/rate -1", "GET /:/scrobble", "GET /:/unscrobble"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+
+	if err := client.Rate("42", 11); err == nil {
+		t.Error("expected an error for a rating above 10")
+	}
+	if err := client.Scrobble(""); err == nil {
+		t.Error("expected an error without a rating key")
+	}
+	if len(got) != len(want) {
+		t.Errorf("invalid calls should not reach the server, got %v", got)
+	}
+}
@@ -0,0 +1,192 @@
+package plex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"plexcord/internal/errors"
+	"plexcord/internal/version"
+)
+
+const (
+	// plexResourcesURL lists every server and player the account can reach,
+	// including servers shared by friends, with their connection URIs.
+	plexResourcesURL = "https://plex.tv/api/v2/resources"
+
+	// resourcesClientID identifies PlexCord to plex.tv for resource listing.
+	// Unlike PIN auth, no per-session state is tied to it, so it is stable.
+	resourcesClientID = "plexcord-resources"
+)
+
+// ServerConnection is one way of reaching a server, as advertised by plex.tv.
+// A server typically advertises a LAN address, a public address and, when
+// Remote Access can't be reached directly, a relay through plex.tv.
+type ServerConnection struct {
+	URI      string `json:"uri"`      // Full base URL (e.g. https://10-0-0-5.abc.plex.direct:32400)
+	Protocol string `json:"protocol"` // "http" or "https"
+	Address  string `json:"address"`  // Host or IP address
+	Port     int    `json:"port"`     // Port
+	Local    bool   `json:"local"`    // Reachable on the server's LAN
+	Relay    bool   `json:"relay"`    // Proxied through plex.tv (bandwidth-limited)
+	IPv6     bool   `json:"IPv6"`     // Address is IPv6
+}
+
+// resourceEntry is a single item of the /api/v2/resources JSON response.
+type resourceEntry struct {
+	Name             string             `json:"name"`
+	Product          string             `json:"product"`
+	ProductVersion   string             `json:"productVersion"`
+	ClientIdentifier string             `json:"clientIdentifier"`
+	Provides         string             `json:"provides"` // Comma-separated: "server", "player", "client", ...
+	AccessToken      string             `json:"accessToken"`
+	SourceTitle      string             `json:"sourceTitle"` // Owner's username for shared servers
+	Connections      []ServerConnection `json:"connections"`
+	Owned            bool               `json:"owned"`
+	Presence         bool               `json:"presence"` // Recently seen online by plex.tv
+}
+
+// provides reports whether the resource advertises the given capability.
+func (r *resourceEntry) provides(capability string) bool {
+	for _, p := range strings.Split(r.Provides, ",") {
+		if strings.TrimSpace(p) == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// toServer converts a server resource into a Server. Address and Port come
+// from the preferred connection so Server.URL stays meaningful for callers
+// that don't look at Connections.
+func (r *resourceEntry) toServer() Server {
+	server := Server{
+		ID:          r.ClientIdentifier,
+		Name:        r.Name,
+		Version:     r.ProductVersion,
+		Connections: r.Connections,
+		AccessToken: r.AccessToken,
+		Owner:       r.SourceTitle,
+		Owned:       r.Owned,
+	}
+	if conn := server.preferredConnection(); conn != nil {
+		server.Address = conn.Address
+		server.Port = strconv.Itoa(conn.Port)
+		server.IsLocal = conn.Local
+	}
+	return server
+}
+
+// DiscoverRemoteServers lists the servers the account can access through the
+// plex.tv resources API, including remote and shared (friends') servers that
+// GDM cannot see. Each Server carries its connection list and the access
+// token to use for that server, which differs from the account token for
+// shared servers.
+func DiscoverRemoteServers(ctx context.Context, token string) ([]Server, error) {
+	return fetchResourceServers(ctx, &http.Client{Timeout: 10 * time.Second}, plexResourcesURL, token)
+}
+
+// fetchResourceServers performs the resources request against endpoint.
+// Split from DiscoverRemoteServers so tests can target an httptest server.
+func fetchResourceServers(ctx context.Context, httpClient *http.Client, endpoint, token string) ([]Server, error) {
+	resources, err := fetchResources(ctx, httpClient, endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := make([]Server, 0, len(resources))
+	for i := range resources {
+		if !resources[i].provides("server") {
+			continue
+		}
+		servers = append(servers, resources[i].toServer())
+	}
+	return servers, nil
+}
+
+// fetchResources requests and decodes the account's resource list.
+func fetchResources(ctx context.Context, httpClient *http.Client, endpoint, token string) ([]resourceEntry, error) {
+	if token == "" {
+		return nil, errors.New(errors.PLEX_AUTH_FAILED, "plex token is required for remote discovery")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?includeHttps=1&includeRelay=1", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create resources request")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Product", productName)
+	req.Header.Set("X-Plex-Version", version.Version)
+	req.Header.Set("X-Plex-Client-Identifier", resourcesClientID)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_UNREACHABLE, "failed to reach plex.tv")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Warning: Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, errors.New(errors.PLEX_AUTH_FAILED, "plex.tv rejected the token")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(errors.PLEX_CONN_FAILED,
+			fmt.Sprintf("resources request failed with status %d", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to read resources response")
+	}
+
+	var resources []resourceEntry
+	if err := json.Unmarshal(body, &resources); err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to parse resources response")
+	}
+	return resources, nil
+}
+
+// DiscoverPlayers lists the account's Plex players that can be reached
+// directly (they advertise a connection) and speak the Companion protocol,
+// for use with CompanionSource.
+func DiscoverPlayers(ctx context.Context, token string) ([]CompanionPlayer, error) {
+	return fetchResourcePlayers(ctx, &http.Client{Timeout: 10 * time.Second}, plexResourcesURL, token)
+}
+
+// fetchResourcePlayers performs the resources request against endpoint and
+// keeps the reachable players.
+func fetchResourcePlayers(ctx context.Context, httpClient *http.Client, endpoint, token string) ([]CompanionPlayer, error) {
+	resources, err := fetchResources(ctx, httpClient, endpoint, token)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]CompanionPlayer, 0, len(resources))
+	for i := range resources {
+		r := &resources[i]
+		if !r.provides("player") {
+			continue
+		}
+		server := r.toServer() // Reuse the connection preference order
+		uris := server.ConnectionURIs()
+		if len(uris) == 0 {
+			continue // Mobile players often can't be reached directly
+		}
+		players = append(players, CompanionPlayer{
+			ID:   r.ClientIdentifier,
+			Name: r.Name,
+			URI:  uris[0],
+		})
+	}
+	return players, nil
+}
@@ -0,0 +1,145 @@
+package plex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"plexcord/internal/errors"
+)
+
+const resourcesJSON = `[
+  {
+    "name": "Friend's Server",
+    "product": "Plex Media Server",
+    "productVersion": "1.40.0.7998",
+    "clientIdentifier": "abc123",
+    "provides": "server",
+    "owned": false,
+    "accessToken": "shared-token",
+    "sourceTitle": "friend",
+    "connections": [
+      {"protocol": "https", "address": "203.0.113.5", "port": 32400, "uri": "https://203-0-113-5.abc123.plex.direct:32400", "local": false, "relay": false},
+      {"protocol": "https", "address": "10.0.0.5", "port": 32400, "uri": "https://10-0-0-5.abc123.plex.direct:32400", "local": true, "relay": false},
+      {"protocol": "https", "address": "198.51.100.1", "port": 8443, "uri": "https://198-51-100-1.abc123.plex.direct:8443", "local": false, "relay": true}
+    ]
+  },
+  {
+    "name": "Living Room TV",
+    "product": "Plex for Android (TV)",
+    "clientIdentifier": "tv1",
+    "provides": "player,pubsub-player",
+    "owned": true,
+    "connections": []
+  }
+]`
+
+func TestFetchResourceServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != "account-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("X-Plex-Client-Identifier") == "" {
+			t.Error("expected X-Plex-Client-Identifier header")
+		}
+		if r.URL.Query().Get("includeRelay") != "1" || r.URL.Query().Get("includeHttps") != "1" {
+			t.Errorf("expected relay and https connections to be requested, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(resourcesJSON))
+	}))
+	defer server.Close()
+
+	servers, err := fetchResourceServers(context.Background(), server.Client(), server.URL, "account-token")
+	if err != nil {
+		t.Fatalf("fetchResourceServers() error = %v", err)
+	}
+	if len(servers) != 1 {
+		t.Fatalf("expected only the server resource, got %d", len(servers))
+	}
+
+	s := servers[0]
+	if s.ID != "abc123" || s.Name != "Friend's Server" || s.AccessToken != "shared-token" || s.Owned || s.Owner != "friend" {
+		t.Errorf("unexpected server: %+v", s)
+	}
+	if len(s.Connections) != 3 {
+		t.Fatalf("expected 3 connections, got %d", len(s.Connections))
+	}
+	// The LAN connection is preferred over remote and relay
+	if got := s.URL(); got != "https://10-0-0-5.abc123.plex.direct:32400" {
+		t.Errorf("URL() = %q, want local connection", got)
+	}
+	if s.Address != "10.0.0.5" || s.Port != "32400" || !s.IsLocal {
+		t.Errorf("expected address/port from preferred connection, got %s:%s local=%v", s.Address, s.Port, s.IsLocal)
+	}
+
+	uris := s.ConnectionURIs()
+	want := []string{
+		"https://10-0-0-5.abc123.plex.direct:32400",
+		"https://203-0-113-5.abc123.plex.direct:32400",
+		"https://198-51-100-1.abc123.plex.direct:8443",
+	}
+	if len(uris) != len(want) {
+		t.Fatalf("ConnectionURIs() = %v, want %v", uris, want)
+	}
+	for i := range want {
+		if uris[i] != want[i] {
+			t.Errorf("ConnectionURIs()[%d] = %q, want %q", i, uris[i], want[i])
+		}
+	}
+}
+
+func TestFetchResourceServersErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := fetchResourceServers(context.Background(), server.Client(), server.URL, "bad-token")
+	if code := errors.GetCode(err); code != errors.PLEX_AUTH_FAILED {
+		t.Errorf("expected %s for rejected token, got %v", errors.PLEX_AUTH_FAILED, err)
+	}
+
+	if _, err := fetchResourceServers(context.Background(), server.Client(), server.URL, ""); err == nil {
+		t.Error("expected error for empty token")
+	}
+}
+
+func TestServerURLWithoutConnections(t *testing.T) {
+	s := Server{Address: "192.168.1.10", Port: "32400"}
+	if got := s.URL(); got != "http://192.168.1.10:32400" {
+		t.Errorf("URL() = %q, want address/port URL", got)
+	}
+	if len(s.ConnectionURIs()) != 0 {
+		t.Error("expected no connection URIs for a GDM server")
+	}
+}
+
+func TestFetchResourcePlayers(t *testing.T) {
+	body := `[
+  {"name": "NAS", "clientIdentifier": "srv", "provides": "server",
+   "connections": [{"protocol": "http", "address": "10.0.0.2", "port": 32400, "uri": "http://10.0.0.2:32400", "local": true}]},
+  {"name": "Living Room TV", "clientIdentifier": "tv1", "provides": "player,pubsub-player",
+   "connections": [{"protocol": "http", "address": "10.0.0.9", "port": 32500, "uri": "http://10.0.0.9:32500", "local": true}]},
+  {"name": "Phone", "clientIdentifier": "phone1", "provides": "player", "connections": []}
+]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	players, err := fetchResourcePlayers(context.Background(), server.Client(), server.URL, "account-token")
+	if err != nil {
+		t.Fatalf("fetchResourcePlayers() error = %v", err)
+	}
+	// The server and the unreachable phone are skipped
+	if len(players) != 1 {
+		t.Fatalf("expected 1 reachable player, got %d: %+v", len(players), players)
+	}
+	if p := players[0]; p.ID != "tv1" || p.Name != "Living Room TV" || p.URI != "http://10.0.0.9:32500" {
+		t.Errorf("unexpected player: %+v", p)
+	}
+}
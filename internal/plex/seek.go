@@ -0,0 +1,65 @@
+package plex
+
+import "time"
+
+// DefaultSeekTolerance is how far a session's reported position may stray
+// from the one extrapolated from wall-clock time before it counts as a seek.
+// Players report their position every few seconds, so anything tighter would
+// flag their reporting lag as seeks.
+const DefaultSeekTolerance = 5 * time.Second
+
+// playbackPosition is a reported position and when it was observed.
+type playbackPosition struct {
+	offset   int64 // Milliseconds
+	duration int64 // Milliseconds, 0 when unknown
+	at       time.Time
+	playing  bool
+}
+
+// seekedFrom reports whether curr is too far from where prev's position
+// would have advanced to by curr's observation time. Only continuous playback
+// is compared: a paused session has no progress bar to re-anchor, and the
+// state change on resume re-anchors it anyway.
+func (curr playbackPosition) seekedFrom(prev playbackPosition, tolerance time.Duration) bool {
+	if !prev.playing || !curr.playing || prev.at.IsZero() || curr.at.IsZero() {
+		return false
+	}
+	expected := prev.offset + curr.at.Sub(prev.at).Milliseconds()
+	if prev.duration > 0 && expected > prev.duration {
+		expected = prev.duration
+	}
+	drift := curr.offset - expected
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift > tolerance.Milliseconds()
+}
+
+// position returns the session's playback position for seek detection.
+func (m *MediaSession) position() playbackPosition {
+	return playbackPosition{offset: m.ViewOffset, duration: m.Duration, at: m.ObservedAt, playing: m.State == "playing"}
+}
+
+// position returns the session's playback position for seek detection.
+func (m *MusicSession) position() playbackPosition {
+	return playbackPosition{offset: m.ViewOffset, duration: m.Duration, at: m.ObservedAt, playing: m.State == "playing"}
+}
+
+// mediaSessionSeeked reports whether curr is a seek within prev's item and
+// marks it as such. Call only when mediaSessionChanged found no change.
+func mediaSessionSeeked(prev, curr *MediaSession, tolerance time.Duration) bool {
+	if prev == nil || curr == nil || !curr.position().seekedFrom(prev.position(), tolerance) {
+		return false
+	}
+	curr.Seeked = true
+	return true
+}
+
+// musicSessionSeeked is mediaSessionSeeked for music-only polling.
+func musicSessionSeeked(prev, curr *MusicSession, tolerance time.Duration) bool {
+	if prev == nil || curr == nil || !curr.position().seekedFrom(prev.position(), tolerance) {
+		return false
+	}
+	curr.Seeked = true
+	return true
+}
@@ -0,0 +1,52 @@
+package plex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaSessionSeeked(t *testing.T) {
+	start := time.Unix(1000, 0)
+	prev := &MediaSession{SessionKey: "1", State: "playing", ViewOffset: 60_000, Duration: 240_000, ObservedAt: start}
+
+	tests := []struct {
+		name   string
+		state  string
+		offset int64
+		after  time.Duration
+		want   bool
+	}{
+		{"on schedule", "playing", 70_000, 10 * time.Second, false},
+		{"slow drift within tolerance", "playing", 66_000, 10 * time.Second, false},
+		{"seek forward", "playing", 150_000, 10 * time.Second, true},
+		{"seek backward", "playing", 5_000, 10 * time.Second, true},
+		{"paused sessions aren't compared", "paused", 150_000, 10 * time.Second, false},
+		{"extrapolation stops at the end", "playing", 240_000, 5 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			curr := &MediaSession{SessionKey: "1", State: tt.state, ViewOffset: tt.offset, Duration: 240_000, ObservedAt: start.Add(tt.after)}
+			if got := mediaSessionSeeked(prev, curr, DefaultSeekTolerance); got != tt.want {
+				t.Errorf("mediaSessionSeeked() = %v, want %v", got, tt.want)
+			}
+			if curr.Seeked != tt.want {
+				t.Errorf("Seeked = %v, want %v", curr.Seeked, tt.want)
+			}
+		})
+	}
+}
+
+func TestMusicSessionSeeked_NeedsObservationTimes(t *testing.T) {
+	prev := &MusicSession{Session: Session{State: "playing"}, ViewOffset: 0}
+	curr := &MusicSession{Session: Session{State: "playing"}, ViewOffset: 120_000}
+	if musicSessionSeeked(prev, curr, DefaultSeekTolerance) {
+		t.Error("sessions without observation times should never count as seeks")
+	}
+
+	prev.ObservedAt = time.Unix(1000, 0)
+	curr.ObservedAt = prev.ObservedAt.Add(2 * time.Second)
+	if !musicSessionSeeked(prev, curr, DefaultSeekTolerance) {
+		t.Error("expected a jump of two minutes in two seconds to be a seek")
+	}
+}
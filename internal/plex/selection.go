@@ -0,0 +1,165 @@
+package plex
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Media preference values for SelectionPolicy.PreferMedia.
+const (
+	PreferVideo = "video" // Movies, TV, clips and Live TV over audio
+	PreferAudio = "audio" // Music, audiobooks and podcasts over video
+)
+
+// SelectionPolicy chooses which of a user's concurrent sessions drives
+// presence, e.g. music on Plexamp while a movie plays on the TV.
+//
+// Criteria are applied in this order, each only breaking ties left by the
+// previous ones:
+//  1. PreferredPlayer: a session on the named player (case-insensitive).
+//  2. PreferPlaying: a playing session over a paused or buffering one.
+//  3. PreferMedia: video over audio or the reverse.
+//  4. PreferRecent: the session that started its current item last
+//     (StartedAt, as stamped by the session source).
+//
+// Remaining ties go to the session the server lists first. The zero value
+// keeps the server's order.
+type SelectionPolicy struct {
+	PreferredPlayer string
+	PreferPlaying   bool
+	PreferMedia     string // PreferVideo, PreferAudio or "" for no preference
+	PreferRecent    bool
+}
+
+// selectionCandidate is what the policy knows about a session.
+type selectionCandidate struct {
+	started   time.Time
+	mediaType string
+	state     string
+	player    string
+}
+
+// SelectMedia returns the index of the session to show, or -1 when there is
+// none.
+func (p SelectionPolicy) SelectMedia(sessions []MediaSession) int {
+	candidates := make([]selectionCandidate, len(sessions))
+	for i, s := range sessions {
+		candidates[i] = selectionCandidate{started: s.StartedAt, mediaType: s.MediaType, state: s.State, player: s.PlayerName}
+	}
+	return p.pick(candidates)
+}
+
+// SelectMusic is SelectMedia for music-only polling.
+func (p SelectionPolicy) SelectMusic(sessions []MusicSession) int {
+	candidates := make([]selectionCandidate, len(sessions))
+	for i, s := range sessions {
+		candidates[i] = selectionCandidate{started: s.StartedAt, mediaType: MediaTypeMusic, state: s.State, player: s.PlayerName}
+	}
+	return p.pick(candidates)
+}
+
+// pick returns the index of the best candidate, or -1 if there is none.
+func (p SelectionPolicy) pick(candidates []selectionCandidate) int {
+	best := -1
+	for i := range candidates {
+		if best == -1 || p.better(candidates[i], candidates[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// better reports whether a should be shown rather than b.
+func (p SelectionPolicy) better(a, b selectionCandidate) bool {
+	if p.PreferredPlayer != "" {
+		aMatch := strings.EqualFold(a.player, p.PreferredPlayer)
+		bMatch := strings.EqualFold(b.player, p.PreferredPlayer)
+		if aMatch != bMatch {
+			return aMatch
+		}
+	}
+	if p.PreferPlaying {
+		aPlaying, bPlaying := a.state == "playing", b.state == "playing"
+		if aPlaying != bPlaying {
+			return aPlaying
+		}
+	}
+	if p.PreferMedia != "" {
+		aRank, bRank := p.mediaRank(a.mediaType), p.mediaRank(b.mediaType)
+		if aRank != bRank {
+			return aRank > bRank
+		}
+	}
+	if p.PreferRecent && !a.started.Equal(b.started) {
+		return a.started.After(b.started)
+	}
+	return false
+}
+
+// mediaRank scores a media type under PreferMedia: 2 for the preferred kind,
+// 1 for the other kind, 0 for photos and unknown types.
+func (p SelectionPolicy) mediaRank(mediaType string) int {
+	var video bool
+	switch mediaType {
+	case MediaTypeMovie, MediaTypeTV, MediaTypeClip, MediaTypeLive:
+		video = true
+	case MediaTypeMusic, MediaTypeAudiobook, MediaTypePodcast:
+		video = false
+	default:
+		return 0
+	}
+	if video == (p.PreferMedia == PreferVideo) {
+		return 2
+	}
+	return 1
+}
+
+// sessionClock tells when each session started its current item, for
+// PreferRecent. Session keys are opaque (Plex reuses them, and companion
+// sessions are keyed by player), so the order sessions started in has to be
+// observed across fetches. Safe for concurrent use.
+type sessionClock struct {
+	mu   sync.Mutex
+	seen map[string]sessionStart // By session key
+}
+
+// sessionStart is when a session was first seen with an item.
+type sessionStart struct {
+	ratingKey string
+	at        time.Time
+}
+
+// stampMedia sets StartedAt on every session fetched at now and forgets the
+// sessions that are gone.
+func (c *sessionClock) stampMedia(sessions []MediaSession, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := make(map[string]sessionStart, len(sessions))
+	for i := range sessions {
+		sessions[i].StartedAt = c.startLocked(current, sessions[i].SessionKey, sessions[i].RatingKey, now)
+	}
+	c.seen = current
+}
+
+// stampMusic is stampMedia for music-only polling.
+func (c *sessionClock) stampMusic(sessions []MusicSession, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := make(map[string]sessionStart, len(sessions))
+	for i := range sessions {
+		sessions[i].StartedAt = c.startLocked(current, sessions[i].SessionKey, sessions[i].RatingKey, now)
+	}
+	c.seen = current
+}
+
+// startLocked returns when the session started ratingKey, recording it in
+// current. A session that moved on to another item starts over at now.
+func (c *sessionClock) startLocked(current map[string]sessionStart, sessionKey, ratingKey string, now time.Time) time.Time {
+	start, ok := c.seen[sessionKey]
+	if !ok || start.ratingKey != ratingKey {
+		start = sessionStart{ratingKey: ratingKey, at: now}
+	}
+	current[sessionKey] = start
+	return start.at
+}
@@ -0,0 +1,119 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSelectionPolicy_SelectMedia(t *testing.T) {
+	start := time.Now()
+	sessions := []MediaSession{
+		{SessionKey: "3", MediaType: MediaTypeMusic, State: "paused", PlayerName: "Plexamp", StartedAt: start},
+		{SessionKey: "5", MediaType: MediaTypeMovie, State: "playing", PlayerName: "Living Room TV", StartedAt: start.Add(time.Minute)},
+		{SessionKey: "7", MediaType: MediaTypeMusic, State: "playing", PlayerName: "Office", StartedAt: start.Add(2 * time.Minute)},
+	}
+
+	tests := []struct {
+		name   string
+		policy SelectionPolicy
+		want   string
+	}{
+		{"zero value keeps server order", SelectionPolicy{}, "3"},
+		{"prefer playing", SelectionPolicy{PreferPlaying: true}, "5"},
+		{"prefer audio", SelectionPolicy{PreferMedia: PreferAudio}, "3"},
+		{"prefer audio and playing", SelectionPolicy{PreferPlaying: true, PreferMedia: PreferAudio}, "7"},
+		{"prefer video", SelectionPolicy{PreferMedia: PreferVideo}, "5"},
+		{"prefer recent", SelectionPolicy{PreferRecent: true}, "7"},
+		{"preferred player wins over playing", SelectionPolicy{PreferredPlayer: "plexamp", PreferPlaying: true}, "3"},
+		{"unknown preferred player falls through", SelectionPolicy{PreferredPlayer: "Phone", PreferRecent: true}, "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := tt.policy.SelectMedia(sessions)
+			if idx < 0 || sessions[idx].SessionKey != tt.want {
+				t.Errorf("SelectMedia() picked index %d, want session %s", idx, tt.want)
+			}
+		})
+	}
+
+	if idx := (SelectionPolicy{}).SelectMedia(nil); idx != -1 {
+		t.Errorf("SelectMedia(nil) = %d, want -1", idx)
+	}
+}
+
+func TestSessionClock(t *testing.T) {
+	var clock sessionClock
+	start := time.Now()
+
+	// Session keys say nothing about age: "12" was there first
+	first := []MediaSession{{SessionKey: "12", RatingKey: "100"}}
+	clock.stampMedia(first, start)
+
+	later := start.Add(time.Minute)
+	second := []MediaSession{
+		{SessionKey: "12", RatingKey: "100"},
+		{SessionKey: "3", RatingKey: "200"},
+	}
+	clock.stampMedia(second, later)
+	if !second[0].StartedAt.Equal(start) || !second[1].StartedAt.Equal(later) {
+		t.Errorf("StartedAt = %v, %v; want %v, %v", second[0].StartedAt, second[1].StartedAt, start, later)
+	}
+	if idx := (SelectionPolicy{PreferRecent: true}).SelectMedia(second); idx != 1 {
+		t.Errorf("PreferRecent picked index %d, want the session that started last", idx)
+	}
+
+	// A session moving on to another item starts over
+	latest := later.Add(time.Minute)
+	third := []MediaSession{
+		{SessionKey: "12", RatingKey: "101"},
+		{SessionKey: "3", RatingKey: "200"},
+	}
+	clock.stampMedia(third, latest)
+	if !third[0].StartedAt.Equal(latest) || !third[1].StartedAt.Equal(later) {
+		t.Errorf("StartedAt = %v, %v; want %v, %v", third[0].StartedAt, third[1].StartedAt, latest, later)
+	}
+
+	// Ended sessions are forgotten: a reused key is a new session
+	clock.stampMedia(nil, latest)
+	again := []MediaSession{{SessionKey: "3", RatingKey: "200"}}
+	clock.stampMedia(again, latest.Add(time.Minute))
+	if !again[0].StartedAt.Equal(latest.Add(time.Minute)) {
+		t.Errorf("expected a reused session key to start over, got %v", again[0].StartedAt)
+	}
+}
+
+func TestPollerMediaSelectionAndSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<MediaContainer size="2">
+  <Track sessionKey="1" type="track" title="Song">
+    <User id="user1" title="User1"/>
+    <Player state="playing" title="Plexamp"/>
+  </Track>
+  <Video sessionKey="2" type="movie" title="Heat">
+    <User id="user1" title="User1"/>
+    <Player state="playing" title="TV"/>
+  </Video>
+</MediaContainer>`))
+	}))
+	defer server.Close()
+
+	poller := NewPoller(NewClient("token", server.URL), "user1", 5*time.Second)
+	poller.SetMediaTypes([]string{MediaTypeMusic, MediaTypeMovie})
+	poller.SetSelectionPolicy(SelectionPolicy{PreferMedia: PreferVideo})
+
+	session, ok := poller.doMediaPoll()
+	if !ok || session == nil {
+		t.Fatal("expected a session")
+	}
+	if session.Title != "Heat" {
+		t.Errorf("expected the movie to be preferred, got %q", session.Title)
+	}
+	if all := poller.Sessions(); len(all) != 2 {
+		t.Errorf("expected both sessions to be visible, got %d", len(all))
+	}
+}
@@ -30,17 +30,42 @@ type transport struct {
 // Authentication is always appended via X-Plex-Token query parameter.
 // The response body is fully read and closed before returning.
 func (t *transport) get(ctx context.Context, path string) ([]byte, error) {
-	reqURL := fmt.Sprintf("%s%s?X-Plex-Token=%s", t.serverURL, path, url.QueryEscape(t.token))
-	return t.doRequest(ctx, "GET", reqURL)
+	return t.getQuery(ctx, path, nil)
+}
+
+// getQuery is get with extra query parameters. The token is added to them.
+func (t *transport) getQuery(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	return t.getHeader(ctx, path, query, nil)
+}
+
+// getHeader is getQuery with extra request headers, e.g. the player a
+// remote control command is proxied to.
+func (t *transport) getHeader(ctx context.Context, path string, query url.Values, header http.Header) ([]byte, error) {
+	return t.request(ctx, "GET", path, query, header)
+}
+
+// request executes a request of any method against path with query
+// parameters and extra headers. The token is added to the parameters.
+func (t *transport) request(ctx context.Context, method, path string, query url.Values, header http.Header) ([]byte, error) {
+	params := url.Values{}
+	for k, v := range query {
+		params[k] = v
+	}
+	params.Set("X-Plex-Token", t.token)
+	reqURL := fmt.Sprintf("%s%s?%s", t.serverURL, path, params.Encode())
+	return t.doRequest(ctx, method, reqURL, header)
 }
 
 // doRequest is the shared HTTP execution path — standard headers, error
 // mapping, body read, and close. All fetch operations go through here.
-func (t *transport) doRequest(ctx context.Context, method, reqURL string) ([]byte, error) {
+func (t *transport) doRequest(ctx context.Context, method, reqURL string, header http.Header) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "failed to create request")
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 
 	req.Header.Set("User-Agent", "PlexCord/1.0")
 	req.Header.Set("Accept", "application/xml")
@@ -80,3 +105,19 @@ func parseSessionsResponse(body []byte) (*SessionsResponse, error) {
 	}
 	return &resp, nil
 }
+
+// parseMetadataResponse deserializes a /library/metadata/{ratingKey} XML
+// payload and returns its first item. Returns a wrapped error on invalid XML
+// or an empty container.
+func parseMetadataResponse(body []byte) (*metadataItem, error) {
+	var resp metadataResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errors.PLEX_CONN_FAILED, "invalid metadata response format")
+	}
+	for _, items := range [][]metadataItem{resp.Tracks, resp.Videos, resp.Directories, resp.Photos} {
+		if len(items) > 0 {
+			return &items[0], nil
+		}
+	}
+	return nil, errors.New(errors.PLEX_CONN_FAILED, "metadata response contains no item")
+}
@@ -3,6 +3,8 @@ package plex
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // Fallback constants for missing metadata (AC1, AC2, AC3, AC7)
@@ -12,6 +14,7 @@ const (
 	FallbackAlbum      = "Unknown Album"
 	FallbackTitle      = "Unknown Title"
 	FallbackShowTitle  = "Unknown Show"
+	FallbackChannel    = "Live TV"
 )
 
 // Media type constants for MediaSession.MediaType
@@ -20,23 +23,82 @@ const (
 	MediaTypeMovie = "movie"
 	MediaTypeTV    = "tv"
 	MediaTypePhoto = "photo"
+	MediaTypeClip  = "clip" // Music videos and other clips
+	MediaTypeLive  = "live" // Live TV through a Plex tuner (any program type)
+
+	// Audio classified by where it comes from rather than its Plex type:
+	// audiobooks are tracks in an audiobook library, podcasts are episodes
+	// from Plex's podcast provider or items in a podcast library.
+	MediaTypeAudiobook = "audiobook"
+	MediaTypePodcast   = "podcast"
 )
 
-// Server represents a discovered Plex Media Server
+// Server represents a discovered Plex Media Server.
+// Servers found through GDM only carry Address/Port; servers returned by the
+// plex.tv resources API also carry their connection list and access token.
 type Server struct {
-	ID      string `json:"id"`      // Unique resource identifier
-	Name    string `json:"name"`    // Server display name
-	Address string `json:"address"` // IP address
-	Port    string `json:"port"`    // Port (typically 32400)
-	Version string `json:"version"` // Server version (optional)
-	IsLocal bool   `json:"isLocal"` // True if on local network
+	ID          string             `json:"id"`                    // Unique resource identifier (machine identifier)
+	Name        string             `json:"name"`                  // Server display name
+	Address     string             `json:"address"`               // IP address
+	Port        string             `json:"port"`                  // Port (typically 32400)
+	Version     string             `json:"version"`               // Server version (optional)
+	AccessToken string             `json:"accessToken,omitempty"` // Token for this server (resources API only)
+	Owner       string             `json:"owner,omitempty"`       // Owner's username for shared servers
+	Connections []ServerConnection `json:"connections,omitempty"` // Advertised URIs (resources API only)
+	IsLocal     bool               `json:"isLocal"`               // True if on local network
+	Owned       bool               `json:"owned"`                 // False for servers shared by a friend
 }
 
-// URL returns the full server URL
+// URL returns the full server URL. For servers with advertised connections
+// the preferred connection's URI is used; otherwise it is built from
+// Address and Port.
 func (s *Server) URL() string {
+	if conn := s.preferredConnection(); conn != nil && conn.URI != "" {
+		return conn.URI
+	}
 	return fmt.Sprintf("http://%s:%s", s.Address, s.Port)
 }
 
+// ConnectionURIs returns the URIs of every advertised connection in
+// preference order (local, then remote, then relay).
+func (s *Server) ConnectionURIs() []string {
+	uris := make([]string, 0, len(s.Connections))
+	for _, rank := range []int{0, 1, 2} {
+		for _, c := range s.Connections {
+			if c.URI != "" && connectionRank(c) == rank {
+				uris = append(uris, c.URI)
+			}
+		}
+	}
+	return uris
+}
+
+// preferredConnection returns the best advertised connection, or nil when
+// the server has none: a direct LAN connection beats a direct remote one,
+// and the plex.tv relay is only used as a last resort.
+func (s *Server) preferredConnection() *ServerConnection {
+	var best *ServerConnection
+	for i := range s.Connections {
+		c := &s.Connections[i]
+		if best == nil || connectionRank(*c) < connectionRank(*best) {
+			best = c
+		}
+	}
+	return best
+}
+
+// connectionRank orders connections by preference (lower is better).
+func connectionRank(c ServerConnection) int {
+	switch {
+	case c.Relay:
+		return 2
+	case c.Local:
+		return 0
+	default:
+		return 1
+	}
+}
+
 // ValidationResult represents the outcome of validating a Plex connection
 type ValidationResult struct {
 	ServerName        string `json:"serverName"`        // Plex server friendly name
@@ -96,16 +158,32 @@ func (sr *SessionsResponse) AllEntries() []SessionEntry {
 //   - Track:   GrandparentTitle=Artist, ParentTitle=Album, Title=TrackName
 //   - Episode: GrandparentTitle=ShowName, ParentTitle=SeasonName, Title=EpisodeName
 //   - Movie:   Title=MovieName, Year=ReleaseYear
-//   - Photo:   Title=PhotoName
+//   - Photo:   Title=PhotoName, ParentTitle=PhotoAlbum
+//   - Clip:    Title=VideoName, GrandparentTitle=Artist (music videos)
 type SessionEntry struct {
 	// Nested elements
-	User   SessionUser   `xml:"User"`
-	Player SessionPlayer `xml:"Player"`
+	User   SessionUser    `xml:"User"`
+	Player SessionPlayer  `xml:"Player"`
+	Media  []SessionMedia `xml:"Media"`
+
+	// Transcode is present while the server transcodes the stream
+	Transcode *TranscodeSession `xml:"TranscodeSession"`
 
 	// Core session identifiers
 	SessionKey string `xml:"sessionKey,attr"`
 	Key        string `xml:"key,attr"`
-	Type       string `xml:"type,attr"` // "track", "episode", "movie", "photo"
+	RatingKey  string `xml:"ratingKey,attr"` // Library item ID
+	Type       string `xml:"type,attr"`      // "track", "episode", "movie", "photo", "clip"
+
+	// Origin of the item, used to classify audiobooks and podcasts
+	LibrarySectionID string `xml:"librarySectionID,attr"` // Library section the item belongs to
+	GUID             string `xml:"guid,attr"`             // e.g. "plex://podcast/..." for provider podcasts
+	Source           string `xml:"source,attr"`           // e.g. "provider://tv.plex.provider.podcasts"
+
+	// Public identity of the item, or of the episode's show, for links
+	GrandparentGUID string `xml:"grandparentGuid,attr"`
+	Slug            string `xml:"slug,attr"`            // watch.plex.tv page name, e.g. "the-matrix"
+	GrandparentSlug string `xml:"grandparentSlug,attr"` // Show's page name (episodes)
 
 	// Common metadata
 	Title            string `xml:"title,attr"`            // Track/episode/movie title
@@ -115,10 +193,71 @@ type SessionEntry struct {
 	Duration         int64  `xml:"duration,attr"`         // Duration in milliseconds
 	ViewOffset       int64  `xml:"viewOffset,attr"`       // Current position in milliseconds
 
+	// UserRating is the user's rating out of 10 (absent when unrated)
+	UserRating float64 `xml:"userRating,attr"`
+
 	// Video-specific metadata
 	Year        int `xml:"year,attr"`        // Release year (movies, episodes)
 	ParentIndex int `xml:"parentIndex,attr"` // Season number (TV episodes)
 	Index       int `xml:"index,attr"`       // Episode number (TV) or track number (music)
+
+	// Live is "1" for Live TV sessions, whose duration is the program's
+	// rather than a fixed item length
+	Live string `xml:"live,attr"`
+
+	// Play queue the item is played from. Sessions only carry the item's ID
+	// in the queue; the queue ID comes from notifications or timelines.
+	PlayQueueID     string `xml:"playQueueID,attr"`
+	PlayQueueItemID string `xml:"playQueueItemID,attr"`
+}
+
+// IsLive reports whether the entry is a Live TV session.
+func (e *SessionEntry) IsLive() bool {
+	return e.Live == "1"
+}
+
+// IsPodcast reports whether the entry comes from Plex's podcast provider.
+func (e *SessionEntry) IsPodcast() bool {
+	return strings.HasPrefix(e.GUID, "plex://podcast") ||
+		strings.Contains(e.Source, "tv.plex.provider.podcasts")
+}
+
+// SessionMedia is a media version of the playing item. Live TV sessions carry
+// the tuned channel on it.
+type SessionMedia struct {
+	Parts []SessionPart `xml:"Part"`
+
+	// Source format (see StreamQuality for what's actually streamed)
+	AudioCodec      string `xml:"audioCodec,attr"`      // e.g. "flac", "aac"
+	VideoCodec      string `xml:"videoCodec,attr"`      // e.g. "h264", "hevc"
+	Bitrate         int    `xml:"bitrate,attr"`         // kbps
+	VideoResolution string `xml:"videoResolution,attr"` // e.g. "1080", "4k", "sd"
+	Container       string `xml:"container,attr"`       // e.g. "flac", "mkv"
+
+	ChannelTitle      string `xml:"channelTitle,attr"`      // Channel name (e.g. "BBC One")
+	ChannelCallSign   string `xml:"channelCallSign,attr"`   // Call sign (e.g. "WABC")
+	ChannelIdentifier string `xml:"channelIdentifier,attr"` // Channel number (e.g. "7.1")
+	ChannelVcn        string `xml:"channelVcn,attr"`        // Virtual channel number, when it differs
+}
+
+// channel returns the tuned channel's display name and number, preferring
+// the channel title over the call sign and the identifier over the VCN.
+func (e *SessionEntry) channel() (name, number string) {
+	for _, m := range e.Media {
+		if name == "" {
+			name = m.ChannelTitle
+			if name == "" {
+				name = m.ChannelCallSign
+			}
+		}
+		if number == "" {
+			number = m.ChannelIdentifier
+			if number == "" {
+				number = m.ChannelVcn
+			}
+		}
+	}
+	return name, number
 }
 
 // SessionUser represents the user associated with a session
@@ -133,11 +272,15 @@ type SessionPlayer struct {
 	State   string `xml:"state,attr"`   // "playing", "paused", "stopped"
 	Title   string `xml:"title,attr"`   // Player name (e.g., "Chrome")
 	Product string `xml:"product,attr"` // Product name (e.g., "Plex Web")
+
+	MachineIdentifier string `xml:"machineIdentifier,attr"` // Player's client identifier
 }
 
 // Session represents a parsed Plex playback session
 type Session struct {
 	SessionKey string `json:"sessionKey"` // Unique session identifier
+	RatingKey  string `json:"ratingKey"`  // Library item ID
+	Key        string `json:"key"`        // Library item path (e.g. /library/metadata/123)
 	UserID     string `json:"userId"`     // User ID for this session
 	UserName   string `json:"userName"`   // User display name
 	Type       string `json:"type"`       // Media type: "track", "episode", "movie", "photo"
@@ -155,6 +298,35 @@ type MusicSession struct {
 	ThumbURL   string `json:"thumbUrl"`   // Absolute album artwork URL (includes server URL and token)
 	Duration   int64  `json:"duration"`   // Track duration in milliseconds
 	ViewOffset int64  `json:"viewOffset"` // Current playback position in milliseconds
+
+	// UserRating is the user's rating out of 10, 0 when unrated
+	UserRating float64 `json:"userRating,omitempty"`
+
+	// Album position. TrackCount needs metadata enrichment.
+	TrackNumber int `json:"trackNumber,omitempty"`
+	TrackCount  int `json:"trackCount,omitempty"`
+
+	// Play queue the track is played from, and what plays after it
+	// (attached by a PlayQueueResolver)
+	PlayQueueID     string          `json:"playQueueId,omitempty"`
+	PlayQueueItemID string          `json:"playQueueItemId,omitempty"`
+	UpNext          []PlayQueueItem `json:"upNext,omitempty"`
+
+	// Quality is the format being streamed and how the server delivers it.
+	Quality StreamQuality `json:"quality"`
+
+	// ObservedAt is when ViewOffset was reported, and Seeked marks an update
+	// emitted only because the position jumped (see DefaultSeekTolerance).
+	ObservedAt time.Time `json:"-"`
+	Seeked     bool      `json:"seeked,omitempty"`
+
+	// StartedAt is when the session was first seen playing its current
+	// item, for SelectionPolicy.PreferRecent (see sessionClock).
+	StartedAt time.Time `json:"-"`
+
+	// Metadata is the item's full library metadata, attached by a
+	// MetadataEnricher. Nil when enrichment is disabled or failed.
+	Metadata *ItemMetadata `json:"metadata,omitempty"`
 }
 
 // ApplyFallbacks replaces empty metadata fields with appropriate fallback values.
@@ -177,6 +349,8 @@ func (m *MusicSession) ApplyFallbacks() {
 // (music, movies, TV episodes, photos). This is the unified session type for multi-media support.
 type MediaSession struct {
 	SessionKey string `json:"sessionKey"`
+	RatingKey  string `json:"ratingKey"` // Library item ID
+	Key        string `json:"key"`       // Library item path (e.g. /library/metadata/123)
 	Type       string `json:"type"`      // Plex type: "track", "movie", "episode", "photo"
 	MediaType  string `json:"mediaType"` // Simplified: "music", "movie", "tv", "photo"
 	State      string `json:"state"`     // "playing", "paused", "stopped"
@@ -189,26 +363,77 @@ type MediaSession struct {
 	ViewOffset int64  `json:"viewOffset"` // Current playback position in milliseconds
 	Year       int    `json:"year"`       // Release year
 
-	// Music-specific
-	Artist string `json:"artist"` // Artist name (music only)
-	Album  string `json:"album"`  // Album name (music only)
+	// UserRating is the user's rating out of 10 (MaxRating), 0 when unrated
+	UserRating float64 `json:"userRating,omitempty"`
+
+	// Music-specific (audiobooks: Artist holds the author, Album the book
+	// and TrackNumber the chapter)
+	Artist      string `json:"artist"`                // Artist name (music only)
+	Album       string `json:"album"`                 // Album name (music only)
+	TrackNumber int    `json:"trackNumber,omitempty"` // Position on the album
+	TrackCount  int    `json:"trackCount,omitempty"`  // Tracks on the album; needs metadata enrichment
 
 	// TV-specific
 	ShowTitle string `json:"showTitle"` // Show name (TV episodes only)
 	Season    int    `json:"season"`    // Season number (TV episodes only)
 	Episode   int    `json:"episode"`   // Episode number (TV episodes only)
 
+	// Photo-specific (Album holds the photo album's name)
+	PhotoCount int `json:"photoCount,omitempty"` // Photos in the album; needs metadata enrichment
+
+	// Live TV-specific. Title is the airing item's title (episode or movie)
+	// and ProgramTitle the program's (show name, or the movie title).
+	ChannelName   string `json:"channelName,omitempty"`
+	ChannelNumber string `json:"channelNumber,omitempty"`
+	ProgramTitle  string `json:"programTitle,omitempty"`
+
+	// LibrarySectionID is the library the item belongs to ("" for provider
+	// items and Live TV)
+	LibrarySectionID string `json:"librarySectionId,omitempty"`
+
+	// Identity of the item beyond its library, for links (see links.go):
+	// the server it plays from, the agent GUID ("plex://movie/...") and the
+	// watch.plex.tv slug. Episodes also carry their show's.
+	ServerID string `json:"serverId,omitempty"` // Server's machine identifier
+	GUID     string `json:"guid,omitempty"`
+	Slug     string `json:"slug,omitempty"`
+	ShowGUID string `json:"showGuid,omitempty"`
+	ShowSlug string `json:"showSlug,omitempty"`
+
 	// Session context
 	UserID     string `json:"userId"`
 	UserName   string `json:"userName"`
 	PlayerName string `json:"playerName"`
+	PlayerID   string `json:"playerId,omitempty"` // Player's machine identifier, for remote control
+
+	// Play queue the item is played from, and what plays after it
+	// (attached by a PlayQueueResolver)
+	PlayQueueID     string          `json:"playQueueId,omitempty"`
+	PlayQueueItemID string          `json:"playQueueItemId,omitempty"`
+	UpNext          []PlayQueueItem `json:"upNext,omitempty"`
+
+	// Quality is the format being streamed and how the server delivers it.
+	Quality StreamQuality `json:"quality"`
+
+	// ObservedAt is when ViewOffset was reported, and Seeked marks an update
+	// emitted only because the position jumped (see DefaultSeekTolerance).
+	ObservedAt time.Time `json:"-"`
+	Seeked     bool      `json:"seeked,omitempty"`
+
+	// StartedAt is when the session was first seen playing its current
+	// item, for SelectionPolicy.PreferRecent (see sessionClock).
+	StartedAt time.Time `json:"-"`
+
+	// Metadata is the item's full library metadata, attached by a
+	// MetadataEnricher. Nil when enrichment is disabled or failed.
+	Metadata *ItemMetadata `json:"metadata,omitempty"`
 }
 
 // ApplyFallbacks replaces empty metadata fields with appropriate fallback values
 // based on the media type.
 func (m *MediaSession) ApplyFallbacks() {
 	switch m.MediaType {
-	case MediaTypeMusic:
+	case MediaTypeMusic, MediaTypeAudiobook:
 		if m.Title == "" {
 			m.Title = FallbackTrackTitle
 		}
@@ -218,17 +443,27 @@ func (m *MediaSession) ApplyFallbacks() {
 		if m.Album == "" {
 			m.Album = FallbackAlbum
 		}
-	case MediaTypeTV:
+	case MediaTypeTV, MediaTypePodcast:
 		if m.Title == "" {
 			m.Title = FallbackTitle
 		}
 		if m.ShowTitle == "" {
 			m.ShowTitle = FallbackShowTitle
 		}
-	case MediaTypeMovie, MediaTypePhoto:
+	case MediaTypeMovie, MediaTypePhoto, MediaTypeClip:
+		if m.Title == "" {
+			m.Title = FallbackTitle
+		}
+	case MediaTypeLive:
 		if m.Title == "" {
 			m.Title = FallbackTitle
 		}
+		if m.ProgramTitle == "" {
+			m.ProgramTitle = m.Title
+		}
+		if m.ChannelName == "" {
+			m.ChannelName = FallbackChannel
+		}
 	}
 }
 
@@ -243,17 +478,31 @@ func mediaTypeFromPlexType(plexType string) string {
 		return MediaTypeTV
 	case "photo":
 		return MediaTypePhoto
+	case "clip":
+		return MediaTypeClip
 	default:
 		return plexType
 	}
 }
 
 // NewMediaSessionFromEntry creates a MediaSession from a SessionEntry and absolute thumb URL.
+// Live TV sessions get MediaTypeLive whatever the airing item's type, and no
+// duration: the program's length isn't the length of what's being watched.
+// Provider podcasts get MediaTypePodcast; audiobooks and library podcasts
+// need their library's type, see LibraryClassifier.
 func NewMediaSessionFromEntry(entry SessionEntry, thumbURL string) MediaSession {
 	mediaType := mediaTypeFromPlexType(entry.Type)
+	switch {
+	case entry.IsLive():
+		mediaType = MediaTypeLive
+	case entry.IsPodcast():
+		mediaType = MediaTypePodcast
+	}
 
 	ms := MediaSession{
 		SessionKey: entry.SessionKey,
+		RatingKey:  entry.RatingKey,
+		Key:        entry.Key,
 		Type:       entry.Type,
 		MediaType:  mediaType,
 		State:      entry.Player.State,
@@ -263,9 +512,18 @@ func NewMediaSessionFromEntry(entry SessionEntry, thumbURL string) MediaSession
 		Duration:   entry.Duration,
 		ViewOffset: entry.ViewOffset,
 		Year:       entry.Year,
+		UserRating: entry.UserRating,
 		UserID:     entry.User.ID,
 		UserName:   entry.User.Title,
 		PlayerName: entry.Player.Title,
+		PlayerID:   entry.Player.MachineIdentifier,
+
+		LibrarySectionID: entry.LibrarySectionID,
+		GUID:             entry.GUID,
+		Slug:             entry.Slug,
+		PlayQueueID:      entry.PlayQueueID,
+		PlayQueueItemID:  entry.PlayQueueItemID,
+		Quality:          entry.quality(),
 	}
 
 	// Populate type-specific fields based on Plex's metadata hierarchy
@@ -273,10 +531,33 @@ func NewMediaSessionFromEntry(entry SessionEntry, thumbURL string) MediaSession
 	case MediaTypeMusic:
 		ms.Artist = entry.GrandparentTitle
 		ms.Album = entry.ParentTitle
+		ms.TrackNumber = entry.Index
+	case MediaTypePodcast:
+		// Podcasts have no seasons: the show is the episode's parent
+		ms.ShowTitle = entry.GrandparentTitle
+		if ms.ShowTitle == "" {
+			ms.ShowTitle = entry.ParentTitle
+		}
 	case MediaTypeTV:
+		ms.ShowTitle = entry.GrandparentTitle
+		ms.ShowGUID = entry.GrandparentGUID
+		ms.ShowSlug = entry.GrandparentSlug
+		ms.Season = entry.ParentIndex
+		ms.Episode = entry.Index
+	case MediaTypePhoto:
+		ms.Album = entry.ParentTitle
+	case MediaTypeClip:
+		ms.Artist = entry.GrandparentTitle
+	case MediaTypeLive:
+		ms.Duration = 0
 		ms.ShowTitle = entry.GrandparentTitle
 		ms.Season = entry.ParentIndex
 		ms.Episode = entry.Index
+		ms.ProgramTitle = entry.GrandparentTitle
+		if ms.ProgramTitle == "" {
+			ms.ProgramTitle = entry.Title
+		}
+		ms.ChannelName, ms.ChannelNumber = entry.channel()
 	}
 
 	return ms